@@ -0,0 +1,325 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package tide ties the terminal backend, render engine and widget tree
+// together into a runnable application.
+package tide
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+	"github.com/watzon/tide/pkg/widget"
+)
+
+// Config holds application configuration
+type Config struct {
+	// Terminal configures the terminal backend. Nil uses terminal.DefaultConfig.
+	Terminal *terminal.Config
+
+	// Screen overrides the tcell screen the terminal draws to. Nil creates
+	// a screen for the controlling terminal.
+	Screen tcell.Screen
+
+	// HandleSignals stops the app cleanly on SIGINT and SIGTERM
+	HandleSignals bool
+
+	// ExitOnCtrlC stops the app when Ctrl+C is pressed
+	ExitOnCtrlC bool
+
+	// MaxFPS caps how often frames are produced. Rebuild requests made
+	// between frames are batched into the next one. Zero means no cap.
+	MaxFPS int
+}
+
+// DefaultConfig returns the default application configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Terminal:      terminal.DefaultConfig(),
+		HandleSignals: true,
+		ExitOnCtrlC:   true,
+		MaxFPS:        60,
+	}
+}
+
+// App owns the terminal and the root element and drives the frame loop
+type App struct {
+	config     *Config
+	rootWidget widget.Widget
+	root       widget.Element
+	owner      *widget.BuildOwner
+	term       *terminal.Terminal
+	ctx        *engine.TerminalContext
+
+	onEvent func(terminal.Event) bool
+	// hover tells render objects when the pointer enters and leaves them
+	hover widget.HoverTracker
+
+	events   chan terminal.Event
+	frames   chan struct{}
+	quit     chan struct{}
+	stopOnce sync.Once
+
+	// repaint forces a paint of the whole tree on the next frame even when
+	// no render object changed
+	repaint   atomic.Bool
+	lastFrame time.Time
+	// painted is the root render object the last frame painted
+	painted widget.RenderObject
+}
+
+// NewApp creates an application for the given root widget using the default configuration
+func NewApp(root widget.Widget) *App {
+	return NewAppWithConfig(root, DefaultConfig())
+}
+
+// NewAppWithConfig creates an application for the given root widget
+func NewAppWithConfig(root widget.Widget, config *Config) *App {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	a := &App{
+		config:     config,
+		rootWidget: root,
+		owner:      widget.NewBuildOwner(),
+		events:     make(chan terminal.Event),
+		frames:     make(chan struct{}, 1),
+		quit:       make(chan struct{}),
+	}
+	a.owner.OnBuildScheduled(a.scheduleFrame)
+	return a
+}
+
+// OnEvent registers a handler that sees every terminal event before the app
+// does. Returning true stops the app.
+func (a *App) OnEvent(handler func(terminal.Event) bool) {
+	a.onEvent = handler
+}
+
+// Terminal returns the terminal backing the app, or nil before Run
+func (a *App) Terminal() *terminal.Terminal {
+	return a.term
+}
+
+// Root returns the root element, or nil before Run
+func (a *App) Root() widget.Element {
+	return a.root
+}
+
+// Run initializes the terminal, mounts the root widget and runs the frame
+// loop until the app is stopped. The terminal is always restored on return.
+func (a *App) Run() error {
+	if err := a.init(); err != nil {
+		return err
+	}
+	defer a.shutdown()
+
+	return a.loop()
+}
+
+// Stop ends the frame loop. It is safe to call from any goroutine.
+func (a *App) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.quit)
+	})
+}
+
+// Owner returns the build owner scheduling rebuilds of the tree
+func (a *App) Owner() *widget.BuildOwner {
+	return a.owner
+}
+
+// Post runs fn on the app's UI goroutine before the next frame is built.
+// Widgets, states and render objects must only be touched there, so
+// background goroutines use Post to update state. Safe for concurrent use.
+func (a *App) Post(fn func()) {
+	a.owner.Post(fn)
+}
+
+// RequestFrame schedules a frame that paints the whole tree, even if no
+// render object changed. Multiple requests made before the frame runs are
+// coalesced.
+func (a *App) RequestFrame() {
+	a.repaint.Store(true)
+	a.scheduleFrame()
+}
+
+// scheduleFrame wakes the frame loop, which only lays out and paints what
+// changed
+func (a *App) scheduleFrame() {
+	select {
+	case a.frames <- struct{}{}:
+	default:
+	}
+}
+
+// frameInterval returns the minimum time between two frames
+func (a *App) frameInterval() time.Duration {
+	if a.config.MaxFPS <= 0 {
+		return 0
+	}
+	return time.Second / time.Duration(a.config.MaxFPS)
+}
+
+func (a *App) init() error {
+	termConfig := a.config.Terminal
+	if termConfig == nil {
+		termConfig = terminal.DefaultConfig()
+	}
+
+	var err error
+	if a.config.Screen != nil {
+		a.term, err = terminal.NewWithScreen(a.config.Screen, termConfig)
+	} else {
+		a.term, err = terminal.NewWithConfig(termConfig)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize terminal: %w", err)
+	}
+
+	a.term.HideCursor()
+	a.term.Clear()
+	a.ctx = engine.NewTerminalContext(a.term)
+	a.owner.SetRenderContext(a.ctx)
+	a.owner.SetClipboard(a.term)
+
+	a.root = widget.NewElement(a.rootWidget)
+	a.owner.MountRoot(a.root)
+	return nil
+}
+
+func (a *App) shutdown() {
+	// Release the event forwarder before tearing down the terminal
+	a.Stop()
+	if a.root != nil {
+		a.root.Unmount()
+	}
+	a.term.Shutdown()
+}
+
+func (a *App) loop() error {
+	go a.term.HandleEvents(a.forwardEvent)
+
+	var signals chan os.Signal
+	if a.config.HandleSignals {
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+	}
+
+	// frameTimer is armed when a frame is pending and fires once the
+	// frame interval has passed since the previous frame
+	var frameTimer <-chan time.Time
+
+	a.RequestFrame()
+	for {
+		select {
+		case <-a.quit:
+			return nil
+		case <-signals:
+			return nil
+		case ev := <-a.events:
+			if a.handleEvent(ev) {
+				return nil
+			}
+			a.scheduleFrame()
+		case <-a.frames:
+			if frameTimer == nil {
+				delay := a.frameInterval() - time.Since(a.lastFrame)
+				frameTimer = time.After(max(delay, 0))
+			}
+		case <-frameTimer:
+			frameTimer = nil
+			a.lastFrame = time.Now()
+			if err := a.drawFrame(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// forwardEvent hands terminal events to the frame loop goroutine
+func (a *App) forwardEvent(ev terminal.Event) bool {
+	select {
+	case a.events <- ev:
+		return false
+	case <-a.quit:
+		return true
+	}
+}
+
+// handleEvent processes a single event and reports whether the app should stop
+func (a *App) handleEvent(ev terminal.Event) bool {
+	if _, ok := ev.(terminal.ResizeEvent); ok {
+		// The clip rect of the render context is sized to the terminal,
+		// so it is recreated whenever the terminal changes size
+		a.ctx = engine.NewTerminalContext(a.term)
+		a.owner.SetRenderContext(a.ctx)
+		a.RequestFrame()
+	}
+	if mouse, ok := ev.(terminal.MouseEvent); ok && a.root != nil {
+		a.hover.Update(a.root.RenderObject(), mouse.Position)
+	}
+	if a.onEvent != nil && a.onEvent(ev) {
+		return true
+	}
+	if a.root != nil && a.owner.FocusManager().DispatchEvent(a.root.RenderObject(), ev) {
+		return false
+	}
+	if key, ok := ev.(terminal.KeyEvent); ok {
+		return a.handleKey(key)
+	}
+	return false
+}
+
+// handleKey handles keys no widget consumed: Tab and Shift+Tab move focus,
+// and Ctrl+C may stop the app
+func (a *App) handleKey(key terminal.KeyEvent) bool {
+	switch key.Key {
+	case tcell.KeyTab:
+		a.owner.FocusManager().NextFocus()
+	case tcell.KeyBacktab:
+		a.owner.FocusManager().PreviousFocus()
+	case tcell.KeyCtrlC:
+		return a.config.ExitOnCtrlC
+	}
+	return false
+}
+
+// drawFrame runs posted tasks and rebuilds dirty elements, then lays out
+// the render objects that were marked as needing layout against the
+// terminal size. If anything changed, or a repaint was requested, the
+// tree is painted and the result presented. Painting always covers the
+// whole tree; the terminal only redraws the cells that differ.
+func (a *App) drawFrame() error {
+	a.owner.FlushPosted()
+	a.owner.BuildScope()
+
+	repaint := a.repaint.Swap(false)
+	renderObject := a.root.RenderObject()
+	if renderObject == nil {
+		return nil
+	}
+
+	size := a.term.Size()
+	widget.LayoutIfNeeded(renderObject, widget.NewConstraints(geometry.Size{}, size))
+	if !repaint && renderObject == a.painted && !widget.NeedsPaint(renderObject) {
+		return nil
+	}
+
+	a.ctx.Clear()
+	widget.PaintRoot(a.ctx, renderObject)
+	a.painted = renderObject
+	return a.ctx.Present()
+}
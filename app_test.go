@@ -0,0 +1,378 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package tide
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/engine"
+	"github.com/watzon/tide/pkg/widget"
+)
+
+// readyWidget builds its child and closes ready the first time it is built,
+// which happens after the terminal has been initialized
+type readyWidget struct {
+	widget.BaseWidget
+	child widget.Widget
+	ready chan struct{}
+	once  sync.Once
+}
+
+func (w *readyWidget) Build(context widget.BuildContext) widget.Widget {
+	w.once.Do(func() { close(w.ready) })
+	return w.child
+}
+
+type testApp struct {
+	app    *App
+	screen tcell.SimulationScreen
+	ready  chan struct{}
+	done   chan error
+}
+
+func newTestApp(root widget.Widget) *testApp {
+	screen := tcell.NewSimulationScreen("")
+	config := DefaultConfig()
+	config.Screen = screen
+	config.HandleSignals = false
+
+	ready := make(chan struct{})
+	return &testApp{
+		app:    NewAppWithConfig(&readyWidget{child: root, ready: ready}, config),
+		screen: screen,
+		ready:  ready,
+		done:   make(chan error, 1),
+	}
+}
+
+// start runs the app and waits until the screen is safe to inspect
+func (ta *testApp) start(t *testing.T) *testApp {
+	go func() {
+		ta.done <- ta.app.Run()
+	}()
+	select {
+	case <-ta.ready:
+	case err := <-ta.done:
+		t.Fatalf("app exited early: %v", err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("app did not start")
+	}
+	return ta
+}
+
+func startTestApp(t *testing.T, root widget.Widget) *testApp {
+	return newTestApp(root).start(t)
+}
+
+// screenText returns the first row of the simulation screen as a string.
+// The screen's cells are only stable between frames, so they are read on
+// the app's UI goroutine.
+func (ta *testApp) screenText() string {
+	result := make(chan string, 1)
+	ta.app.Post(func() {
+		cells, width, _ := ta.screen.GetContents()
+		var sb strings.Builder
+		for x := 0; x < width && x < len(cells); x++ {
+			if len(cells[x].Runes) > 0 {
+				sb.WriteRune(cells[x].Runes[0])
+			}
+		}
+		result <- sb.String()
+	})
+
+	select {
+	case text := <-result:
+		return text
+	case <-time.After(time.Second):
+		return ""
+	}
+}
+
+func (ta *testApp) waitForText(t *testing.T, text string) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if strings.Contains(ta.screenText(), text) {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %q, screen shows %q", text, ta.screenText())
+}
+
+func (ta *testApp) waitForExit(t *testing.T) error {
+	select {
+	case err := <-ta.done:
+		return err
+	case <-time.After(2 * time.Second):
+		t.Fatal("app did not stop")
+		return nil
+	}
+}
+
+func TestApp_RunPaintsRoot(t *testing.T) {
+	ta := startTestApp(t, widget.NewText("Hello, Tide"))
+
+	ta.waitForText(t, "Hello, Tide")
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+func TestApp_ExitOnCtrlC(t *testing.T) {
+	ta := startTestApp(t, widget.NewText("ctrl-c"))
+	ta.waitForText(t, "ctrl-c")
+
+	ta.screen.InjectKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+func TestApp_OnEvent(t *testing.T) {
+	received := make(chan terminal.KeyEvent, 1)
+
+	ta := newTestApp(widget.NewText("events"))
+	ta.app.OnEvent(func(ev terminal.Event) bool {
+		if key, ok := ev.(terminal.KeyEvent); ok {
+			received <- key
+			return true
+		}
+		return false
+	})
+	ta.start(t)
+	ta.waitForText(t, "events")
+
+	ta.screen.InjectKey(tcell.KeyRune, 'q', tcell.ModNone)
+	select {
+	case key := <-received:
+		assert.Equal(t, 'q', key.Rune)
+	case <-time.After(2 * time.Second):
+		t.Fatal("event handler was not called")
+	}
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+func TestApp_Resize(t *testing.T) {
+	ta := startTestApp(t, widget.NewText("resize"))
+	ta.waitForText(t, "resize")
+
+	ta.screen.SetSize(40, 10)
+	ta.screen.PostEvent(tcell.NewEventResize(40, 10))
+
+	// The tree is repainted against the new size
+	ta.waitForText(t, "resize")
+	_, width, height := ta.screen.GetContents()
+	assert.Equal(t, 40, width)
+	assert.Equal(t, 10, height)
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+// tickerWidget displays a counter that background goroutines increment
+type tickerWidget struct {
+	widget.BaseWidget
+	state *tickerState
+}
+
+func (w *tickerWidget) CreateState() widget.State {
+	w.state = &tickerState{}
+	return w.state
+}
+
+type tickerState struct {
+	widget.BaseState
+	count int
+}
+
+func (s *tickerState) Build(context widget.BuildContext) widget.Widget {
+	return widget.NewText(fmt.Sprintf("count=%d", s.count))
+}
+
+func TestApp_Post(t *testing.T) {
+	ticker := &tickerWidget{}
+	ta := startTestApp(t, ticker)
+	ta.waitForText(t, "count=0")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				ta.app.Post(func() {
+					ticker.state.SetState(func() { ticker.state.count++ })
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	ta.waitForText(t, "count=500")
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+// contextProbe reports the render context its build context exposes
+type contextProbe struct {
+	widget.BaseWidget
+	found chan engine.RenderContext
+}
+
+func (w *contextProbe) Build(context widget.BuildContext) widget.Widget {
+	select {
+	case w.found <- context.RenderContext():
+	default:
+	}
+	return widget.NewText("probe")
+}
+
+func TestApp_BuildContextRenderContext(t *testing.T) {
+	probe := &contextProbe{found: make(chan engine.RenderContext, 1)}
+	ta := startTestApp(t, probe)
+	ta.waitForText(t, "probe")
+
+	renderCtx := <-probe.found
+	assert.NotNil(t, renderCtx)
+	assert.Equal(t, ta.app.Terminal().Size(), renderCtx.Size())
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+func TestApp_TabFocusesTextInput(t *testing.T) {
+	ta := startTestApp(t, widget.NewTextInput().WithLabel("name:"))
+	ta.waitForText(t, "name:")
+
+	ta.screen.InjectKey(tcell.KeyRune, 'x', tcell.ModNone)
+	ta.screen.InjectKey(tcell.KeyTab, 0, tcell.ModNone)
+	ta.screen.InjectKey(tcell.KeyRune, 'h', tcell.ModNone)
+	ta.screen.InjectKey(tcell.KeyRune, 'i', tcell.ModNone)
+	ta.waitForText(t, "name: hi")
+
+	// With nothing selected the input leaves Ctrl+C to the app
+	ta.screen.InjectKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+// paintCounter is a leaf widget whose render object counts its paints
+type paintCounter struct {
+	widget.BaseWidget
+	paints int
+}
+
+func (w *paintCounter) Build(context widget.BuildContext) widget.Widget {
+	return w
+}
+
+func (w *paintCounter) CreateRenderObject() widget.RenderObject {
+	return &countingPaint{counter: w}
+}
+
+func (w *paintCounter) UpdateRenderObject(renderObject widget.RenderObject) {}
+
+type countingPaint struct {
+	widget.BaseRenderObject
+	counter *paintCounter
+}
+
+func (r *countingPaint) Paint(context engine.RenderContext) {
+	r.counter.paints++
+}
+
+// paints reads the paint count of w once the frames already scheduled have
+// run, on the app's UI goroutine
+func (ta *testApp) paints(w *paintCounter) int {
+	result := make(chan int, 1)
+	// Posted tasks run before the frame is drawn, so the second one runs
+	// after the frame that followed the first
+	ta.app.Post(func() {
+		ta.app.Post(func() { result <- w.paints })
+	})
+	select {
+	case paints := <-result:
+		return paints
+	case <-time.After(time.Second):
+		return -1
+	}
+}
+
+func TestApp_UnconsumedEventsDontRepaint(t *testing.T) {
+	counter := &paintCounter{}
+	received := make(chan terminal.MouseEvent, 1)
+	ta := newTestApp(counter)
+	ta.app.config.Terminal = terminal.DefaultConfig()
+	ta.app.config.Terminal.MouseMode = terminal.MouseMotion
+	ta.app.OnEvent(func(ev terminal.Event) bool {
+		if mouse, ok := ev.(terminal.MouseEvent); ok {
+			received <- mouse
+		}
+		return false
+	})
+	ta.start(t)
+
+	painted := ta.paints(counter)
+	assert.Positive(t, painted)
+
+	ta.screen.InjectMouse(4, 2, tcell.ButtonNone, tcell.ModNone)
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("mouse event was not delivered")
+	}
+	assert.Equal(t, painted, ta.paints(counter))
+
+	// A requested frame still paints everything
+	ta.app.RequestFrame()
+	assert.Equal(t, painted+1, ta.paints(counter))
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
+
+// controllerOffset reads the offset of c on the app's UI goroutine
+func (ta *testApp) controllerOffset(c *widget.ScrollController) int {
+	result := make(chan int, 1)
+	ta.app.Post(func() { result <- c.Offset() })
+	select {
+	case offset := <-result:
+		return offset
+	case <-time.After(time.Second):
+		return -1
+	}
+}
+
+func TestApp_WheelTicksScrollController(t *testing.T) {
+	lines := make([]widget.Widget, 100)
+	for i := range lines {
+		lines[i] = widget.NewText(fmt.Sprintf("line%d", i))
+	}
+	controller := widget.NewScrollController()
+	ta := startTestApp(t, widget.NewSingleChildScrollView(widget.NewColumn(lines...)).WithController(controller))
+	ta.waitForText(t, "line0")
+
+	// Each tick scrolls three lines, and none of them may be merged away
+	const down, up = 7, 2
+	for i := 0; i < down; i++ {
+		ta.screen.InjectMouse(2, 3, tcell.WheelDown, tcell.ModNone)
+	}
+	for i := 0; i < up; i++ {
+		ta.screen.InjectMouse(2, 3, tcell.WheelUp, tcell.ModNone)
+	}
+
+	want := (down - up) * 3
+	ta.waitForText(t, fmt.Sprintf("line%d", want))
+	assert.Equal(t, want, ta.controllerOffset(controller))
+
+	ta.app.Stop()
+	assert.NoError(t, ta.waitForExit(t))
+}
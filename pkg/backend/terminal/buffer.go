@@ -4,16 +4,20 @@ import (
 	"sync"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/mattn/go-runewidth"
 	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
 )
 
-// Cell represents a single character cell in the buffer
+// Cell represents a single character cell in the buffer. A character wider
+// than one cell is kept in its first cell, followed by continuation cells
+// that hold nothing of their own.
 type Cell struct {
 	Rune      rune
 	Style     tcell.Style
 	Combining []rune
 	Width     int
+	// Continuation marks a cell covered by the wide character before it
+	Continuation bool
 }
 
 // Buffer represents a screen buffer
@@ -33,21 +37,66 @@ func NewBuffer(size geometry.Size) *Buffer {
 	}
 }
 
-// SetCell sets a cell in the buffer
+// SetCell sets a cell in the buffer. A wide character also takes the
+// cells after it, and what is left of wide characters it partly covers is
+// blanked.
 func (b *Buffer) SetCell(x, y int, ch rune, combining []rune, style tcell.Style) {
 	b.lock.Lock()
 	defer b.lock.Unlock()
 
-	pos := geometry.Point{X: x, Y: y}
-	b.cells[pos] = Cell{
+	cell := Cell{
 		Rune:      ch,
 		Style:     style,
 		Combining: combining,
-		Width:     runewidth.RuneWidth(ch),
+		Width:     grapheme.Width(string(ch) + string(combining)),
+	}
+	b.clearOverlap(x, x+cell.Width, y)
+	b.cells[geometry.Point{X: x, Y: y}] = cell
+	for i := 1; i < cell.Width; i++ {
+		b.cells[geometry.Point{X: x + i, Y: y}] = Cell{Style: style, Continuation: true}
 	}
 	b.dirty = true
 }
 
+// SetCluster sets the cell at x, y to a grapheme cluster: its first rune
+// and the runes joined to it
+func (b *Buffer) SetCluster(x, y int, cluster string, style tcell.Style) {
+	runes := []rune(cluster)
+	if len(runes) == 0 {
+		return
+	}
+	var combining []rune
+	if len(runes) > 1 {
+		combining = runes[1:]
+	}
+	b.SetCell(x, y, runes[0], combining, style)
+}
+
+// clearOverlap blanks the cells of wide characters that the cells from x
+// up to end cover only part of, so no half of one is left behind. The
+// caller must hold the lock.
+func (b *Buffer) clearOverlap(x, end, y int) {
+	if b.cells[geometry.Point{X: x, Y: y}].Continuation {
+		for i := x - 1; ; i-- {
+			cell, ok := b.cells[geometry.Point{X: i, Y: y}]
+			if !ok {
+				break
+			}
+			b.cells[geometry.Point{X: i, Y: y}] = Cell{Rune: ' ', Style: cell.Style, Width: 1}
+			if !cell.Continuation {
+				break
+			}
+		}
+	}
+	for i := end; ; i++ {
+		cell := b.cells[geometry.Point{X: i, Y: y}]
+		if !cell.Continuation {
+			break
+		}
+		b.cells[geometry.Point{X: i, Y: y}] = Cell{Rune: ' ', Style: cell.Style, Width: 1}
+	}
+}
+
 // GetCell gets a cell from the buffer
 func (b *Buffer) GetCell(x, y int) (Cell, bool) {
 	b.lock.RLock()
@@ -168,3 +168,41 @@ func TestBufferCopyFrom(t *testing.T) {
 		t.Error("modifying source buffer should not affect destination")
 	}
 }
+
+func TestBufferWideCells(t *testing.T) {
+	style := tcell.StyleDefault
+
+	t.Run("wide clusters take a continuation cell", func(t *testing.T) {
+		buf := terminal.NewBuffer(geometry.Size{Width: 10, Height: 1})
+		buf.SetCluster(0, 0, "👨‍👩‍👧", style)
+
+		lead, _ := buf.GetCell(0, 0)
+		if lead.Rune != '👨' || string(lead.Combining) != "‍👩‍👧" || lead.Width != 2 {
+			t.Errorf("unexpected lead cell %+v", lead)
+		}
+		next, ok := buf.GetCell(1, 0)
+		if !ok || !next.Continuation || next.Width != 0 {
+			t.Errorf("expected a continuation cell, got %+v", next)
+		}
+	})
+
+	t.Run("covering half a wide character blanks the rest", func(t *testing.T) {
+		buf := terminal.NewBuffer(geometry.Size{Width: 10, Height: 1})
+		buf.SetCell(0, 0, '日', nil, style)
+		buf.SetCell(2, 0, '本', nil, style)
+		buf.SetCell(1, 0, 'a', nil, style)
+		buf.SetCell(2, 0, 'b', nil, style)
+
+		var got []rune
+		for x := 0; x < 4; x++ {
+			cell, _ := buf.GetCell(x, 0)
+			if cell.Continuation {
+				t.Errorf("cell %d is still a continuation", x)
+			}
+			got = append(got, cell.Rune)
+		}
+		if string(got) != " ab " {
+			t.Errorf("got %q, want %q", string(got), " ab ")
+		}
+	})
+}
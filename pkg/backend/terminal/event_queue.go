@@ -0,0 +1,115 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package terminal
+
+import "sync"
+
+// DefaultEventQueueSize is the number of pending events after which mouse
+// motion events start being dropped
+const DefaultEventQueueSize = 256
+
+// eventQueue is an ordered, unbounded queue of terminal events with a soft
+// limit. Consecutive resize events and consecutive motion events with the
+// same buttons are merged, and once the limit is reached the oldest motion
+// events are dropped to make room. Key, button, wheel and focus events are
+// never dropped.
+type eventQueue struct {
+	lock    sync.Mutex
+	events  []Event
+	limit   int
+	dropped uint64
+	notify  chan struct{}
+}
+
+func newEventQueue(limit int) *eventQueue {
+	if limit <= 0 {
+		limit = DefaultEventQueueSize
+	}
+	return &eventQueue{
+		limit:  limit,
+		notify: make(chan struct{}, 1),
+	}
+}
+
+// push appends an event and wakes a waiting consumer. It never blocks.
+func (q *eventQueue) push(ev Event) {
+	q.lock.Lock()
+	if n := len(q.events); n > 0 && coalesces(q.events[n-1], ev) {
+		q.events[n-1] = ev
+	} else {
+		if len(q.events) >= q.limit {
+			q.dropOldestMotion()
+		}
+		q.events = append(q.events, ev)
+	}
+	q.lock.Unlock()
+
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// pop removes the oldest event. It reports false when the queue is empty.
+func (q *eventQueue) pop() (Event, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if len(q.events) == 0 {
+		return nil, false
+	}
+	ev := q.events[0]
+	q.events[0] = nil
+	q.events = q.events[1:]
+	return ev, true
+}
+
+// pending returns the number of queued events
+func (q *eventQueue) pending() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return len(q.events)
+}
+
+// droppedCount returns how many events were dropped because the queue was full
+func (q *eventQueue) droppedCount() uint64 {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.dropped
+}
+
+// dropOldestMotion removes the oldest motion event, if there is one
+func (q *eventQueue) dropOldestMotion() {
+	for i, ev := range q.events {
+		if isMotion(ev) {
+			q.events = append(q.events[:i], q.events[i+1:]...)
+			q.dropped++
+			return
+		}
+	}
+}
+
+// coalesces reports whether next supersedes prev when queued right after it
+func coalesces(prev, next Event) bool {
+	switch next := next.(type) {
+	case ResizeEvent:
+		_, ok := prev.(ResizeEvent)
+		return ok
+	case MouseEvent:
+		prevMouse, ok := prev.(MouseEvent)
+		return ok && isMotion(next) && isMotion(prevMouse) && prevMouse.Buttons == next.Buttons
+	}
+	return false
+}
+
+// isMotion reports whether ev is pointer motion. Wheel ticks each scroll,
+// so they never count as motion.
+func isMotion(ev Event) bool {
+	mouse, ok := ev.(MouseEvent)
+	return ok && mouse.Motion && mouse.Buttons&wheelButtons == 0
+}
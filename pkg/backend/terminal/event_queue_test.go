@@ -0,0 +1,227 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package terminal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+func motionAt(x int, buttons tcell.ButtonMask) MouseEvent {
+	return MouseEvent{Buttons: buttons, Position: geometry.Point{X: x}, Motion: true}
+}
+
+func drain(q *eventQueue) []Event {
+	var events []Event
+	for {
+		ev, ok := q.pop()
+		if !ok {
+			return events
+		}
+		events = append(events, ev)
+	}
+}
+
+func TestEventQueueOrder(t *testing.T) {
+	q := newEventQueue(0)
+	for r := 'a'; r <= 'z'; r++ {
+		q.push(KeyEvent{Key: tcell.KeyRune, Rune: r})
+	}
+
+	events := drain(q)
+	if len(events) != 26 {
+		t.Fatalf("expected 26 events, got %d", len(events))
+	}
+	for i, ev := range events {
+		if key := ev.(KeyEvent); key.Rune != 'a'+rune(i) {
+			t.Errorf("event %d: expected %q, got %q", i, 'a'+rune(i), key.Rune)
+		}
+	}
+}
+
+func TestEventQueueCoalescing(t *testing.T) {
+	tests := []struct {
+		name     string
+		events   []Event
+		expected []Event
+	}{
+		{
+			name: "consecutive resizes",
+			events: []Event{
+				ResizeEvent{Size: geometry.Size{Width: 10, Height: 5}},
+				ResizeEvent{Size: geometry.Size{Width: 20, Height: 5}},
+				ResizeEvent{Size: geometry.Size{Width: 30, Height: 5}},
+			},
+			expected: []Event{
+				ResizeEvent{Size: geometry.Size{Width: 30, Height: 5}},
+			},
+		},
+		{
+			name: "consecutive motion",
+			events: []Event{
+				motionAt(1, tcell.ButtonNone),
+				motionAt(2, tcell.ButtonNone),
+				motionAt(3, tcell.ButtonNone),
+			},
+			expected: []Event{
+				motionAt(3, tcell.ButtonNone),
+			},
+		},
+		{
+			name: "motion with different buttons",
+			events: []Event{
+				motionAt(1, tcell.ButtonNone),
+				motionAt(2, tcell.ButtonPrimary),
+			},
+			expected: []Event{
+				motionAt(1, tcell.ButtonNone),
+				motionAt(2, tcell.ButtonPrimary),
+			},
+		},
+		{
+			name: "separated by a key",
+			events: []Event{
+				ResizeEvent{Size: geometry.Size{Width: 10}},
+				KeyEvent{Key: tcell.KeyEnter},
+				ResizeEvent{Size: geometry.Size{Width: 20}},
+			},
+			expected: []Event{
+				ResizeEvent{Size: geometry.Size{Width: 10}},
+				KeyEvent{Key: tcell.KeyEnter},
+				ResizeEvent{Size: geometry.Size{Width: 20}},
+			},
+		},
+		{
+			name: "button presses are kept",
+			events: []Event{
+				MouseEvent{Buttons: tcell.ButtonPrimary},
+				MouseEvent{Buttons: tcell.ButtonPrimary},
+			},
+			expected: []Event{
+				MouseEvent{Buttons: tcell.ButtonPrimary},
+				MouseEvent{Buttons: tcell.ButtonPrimary},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newEventQueue(0)
+			for _, ev := range tt.events {
+				q.push(ev)
+			}
+
+			got := drain(q)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d events, got %d: %v", len(tt.expected), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Errorf("event %d: expected %v, got %v", i, tt.expected[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventQueueBackPressure(t *testing.T) {
+	q := newEventQueue(4)
+
+	// Alternate motion and keys so nothing coalesces
+	for i := 0; i < 4; i++ {
+		q.push(motionAt(i, tcell.ButtonNone))
+		q.push(KeyEvent{Key: tcell.KeyRune, Rune: 'a' + rune(i)})
+	}
+
+	var keys []rune
+	motion := 0
+	for _, ev := range drain(q) {
+		switch ev := ev.(type) {
+		case KeyEvent:
+			keys = append(keys, ev.Rune)
+		case MouseEvent:
+			motion++
+		}
+	}
+
+	if string(keys) != "abcd" {
+		t.Errorf("expected every key in order, got %q", string(keys))
+	}
+	if motion >= 4 {
+		t.Errorf("expected motion events to be dropped, got %d", motion)
+	}
+	if q.droppedCount() != uint64(4-motion) {
+		t.Errorf("expected %d dropped events, got %d", 4-motion, q.droppedCount())
+	}
+}
+
+func TestEventQueueKeysNeverDropped(t *testing.T) {
+	q := newEventQueue(2)
+	for i := 0; i < 10; i++ {
+		q.push(KeyEvent{Key: tcell.KeyRune, Rune: '0' + rune(i)})
+	}
+
+	if q.pending() != 10 {
+		t.Errorf("expected 10 pending keys, got %d", q.pending())
+	}
+	if q.droppedCount() != 0 {
+		t.Errorf("expected no dropped events, got %d", q.droppedCount())
+	}
+}
+
+func TestEventQueueWheelTicksNeverMerged(t *testing.T) {
+	const ticks = 20
+	q := newEventQueue(4)
+	for i := 0; i < ticks; i++ {
+		// Even a tick flagged as motion is a scroll of its own
+		q.push(MouseEvent{Buttons: tcell.WheelDown, Motion: i > 0})
+	}
+
+	if got := len(drain(q)); got != ticks {
+		t.Errorf("expected %d wheel events, got %d", ticks, got)
+	}
+	if q.droppedCount() != 0 {
+		t.Errorf("expected no dropped events, got %d", q.droppedCount())
+	}
+}
+
+func TestTerminalDeliversEveryWheelTick(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("failed to initialize screen: %v", err)
+	}
+	term, err := NewWithScreen(screen, DefaultConfig())
+	if err != nil {
+		t.Fatalf("failed to create terminal: %v", err)
+	}
+	defer term.Shutdown()
+
+	const ticks = 5
+	received := make(chan MouseEvent, ticks)
+	go term.HandleEvents(func(ev Event) bool {
+		if mouse, ok := ev.(MouseEvent); ok {
+			received <- mouse
+		}
+		return false
+	})
+	for i := 0; i < ticks; i++ {
+		screen.InjectMouse(2, 3, tcell.WheelDown, tcell.ModNone)
+	}
+
+	for i := 0; i < ticks; i++ {
+		select {
+		case ev := <-received:
+			if ev.Motion {
+				t.Errorf("tick %d was flagged as motion", i)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d of %d wheel ticks", i, ticks)
+		}
+	}
+}
@@ -21,10 +21,26 @@ type KeyEvent struct {
 }
 
 type MouseEvent struct {
-	Buttons   tcell.ButtonMask
-	Position  geometry.Point
+	Buttons  tcell.ButtonMask
+	Position geometry.Point
+	// Motion is true when the pointer moved without any button changing state
+	Motion    bool
 	timestamp time.Time
 }
 
-func (e KeyEvent) When() time.Time   { return e.timestamp }
-func (e MouseEvent) When() time.Time { return e.timestamp }
+// ResizeEvent reports the new size of the terminal
+type ResizeEvent struct {
+	Size      geometry.Size
+	timestamp time.Time
+}
+
+// FocusEvent reports the terminal gaining or losing focus
+type FocusEvent struct {
+	Focused   bool
+	timestamp time.Time
+}
+
+func (e KeyEvent) When() time.Time    { return e.timestamp }
+func (e MouseEvent) When() time.Time  { return e.timestamp }
+func (e ResizeEvent) When() time.Time { return e.timestamp }
+func (e FocusEvent) When() time.Time  { return e.timestamp }
@@ -14,10 +14,10 @@ import (
 	"unicode"
 
 	"github.com/gdamore/tcell/v2"
-	"github.com/mattn/go-runewidth"
 	"github.com/watzon/tide/internal/utils"
 	"github.com/watzon/tide/pkg/core/color"
 	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
 	"github.com/watzon/tide/pkg/core/style"
 )
 
@@ -62,9 +62,14 @@ type Terminal struct {
 	focused   bool
 	suspended bool
 	lock      sync.RWMutex
-	eventChan chan Event
+	events    *eventQueue
 	stopChan  chan struct{}
 
+	// Event reader
+	captureEvents bool
+	readerDone    chan struct{}
+	lastButtons   tcell.ButtonMask
+
 	// Callbacks
 	onResize      func(geometry.Size)
 	onFocusChange func(bool)
@@ -89,22 +94,29 @@ type Config struct {
 	EnableMouse   bool
 	MouseMode     MouseMode
 	ColorMode     tcell.Color
-	PollInterval  time.Duration
 	HandleSuspend bool
 	HandleResize  bool
 	CaptureEvents bool
+
+	// Deprecated: events are read as soon as they arrive; PollInterval is ignored.
+	PollInterval time.Duration
+
+	// EventQueueSize is the number of pending events after which the oldest
+	// mouse motion events are dropped. Other events are never dropped.
+	// Zero uses DefaultEventQueueSize.
+	EventQueueSize int
 }
 
 // DefaultConfig returns the default terminal configuration
 func DefaultConfig() *Config {
 	return &Config{
-		EnableMouse:   true,
-		MouseMode:     MouseClick,
-		ColorMode:     tcell.ColorDefault,
-		PollInterval:  time.Millisecond * 50,
-		HandleSuspend: true,
-		HandleResize:  true,
-		CaptureEvents: true,
+		EnableMouse:    true,
+		MouseMode:      MouseClick,
+		ColorMode:      tcell.ColorDefault,
+		HandleSuspend:  true,
+		HandleResize:   true,
+		CaptureEvents:  true,
+		EventQueueSize: DefaultEventQueueSize,
 	}
 }
 
@@ -140,7 +152,8 @@ func NewWithScreen(screen tcell.Screen, config *Config) (*Terminal, error) {
 		style:           tcell.StyleDefault,
 		size:            size,
 		mouseMode:       config.MouseMode,
-		eventChan:       make(chan Event, 100),
+		events:          newEventQueue(config.EventQueueSize),
+		captureEvents:   config.CaptureEvents,
 		stopChan:        make(chan struct{}),
 		combiningChars:  true,
 		mainFrontBuffer: NewBuffer(size),
@@ -155,7 +168,7 @@ func NewWithScreen(screen tcell.Screen, config *Config) (*Terminal, error) {
 	}
 
 	if config.CaptureEvents {
-		go t.eventLoop(config.PollInterval)
+		t.startEventLoop()
 	}
 
 	if t.SupportsUnicode() {
@@ -189,7 +202,33 @@ func (t *Terminal) Suspend() error {
 	return nil
 }
 
+// Resume initializes the screen again after Suspend. It does nothing when
+// the terminal isn't suspended.
 func (t *Terminal) Resume() error {
+	t.lock.Lock()
+	suspended := t.suspended
+	t.lock.Unlock()
+	if !suspended {
+		return nil
+	}
+
+	// The reader stops when the screen is finalized on suspend and must be
+	// gone before the screen is initialized again
+	if t.captureEvents {
+		<-t.readerDone
+	}
+
+	if err := t.resumeScreen(); err != nil {
+		return err
+	}
+
+	if t.captureEvents {
+		t.startEventLoop()
+	}
+	return nil
+}
+
+func (t *Terminal) resumeScreen() error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
@@ -209,7 +248,16 @@ func (t *Terminal) Sync() {
 
 // Drawing operations
 
+// Clear clears the screen and the back buffer so the next frame starts empty
 func (t *Terminal) Clear() {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if t.usingAltScreen {
+		t.altBackBuffer.Clear()
+	} else {
+		t.mainBackBuffer.Clear()
+	}
 	t.screen.Clear()
 }
 
@@ -247,19 +295,8 @@ func (t *Terminal) DrawStyledCell(x, y int, ch rune, fg, bg color.Color, style S
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 
-	// Get the current back buffer based on screen mode
-	backBuffer := t.mainBackBuffer
-	if t.usingAltScreen {
-		backBuffer = t.altBackBuffer
-	}
-
-	// Create base style with colors
-	tcellStyle := tcell.StyleDefault.
-		Foreground(t.optimizeColor(fg)).
-		Background(t.optimizeColor(bg))
-
-	// Apply style attributes
-	tcellStyle = t.applyStyleMask(tcellStyle, style)
+	backBuffer := t.backBuffer()
+	tcellStyle := t.cellStyle(fg, bg, style)
 
 	// Handle disabled combining characters
 	if !t.combiningChars && unicode.IsMark(ch) {
@@ -269,9 +306,18 @@ func (t *Terminal) DrawStyledCell(x, y int, ch rune, fg, bg color.Color, style S
 
 	// Handle combining characters when enabled
 	if t.unicodeMode && t.combiningChars && unicode.IsMark(ch) {
-		if prevCell, exists := backBuffer.GetCell(x-1, y); exists && prevCell.Rune != ' ' {
+		// A mark after a wide character joins the cell the character
+		// starts in
+		lead := x - 1
+		for {
+			if cell, _ := backBuffer.GetCell(lead, y); !cell.Continuation {
+				break
+			}
+			lead--
+		}
+		if prevCell, exists := backBuffer.GetCell(lead, y); exists && prevCell.Rune != ' ' {
 			combining := append(prevCell.Combining, ch)
-			backBuffer.SetCell(x-1, y, prevCell.Rune, combining, tcellStyle)
+			backBuffer.SetCell(lead, y, prevCell.Rune, combining, tcellStyle)
 			return
 		}
 	}
@@ -280,6 +326,15 @@ func (t *Terminal) DrawStyledCell(x, y int, ch rune, fg, bg color.Color, style S
 	backBuffer.SetCell(x, y, ch, nil, tcellStyle)
 }
 
+// cellStyle returns the tcell style of a cell drawn in fg and bg with the
+// attributes of style
+func (t *Terminal) cellStyle(fg, bg color.Color, style StyleMask) tcell.Style {
+	base := tcell.StyleDefault.
+		Foreground(t.optimizeColor(fg)).
+		Background(t.optimizeColor(bg))
+	return t.applyStyleMask(base, style)
+}
+
 func (t *Terminal) DrawRegion(region geometry.Rect, style tcell.Style, ch rune) {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
@@ -291,18 +346,39 @@ func (t *Terminal) DrawRegion(region geometry.Rect, style tcell.Style, ch rune)
 	}
 }
 
-// DrawText draws a string of text, handling combining characters appropriately
+// DrawText draws a line of text from x, y. With unicode and combining
+// characters on, each grapheme cluster is drawn into one cell, followed by
+// continuation cells when it is wide; otherwise each rune takes a cell.
 func (t *Terminal) DrawText(x, y int, text string, fg, bg color.Color, style StyleMask) {
-	currentX := x
-	for _, ch := range text {
-		t.DrawStyledCell(currentX, y, ch, fg, bg, style)
-		if !t.combiningChars || !unicode.IsMark(ch) {
-			currentX++
+	t.lock.RLock()
+	unicodeMode, combiningChars := t.unicodeMode, t.combiningChars
+	t.lock.RUnlock()
+
+	if !unicodeMode || !combiningChars {
+		for _, ch := range text {
+			t.DrawStyledCell(x, y, ch, fg, bg, style)
+			if !combiningChars || !unicode.IsMark(ch) {
+				x++
+			}
 		}
+		return
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	backBuffer := t.backBuffer()
+	tcellStyle := t.cellStyle(fg, bg, style)
+	for _, cluster := range grapheme.Clusters(text) {
+		backBuffer.SetCluster(x, y, cluster.Text, tcellStyle)
+		x += cluster.Width
 	}
 }
 
+// StringWidth returns the cells text takes when drawn with DrawText
 func (t *Terminal) StringWidth(s string) int {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
 	if !t.unicodeMode {
 		return len(s)
 	}
@@ -312,8 +388,7 @@ func (t *Terminal) StringWidth(s string) int {
 		return len([]rune(s))
 	}
 
-	// Use runewidth for normal Unicode width calculation
-	return runewidth.StringWidth(s)
+	return grapheme.Width(s)
 }
 
 func (t *Terminal) Present() error {
@@ -353,6 +428,10 @@ func (t *Terminal) Present() error {
 			}
 
 			if backExists {
+				if backCell.Continuation {
+					// The wide character before it already covers this cell
+					continue
+				}
 				if !t.combiningChars && unicode.IsMark(backCell.Rune) {
 					t.screen.SetContent(x, y, '\u25CC', []rune{backCell.Rune}, backCell.Style)
 				} else {
@@ -382,18 +461,29 @@ func (t *Terminal) Size() geometry.Size {
 	return geometry.Size{Width: width, Height: height}
 }
 
+// SetCursor places the cursor in the current back buffer. It shows there
+// when the buffer is presented; a negative position hides it.
 func (t *Terminal) SetCursor(x, y int) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
-	t.mainBackBuffer.SetCursor(x, y)
+	t.backBuffer().SetCursor(x, y)
 }
 
 func (t *Terminal) GetCursor() geometry.Point {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
 
-	return t.mainBackBuffer.GetCursor()
+	return t.backBuffer().GetCursor()
+}
+
+// backBuffer returns the back buffer of the screen in use. The caller must
+// hold the lock.
+func (t *Terminal) backBuffer() *Buffer {
+	if t.usingAltScreen {
+		return t.altBackBuffer
+	}
+	return t.mainBackBuffer
 }
 
 func (t *Terminal) HideCursor() {
@@ -445,78 +535,105 @@ func (t *Terminal) SetMouseMode(mode MouseMode) {
 
 // Event handling
 
-func (t *Terminal) eventLoop(pollInterval time.Duration) {
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+// startEventLoop starts the goroutine that reads events from the screen
+func (t *Terminal) startEventLoop() {
+	t.readerDone = make(chan struct{})
+	go t.eventLoop(t.readerDone)
+}
+
+// eventLoop reads events one at a time, in order, until the screen is
+// finalized. It never holds the terminal lock while queueing events or
+// invoking callbacks, so it cannot stall drawing.
+func (t *Terminal) eventLoop(done chan struct{}) {
+	defer close(done)
 
 	for {
-		select {
-		case <-t.stopChan:
+		ev := t.screen.PollEvent()
+		if ev == nil {
 			return
-		case <-ticker.C:
-			ev := t.screen.PollEvent()
-			if ev == nil {
-				continue
-			}
+		}
 
-			go func(ev tcell.Event) {
-				t.lock.Lock()
-				defer t.lock.Unlock()
-
-				switch ev := ev.(type) {
-				case *tcell.EventResize:
-					width, height := ev.Size()
-					t.size = geometry.Size{Width: width, Height: height}
-					t.screen.Sync()
-					if t.onResize != nil {
-						t.onResize(t.size)
-					}
-				case *tcell.EventMouse:
-					t.handleMouse(ev)
-				case *tcell.EventKey:
-					t.handleKey(ev)
-				case *tcell.EventFocus:
-					t.focused = ev.Focused
-					if t.onFocusChange != nil {
-						t.onFocusChange(t.focused)
-					}
-				}
-			}(ev)
+		switch ev := ev.(type) {
+		case *tcell.EventResize:
+			t.handleResize(ev)
+		case *tcell.EventMouse:
+			t.handleMouse(ev)
+		case *tcell.EventKey:
+			t.handleKey(ev)
+		case *tcell.EventFocus:
+			t.handleFocus(ev)
 		}
 	}
 }
 
-func (t *Terminal) handleMouse(ev *tcell.EventMouse) {
-	// Skip if mouse events are disabled
-	if t.mouseMode == MouseDisabled {
-		return
+func (t *Terminal) handleResize(ev *tcell.EventResize) {
+	width, height := ev.Size()
+	size := geometry.Size{Width: width, Height: height}
+
+	t.lock.Lock()
+	t.size = size
+	callback := t.onResize
+	t.lock.Unlock()
+
+	t.screen.Sync()
+	t.events.push(ResizeEvent{Size: size, timestamp: ev.When()})
+	if callback != nil {
+		callback(size)
 	}
+}
+
+func (t *Terminal) handleFocus(ev *tcell.EventFocus) {
+	t.lock.Lock()
+	t.focused = ev.Focused
+	callback := t.onFocusChange
+	t.lock.Unlock()
+
+	// tcell focus events carry no timestamp
+	t.events.push(FocusEvent{Focused: ev.Focused, timestamp: time.Now()})
+	if callback != nil {
+		callback(ev.Focused)
+	}
+}
+
+// wheelButtons are the buttons tcell reports for mouse wheel scrolls
+const wheelButtons = tcell.WheelUp | tcell.WheelDown | tcell.WheelLeft | tcell.WheelRight
+
+func (t *Terminal) handleMouse(ev *tcell.EventMouse) {
+	t.lock.RLock()
+	mode := t.mouseMode
+	t.lock.RUnlock()
 
 	x, y := ev.Position()
 	buttons := ev.Buttons()
 
+	// lastButtons is only touched by the reader goroutine. Each wheel tick
+	// is a scroll of its own, never motion, however many come in a row.
+	motion := buttons == t.lastButtons && buttons&wheelButtons == 0
+	t.lastButtons = buttons
+
 	// Create mouse event
 	event := MouseEvent{
 		Buttons:   buttons,
 		Position:  geometry.Point{X: x, Y: y},
+		Motion:    motion,
 		timestamp: ev.When(),
 	}
 
 	// Handle based on mouse mode
-	switch t.mouseMode {
+	switch mode {
 	case MouseClick:
-		// Only send button press events (Primary, Secondary, Middle)
-		if buttons&(tcell.ButtonPrimary|tcell.ButtonSecondary|tcell.ButtonMiddle) != 0 {
-			t.eventChan <- event
+		// Only send button presses and wheel scrolls
+		if buttons&(tcell.ButtonPrimary|tcell.ButtonSecondary|tcell.ButtonMiddle|wheelButtons) != 0 {
+			t.events.push(event)
 		}
 	case MouseDrag:
 		// Send button events and drag events
 		if buttons != tcell.ButtonNone {
-			t.eventChan <- event
+			t.events.push(event)
 		}
 	case MouseMotion:
 		// Send all mouse events
-		t.eventChan <- event
+		t.events.push(event)
 	}
 }
 
@@ -529,8 +646,13 @@ func (t *Terminal) handleKey(ev *tcell.EventKey) {
 		timestamp: ev.When(),
 	}
 
-	// Send the event through the channel
-	t.eventChan <- event
+	t.events.push(event)
+}
+
+// DroppedEvents returns how many mouse motion events were dropped because
+// the event queue was full
+func (t *Terminal) DroppedEvents() uint64 {
+	return t.events.droppedCount()
 }
 
 // Clipboard operations
@@ -575,11 +697,21 @@ func (t *Terminal) GetClipboard() (string, error) {
 
 // Callbacks
 
+// OnResize registers a callback invoked on the event reader goroutine
+// whenever the terminal is resized
 func (t *Terminal) OnResize(callback func(geometry.Size)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	t.onResize = callback
 }
 
+// OnFocusChange registers a callback invoked on the event reader goroutine
+// whenever the terminal gains or loses focus
 func (t *Terminal) OnFocusChange(callback func(bool)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
 	t.onFocusChange = callback
 }
 
@@ -591,16 +723,23 @@ func (t *Terminal) OnResume(callback func()) {
 	t.onResume = callback
 }
 
-// Add this new method
+// HandleEvents delivers queued events to handler in the order they were
+// received until handler returns true or the terminal is shut down. Only
+// one goroutine should handle events at a time.
 func (t *Terminal) HandleEvents(handler func(Event) bool) {
 	for {
-		select {
-		case <-t.stopChan:
-			return
-		case event := <-t.eventChan:
-			if handler(event) {
+		event, ok := t.events.pop()
+		if !ok {
+			select {
+			case <-t.stopChan:
 				return
+			case <-t.events.notify:
 			}
+			continue
+		}
+
+		if handler(event) {
+			return
 		}
 	}
 }
@@ -683,22 +822,33 @@ func (t *Terminal) ExitAltScreen() error {
 	return nil
 }
 
-func (t *Terminal) DrawBorder(rect geometry.Rect, s style.Style) {
-	// Draw corners
-	t.DrawStyledCell(rect.Min.X, rect.Min.Y, '┌', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-	t.DrawStyledCell(rect.Max.X-1, rect.Min.Y, '┐', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-	t.DrawStyledCell(rect.Min.X, rect.Max.Y-1, '└', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-	t.DrawStyledCell(rect.Max.X-1, rect.Max.Y-1, '┘', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
+// DrawBorder draws a border along the edges of rect, falling back to ASCII
+// characters when Unicode is disabled
+func (t *Terminal) DrawBorder(rect geometry.Rect, border style.Border) {
+	t.lock.RLock()
+	unicodeMode := t.unicodeMode
+	t.lock.RUnlock()
 
-	// Draw horizontal borders
-	for x := rect.Min.X + 1; x < rect.Max.X-1; x++ {
-		t.DrawStyledCell(x, rect.Min.Y, '─', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-		t.DrawStyledCell(x, rect.Max.Y-1, '─', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-	}
+	mask := styleMask(border.Style)
+	border.Walk(rect, unicodeMode, func(x, y int, ch rune) {
+		t.DrawStyledCell(x, y, ch, border.ForegroundColor, border.BackgroundColor, mask)
+	})
+}
 
-	// Draw vertical borders
-	for y := rect.Min.Y + 1; y < rect.Max.Y-1; y++ {
-		t.DrawStyledCell(rect.Min.X, y, '│', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
-		t.DrawStyledCell(rect.Max.X-1, y, '│', s.ForegroundColor, s.BackgroundColor, StyleMask(0))
+// styleMask returns the attributes of a style as a StyleMask
+func styleMask(s style.Style) StyleMask {
+	var mask StyleMask
+	if s.Bold {
+		mask |= StyleBold
+	}
+	if s.Italic {
+		mask |= StyleItalic
+	}
+	if s.Underline {
+		mask |= StyleUnderline
+	}
+	if s.StrikeThrough {
+		mask |= StyleStrikethrough
 	}
+	return mask
 }
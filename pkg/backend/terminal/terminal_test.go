@@ -16,6 +16,7 @@ import (
 	"github.com/watzon/tide/pkg/backend/terminal"
 	"github.com/watzon/tide/pkg/core/color"
 	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/style"
 )
 
 type testContext struct {
@@ -111,6 +112,68 @@ func TestTerminalFocus(t *testing.T) {
 	}
 }
 
+func TestTerminalEventOrder(t *testing.T) {
+	ctx := setupTest(t)
+	defer ctx.term.Shutdown()
+
+	const count = 200
+	received := make(chan rune, count)
+	go ctx.term.HandleEvents(func(ev terminal.Event) bool {
+		if key, ok := ev.(terminal.KeyEvent); ok {
+			received <- key.Rune
+		}
+		return false
+	})
+
+	// Inject from another goroutine while drawing, like a real app would
+	go func() {
+		for i := 0; i < count; i++ {
+			ctx.screen.(tcell.SimulationScreen).InjectKey(tcell.KeyRune, rune('0'+i%10), tcell.ModNone)
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		ctx.term.DrawCell(0, 0, 'X', color.Color{}, color.Color{})
+		ctx.term.Present()
+	}
+
+	for i := 0; i < count; i++ {
+		select {
+		case r := <-received:
+			if expected := rune('0' + i%10); r != expected {
+				t.Fatalf("event %d: expected %q, got %q", i, expected, r)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out after %d events", i)
+		}
+	}
+}
+
+func TestTerminalResizeEvent(t *testing.T) {
+	ctx := setupTest(t)
+	defer ctx.term.Shutdown()
+
+	received := make(chan terminal.ResizeEvent, 1)
+	go ctx.term.HandleEvents(func(ev terminal.Event) bool {
+		if resize, ok := ev.(terminal.ResizeEvent); ok && resize.Size.Width == 100 {
+			received <- resize
+			return true
+		}
+		return false
+	})
+
+	ctx.screen.SetSize(100, 50)
+	ctx.screen.PostEvent(tcell.NewEventResize(100, 50))
+
+	select {
+	case ev := <-received:
+		if ev.Size.Height != 50 {
+			t.Errorf("expected height 50, got %d", ev.Size.Height)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resize event was not delivered")
+	}
+}
+
 func TestTerminalDrawing(t *testing.T) {
 	ctx := setupTest(t)
 	defer ctx.term.Shutdown()
@@ -265,6 +328,37 @@ func TestTerminalSuspendResume(t *testing.T) {
 	}
 }
 
+func TestTerminalResumeWithoutSuspend(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("failed to initialize screen: %v", err)
+	}
+	term, err := terminal.NewWithScreen(screen, terminal.DefaultConfig())
+	if err != nil {
+		t.Fatalf("failed to create terminal: %v", err)
+	}
+	defer term.Shutdown()
+
+	resumeCalled := false
+	term.OnResume(func() {
+		resumeCalled = true
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- term.Resume() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error on resume: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resume without suspend did not return")
+	}
+	if resumeCalled {
+		t.Error("resume callback was called without a suspend")
+	}
+}
+
 func TestUnicodeSupport(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -297,6 +391,42 @@ func TestUnicodeSupport(t *testing.T) {
 	}
 }
 
+func TestTerminalDrawBorder(t *testing.T) {
+	tests := []struct {
+		name    string
+		unicode bool
+		want    []string
+	}{
+		{"Unicode", true, []string{"╔═╗", "║ ║", "╚═╝"}},
+		{"ASCII", false, []string{"+=+", "| |", "+=+"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := setupTest(t)
+			defer ctx.term.Shutdown()
+
+			if tt.unicode {
+				ctx.term.EnableUnicode()
+			} else {
+				ctx.term.DisableUnicode()
+			}
+
+			ctx.term.DrawBorder(geometry.NewRect(0, 0, 3, 3), style.NewBorder(style.BorderDouble, style.Style{}))
+			ctx.term.Present()
+
+			simScreen := ctx.screen.(tcell.SimulationScreen)
+			for y, row := range tt.want {
+				for x, want := range []rune(row) {
+					if got, _, _, _ := simScreen.GetContent(x, y); got != want {
+						t.Errorf("cell (%d, %d) = %q, want %q", x, y, got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
 func TestTerminalConcurrency(t *testing.T) {
 	ctx := setupTest(t)
 	defer ctx.term.Shutdown()
@@ -439,3 +569,44 @@ func TestCombiningCharacters(t *testing.T) {
 		})
 	}
 }
+
+func TestDrawTextClusters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		// cells holds what each cell shows, with "" for the second half of a
+		// wide character
+		cells []string
+	}{
+		{"CJK", "日本!", []string{"日", "", "本", "", "!"}},
+		{"emoji ZWJ sequence", "👨‍👩‍👧!", []string{"👨‍👩‍👧", "", "!"}},
+		{"flag", "🇯🇵!", []string{"🇯🇵", "", "!"}},
+		{"combining marks", "é!", []string{"é", "!"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := setupTest(t)
+			defer ctx.term.Shutdown()
+			ctx.term.EnableUnicode()
+			ctx.term.EnableCombiningChars()
+
+			if width := ctx.term.StringWidth(tt.input); width != len(tt.cells) {
+				t.Errorf("got width %d, want %d", width, len(tt.cells))
+			}
+			ctx.term.DrawText(0, 0, tt.input, color.White, color.Black, 0)
+			ctx.term.Present()
+
+			simScreen := ctx.screen.(tcell.SimulationScreen)
+			for x, want := range tt.cells {
+				if want == "" {
+					continue
+				}
+				mainc, combc, _, _ := simScreen.GetContent(x, 0)
+				if got := string(mainc) + string(combc); got != want {
+					t.Errorf("cell %d: got %q, want %q", x, got, want)
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,9 @@ type Capabilities struct {
 	SupportsUnderline     bool
 	SupportsStrikethrough bool
 
+	// Character support
+	SupportsUnicode bool
+
 	// Input capabilities
 	SupportsMouse    bool
 	SupportsKeyboard bool
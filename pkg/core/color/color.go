@@ -206,6 +206,16 @@ func (c Color) Darken(amount float64) Color {
 	return Color{R: r, G: g, B: b, A: c.A}
 }
 
+// Blend returns the color amount of the way from c to other, where 0 gives
+// c and 1 gives other. The alpha of c is kept.
+func (c Color) Blend(other Color, amount float64) Color {
+	amount = math.Max(0, math.Min(1, amount))
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*amount))
+	}
+	return Color{R: mix(c.R, other.R), G: mix(c.G, other.G), B: mix(c.B, other.B), A: c.A}
+}
+
 // WithAlpha returns a new Color with the specified alpha value
 func (c Color) WithAlpha(alpha uint8) Color {
 	return Color{R: c.R, G: c.G, B: c.B, A: alpha}
@@ -121,6 +121,26 @@ func TestColorDarken(t *testing.T) {
 	}
 }
 
+func TestColorBlend(t *testing.T) {
+	from := color.Color{R: 200, G: 100, B: 0, A: 255}
+	to := color.Color{R: 0, G: 100, B: 200, A: 128}
+
+	tests := []struct {
+		amount float64
+		want   color.Color
+	}{
+		{0, from},
+		{0.25, color.Color{R: 150, G: 100, B: 50, A: 255}},
+		{1, color.Color{R: 0, G: 100, B: 200, A: 255}},
+		{2, color.Color{R: 0, G: 100, B: 200, A: 255}},
+	}
+	for _, tt := range tests {
+		if got := from.Blend(to, tt.amount); got != tt.want {
+			t.Errorf("Blend(%v) = %+v, want %+v", tt.amount, got, tt.want)
+		}
+	}
+}
+
 func TestColorWithAlpha(t *testing.T) {
 	c := color.Color{R: 100, G: 150, B: 200, A: 255}
 	newAlpha := uint8(128)
@@ -4,6 +4,16 @@ type Point struct {
 	X, Y int
 }
 
+// Add returns the point translated by o
+func (p Point) Add(o Point) Point {
+	return Point{X: p.X + o.X, Y: p.Y + o.Y}
+}
+
+// Sub returns the point translated by -o
+func (p Point) Sub(o Point) Point {
+	return Point{X: p.X - o.X, Y: p.Y - o.Y}
+}
+
 type Size struct {
 	Width, Height int
 }
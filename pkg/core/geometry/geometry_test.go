@@ -11,6 +11,18 @@ import (
 	"github.com/watzon/tide/pkg/core/geometry"
 )
 
+func TestPoint(t *testing.T) {
+	p := geometry.Point{X: 3, Y: 4}
+	o := geometry.Point{X: 1, Y: 2}
+
+	if got := p.Add(o); got != (geometry.Point{X: 4, Y: 6}) {
+		t.Errorf("expected (4,6), got (%d,%d)", got.X, got.Y)
+	}
+	if got := p.Sub(o); got != (geometry.Point{X: 2, Y: 2}) {
+		t.Errorf("expected (2,2), got (%d,%d)", got.X, got.Y)
+	}
+}
+
 func TestRect(t *testing.T) {
 	t.Run("NewRect", func(t *testing.T) {
 		rect := geometry.NewRect(10, 20, 30, 40)
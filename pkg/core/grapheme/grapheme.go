@@ -0,0 +1,68 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+// Package grapheme splits text into extended grapheme clusters, the
+// characters a reader sees, and measures the terminal cells they take. A
+// cluster can be many runes: a letter and its combining marks, emoji
+// joined by zero width joiners, or the two regional indicators of a flag.
+package grapheme
+
+import (
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// maxClusterRunes is how many runes Next looks at to find the end of a
+// cluster. Only piles of combining marks grow longer, and they are split.
+const maxClusterRunes = 32
+
+// Cluster is a grapheme cluster and the cells it takes
+type Cluster struct {
+	Text  string
+	Width int
+}
+
+// Clusters splits s into its grapheme clusters
+func Clusters(s string) []Cluster {
+	var clusters []Cluster
+	state := -1
+	for s != "" {
+		var cluster string
+		var width int
+		cluster, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, Cluster{Text: cluster, Width: cellWidth(width)})
+	}
+	return clusters
+}
+
+// Width returns the cells s takes on one line
+func Width(s string) int {
+	total := 0
+	state := -1
+	for s != "" {
+		var width int
+		_, s, width, state = uniseg.FirstGraphemeClusterInString(s, state)
+		total += cellWidth(width)
+	}
+	return total
+}
+
+// Next returns how many runes the cluster at the start of runes has, and
+// the cells it takes. It returns zero for no runes.
+func Next(runes []rune) (length, width int) {
+	if len(runes) == 0 {
+		return 0, 0
+	}
+	window := string(runes[:min(len(runes), maxClusterRunes)])
+	cluster, _, width, _ := uniseg.FirstGraphemeClusterInString(window, -1)
+	return utf8.RuneCountInString(cluster), cellWidth(width)
+}
+
+// cellWidth is the width of a cluster on screen. Control characters and
+// lone marks measure nothing but still take a cell when drawn.
+func cellWidth(width int) int {
+	return max(1, width)
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package grapheme_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/watzon/tide/pkg/core/grapheme"
+)
+
+func TestClusters(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []grapheme.Cluster
+	}{
+		{"ASCII", "ab", []grapheme.Cluster{{"a", 1}, {"b", 1}}},
+		{"combining marks", "éx", []grapheme.Cluster{{"é", 1}, {"x", 1}}},
+		{"CJK", "日本", []grapheme.Cluster{{"日", 2}, {"本", 2}}},
+		{"emoji ZWJ sequence", "👨‍👩‍👧!", []grapheme.Cluster{{"👨‍👩‍👧", 2}, {"!", 1}}},
+		{"flags", "🇯🇵🇫🇷", []grapheme.Cluster{{"🇯🇵", 2}, {"🇫🇷", 2}}},
+		{"control characters take a cell", "\t", []grapheme.Cluster{{"\t", 1}}},
+		{"empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := grapheme.Clusters(tt.input); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Clusters(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"Hello", 5},
+		{"Hello 世界", 10},
+		{"é", 1},
+		{"👍🏽", 2},
+		{"👨‍👩‍👧", 2},
+		{"🇯🇵", 2},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := grapheme.Width(tt.input); got != tt.want {
+			t.Errorf("Width(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantLength int
+		wantWidth  int
+	}{
+		{"ab", 1, 1},
+		{"ä́b", 3, 1},
+		{"👨‍👩‍👧", 5, 2},
+		{"🇯🇵🇫🇷", 2, 2},
+		{"", 0, 0},
+	}
+
+	for _, tt := range tests {
+		length, width := grapheme.Next([]rune(tt.input))
+		if length != tt.wantLength || width != tt.wantWidth {
+			t.Errorf("Next(%q) = %d, %d, want %d, %d", tt.input, length, width, tt.wantLength, tt.wantWidth)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package style
+
+import "github.com/watzon/tide/pkg/core/geometry"
+
+// BorderStyle is the kind of line a border is drawn with
+type BorderStyle int
+
+const (
+	BorderNone BorderStyle = iota
+	BorderSingle
+	BorderDouble
+	BorderRounded
+	BorderHeavy
+	BorderDashed
+	BorderDotted
+)
+
+// BorderGlyphs are the characters a border is drawn with
+type BorderGlyphs struct {
+	TopLeft     rune
+	TopRight    rune
+	BottomLeft  rune
+	BottomRight rune
+	Horizontal  rune
+	Vertical    rune
+}
+
+// unicodeBorders holds the box drawing characters for each border style
+var unicodeBorders = map[BorderStyle]BorderGlyphs{
+	BorderSingle:  {'┌', '┐', '└', '┘', '─', '│'},
+	BorderDouble:  {'╔', '╗', '╚', '╝', '═', '║'},
+	BorderRounded: {'╭', '╮', '╰', '╯', '─', '│'},
+	BorderHeavy:   {'┏', '┓', '┗', '┛', '━', '┃'},
+	BorderDashed:  {'┌', '┐', '└', '┘', '╌', '╎'},
+	BorderDotted:  {'┌', '┐', '└', '┘', '┈', '┊'},
+}
+
+// asciiBorders holds the fallback characters for backends without Unicode
+var asciiBorders = map[BorderStyle]BorderGlyphs{
+	BorderSingle:  {'+', '+', '+', '+', '-', '|'},
+	BorderDouble:  {'+', '+', '+', '+', '=', '|'},
+	BorderRounded: {'.', '.', '\'', '\'', '-', '|'},
+	BorderHeavy:   {'#', '#', '#', '#', '#', '#'},
+	BorderDashed:  {'+', '+', '+', '+', '-', ':'},
+	BorderDotted:  {'.', '.', '.', '.', '.', ':'},
+}
+
+// Glyphs returns the characters the border style is drawn with, using
+// ASCII when the backend can't draw Unicode box characters. BorderNone
+// has no glyphs.
+func (b BorderStyle) Glyphs(unicode bool) BorderGlyphs {
+	if unicode {
+		return unicodeBorders[b]
+	}
+	return asciiBorders[b]
+}
+
+// Border describes the lines drawn along the edges of a rectangle. Only the
+// enabled sides are drawn; a corner joins two sides when both are enabled.
+// The title is drawn over the top side, after the top left corner.
+type Border struct {
+	// Style holds the colors and attributes of the border cells
+	Style
+	Line   BorderStyle
+	Top    bool
+	Right  bool
+	Bottom bool
+	Left   bool
+	Title  string
+}
+
+// NewBorder creates a border with all four sides drawn using line
+func NewBorder(line BorderStyle, s Style) Border {
+	return Border{Style: s, Line: line, Top: true, Right: true, Bottom: true, Left: true}
+}
+
+// Walk calls draw with the position and character of every border cell
+// along the edges of rect
+func (b Border) Walk(rect geometry.Rect, unicode bool, draw func(x, y int, ch rune)) {
+	if b.Line == BorderNone || rect.IsEmpty() {
+		return
+	}
+	g := b.Line.Glyphs(unicode)
+
+	if b.Top {
+		start := corner(b.Left, g.TopLeft, g.Horizontal)
+		end := corner(b.Right, g.TopRight, g.Horizontal)
+		walkRow(rect, rect.Min.Y, start, end, []rune(b.Title), g.Horizontal, draw)
+	}
+	if b.Bottom && (!b.Top || rect.Max.Y-rect.Min.Y > 1) {
+		start := corner(b.Left, g.BottomLeft, g.Horizontal)
+		end := corner(b.Right, g.BottomRight, g.Horizontal)
+		walkRow(rect, rect.Max.Y-1, start, end, nil, g.Horizontal, draw)
+	}
+
+	// The top and bottom rows already hold the corners
+	top, bottom := rect.Min.Y, rect.Max.Y-1
+	if b.Top {
+		top++
+	}
+	if b.Bottom {
+		bottom--
+	}
+	for y := top; y <= bottom; y++ {
+		if b.Left {
+			draw(rect.Min.X, y, g.Vertical)
+		}
+		if b.Right && rect.Max.X-rect.Min.X > 1 {
+			draw(rect.Max.X-1, y, g.Vertical)
+		}
+	}
+}
+
+// walkRow draws a horizontal side between its end characters, with the
+// title, if any, starting one cell in
+func walkRow(rect geometry.Rect, y int, start, end rune, title []rune, line rune, draw func(x, y int, ch rune)) {
+	last := rect.Max.X - 1
+	for x := rect.Min.X; x <= last; x++ {
+		ch := line
+		switch i := x - rect.Min.X - 1; {
+		case x == rect.Min.X:
+			ch = start
+		case x == last:
+			ch = end
+		case i < len(title):
+			ch = title[i]
+		}
+		draw(x, y, ch)
+	}
+}
+
+// corner returns the corner glyph when the side meeting the row is drawn,
+// and continues the row's line otherwise
+func corner(side bool, glyph, line rune) rune {
+	if side {
+		return glyph
+	}
+	return line
+}
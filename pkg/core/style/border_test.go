@@ -0,0 +1,171 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package style
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// drawBorder walks a border around a width by height rect and returns the
+// rows of cells it drew, with spaces where nothing was drawn
+func drawBorder(b Border, width, height int, unicode bool) []string {
+	rows := make([][]rune, height)
+	for y := range rows {
+		rows[y] = []rune(strings.Repeat(" ", width))
+	}
+	b.Walk(geometry.NewRect(0, 0, width, height), unicode, func(x, y int, ch rune) {
+		rows[y][x] = ch
+	})
+
+	lines := make([]string, height)
+	for y, row := range rows {
+		lines[y] = string(row)
+	}
+	return lines
+}
+
+func TestBorderWalk(t *testing.T) {
+	tests := []struct {
+		name    string
+		border  Border
+		width   int
+		height  int
+		unicode bool
+		want    []string
+	}{
+		{
+			name:    "Single",
+			border:  NewBorder(BorderSingle, Style{}),
+			width:   4,
+			height:  3,
+			unicode: true,
+			want:    []string{"┌──┐", "│  │", "└──┘"},
+		},
+		{
+			name:    "Double",
+			border:  NewBorder(BorderDouble, Style{}),
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"╔═╗", "║ ║", "╚═╝"},
+		},
+		{
+			name:    "Rounded",
+			border:  NewBorder(BorderRounded, Style{}),
+			width:   3,
+			height:  2,
+			unicode: true,
+			want:    []string{"╭─╮", "╰─╯"},
+		},
+		{
+			name:    "Heavy",
+			border:  NewBorder(BorderHeavy, Style{}),
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"┏━┓", "┃ ┃", "┗━┛"},
+		},
+		{
+			name:    "Dashed",
+			border:  NewBorder(BorderDashed, Style{}),
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"┌╌┐", "╎ ╎", "└╌┘"},
+		},
+		{
+			name:    "Dotted",
+			border:  NewBorder(BorderDotted, Style{}),
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"┌┈┐", "┊ ┊", "└┈┘"},
+		},
+		{
+			name:   "ASCII fallback",
+			border: NewBorder(BorderRounded, Style{}),
+			width:  4,
+			height: 3,
+			want:   []string{".--.", "|  |", "'--'"},
+		},
+		{
+			name:    "None",
+			border:  NewBorder(BorderNone, Style{}),
+			width:   3,
+			height:  2,
+			unicode: true,
+			want:    []string{"   ", "   "},
+		},
+		{
+			name:    "Top and bottom only",
+			border:  Border{Line: BorderSingle, Top: true, Bottom: true},
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"───", "   ", "───"},
+		},
+		{
+			name:    "Left and top",
+			border:  Border{Line: BorderSingle, Top: true, Left: true},
+			width:   3,
+			height:  3,
+			unicode: true,
+			want:    []string{"┌──", "│  ", "│  "},
+		},
+		{
+			name:    "Title",
+			border:  Border{Line: BorderSingle, Top: true, Right: true, Bottom: true, Left: true, Title: "Hi"},
+			width:   6,
+			height:  2,
+			unicode: true,
+			want:    []string{"┌Hi──┐", "└────┘"},
+		},
+		{
+			name:    "Long title is cut off",
+			border:  Border{Line: BorderSingle, Top: true, Right: true, Bottom: true, Left: true, Title: "Title"},
+			width:   5,
+			height:  2,
+			unicode: true,
+			want:    []string{"┌Tit┐", "└───┘"},
+		},
+		{
+			name:    "Single row",
+			border:  NewBorder(BorderSingle, Style{}),
+			width:   3,
+			height:  1,
+			unicode: true,
+			want:    []string{"┌─┐"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := drawBorder(tt.border, tt.width, tt.height, tt.unicode)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Walk() drew\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestBorderStyleGlyphs(t *testing.T) {
+	for _, line := range []BorderStyle{BorderSingle, BorderDouble, BorderRounded, BorderHeavy, BorderDashed, BorderDotted} {
+		for _, unicode := range []bool{true, false} {
+			g := line.Glyphs(unicode)
+			for _, ch := range []rune{g.TopLeft, g.TopRight, g.BottomLeft, g.BottomRight, g.Horizontal, g.Vertical} {
+				if ch == 0 {
+					t.Errorf("Glyphs(%v) for style %d has an empty glyph", unicode, line)
+				}
+				if !unicode && ch > 127 {
+					t.Errorf("Glyphs(false) for style %d has non-ASCII glyph %q", line, ch)
+				}
+			}
+		}
+	}
+}
@@ -49,3 +49,21 @@ func (s Style) AdaptStyle(caps capabilities.Capabilities) Style {
 
 	return adapted
 }
+
+// Merge returns the style with other laid over it. Transparent colors of
+// other leave the style's showing, and its text properties add to the
+// style's.
+func (s Style) Merge(other Style) Style {
+	result := s
+	if other.ForegroundColor.A > 0 {
+		result.ForegroundColor = other.ForegroundColor
+	}
+	if other.BackgroundColor.A > 0 {
+		result.BackgroundColor = other.BackgroundColor
+	}
+	result.Bold = result.Bold || other.Bold
+	result.Italic = result.Italic || other.Italic
+	result.Underline = result.Underline || other.Underline
+	result.StrikeThrough = result.StrikeThrough || other.StrikeThrough
+	return result
+}
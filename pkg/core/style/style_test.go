@@ -134,3 +134,28 @@ func TestAdaptStyle(t *testing.T) {
 		})
 	}
 }
+
+func TestMerge(t *testing.T) {
+	red := color.Color{R: 255, A: 255}
+	blue := color.Color{B: 255, A: 255}
+	base := Style{ForegroundColor: red, BackgroundColor: blue, Bold: true}
+
+	tests := []struct {
+		name  string
+		other Style
+		want  Style
+	}{
+		{"empty style keeps the base", Style{}, base},
+		{"colors override", Style{ForegroundColor: blue}, Style{ForegroundColor: blue, BackgroundColor: blue, Bold: true}},
+		{"transparent colors don't", Style{BackgroundColor: color.Color{R: 255}}, base},
+		{"attributes add", Style{Italic: true, StrikeThrough: true}, Style{ForegroundColor: red, BackgroundColor: blue, Bold: true, Italic: true, StrikeThrough: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := base.Merge(tt.other); got != tt.want {
+				t.Errorf("Merge() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
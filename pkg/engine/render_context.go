@@ -27,7 +27,7 @@ type RenderContext interface {
 	DrawText(pos geometry.Point, text string, style style.Style)
 
 	// Box model operations
-	PaintBorder(rect geometry.Rect, style style.Style)
+	PaintBorder(rect geometry.Rect, border style.Border)
 
 	// Clipping
 	PushClipRect(rect geometry.Rect)
@@ -36,6 +36,10 @@ type RenderContext interface {
 	// Transformation
 	PushOffset(offset geometry.Point)
 	PopOffset()
+
+	// Cursor placement. The cursor is hidden when a frame is cleared and
+	// shown where it was last set once the frame is presented.
+	SetCursor(x, y int)
 }
 
 // ClipRect represents a clipping rectangle
@@ -50,6 +54,7 @@ type BaseRenderContext struct {
 	size         geometry.Size
 	clipRect     *ClipRect
 	offset       geometry.Point
+	offsets      []geometry.Point
 }
 
 func NewBaseRenderContext(caps capabilities.Capabilities, size geometry.Size) *BaseRenderContext {
@@ -67,9 +72,12 @@ func (c *BaseRenderContext) Size() geometry.Size {
 	return c.size
 }
 
+// PushClipRect restricts drawing to rect, given relative to the current
+// offset. Drawing stays within every clip rect on the stack, so a nested
+// clip can only shrink the drawable area.
 func (c *BaseRenderContext) PushClipRect(rect geometry.Rect) {
 	c.clipRect = &ClipRect{
-		Rect: rect,
+		Rect: geometry.Rect{Min: rect.Min.Add(c.offset), Max: rect.Max.Add(c.offset)},
 		Next: c.clipRect,
 	}
 }
@@ -80,18 +88,25 @@ func (c *BaseRenderContext) PopClipRect() {
 	}
 }
 
+// PushOffset translates subsequent drawing by offset, relative to the
+// current offset
 func (c *BaseRenderContext) PushOffset(offset geometry.Point) {
+	c.offsets = append(c.offsets, c.offset)
 	c.offset = geometry.Point{
 		X: c.offset.X + offset.X,
 		Y: c.offset.Y + offset.Y,
 	}
 }
 
+// PopOffset restores the offset in effect before the matching PushOffset
 func (c *BaseRenderContext) PopOffset() {
 	// Offsets should be balanced with pushes
-	if c.offset.X != 0 || c.offset.Y != 0 {
+	if len(c.offsets) == 0 {
 		c.offset = geometry.Point{}
+		return
 	}
+	c.offset = c.offsets[len(c.offsets)-1]
+	c.offsets = c.offsets[:len(c.offsets)-1]
 }
 
 // Helper methods for implementations
@@ -103,13 +118,13 @@ func (c *BaseRenderContext) IsInClipRect(x, y int) bool {
 	}
 
 	// Apply offset
-	x += c.offset.X
-	y += c.offset.Y
-
-	return x >= c.clipRect.Rect.Min.X &&
-		x < c.clipRect.Rect.Max.X &&
-		y >= c.clipRect.Rect.Min.Y &&
-		y < c.clipRect.Rect.Max.Y
+	p := geometry.Point{X: x + c.offset.X, Y: y + c.offset.Y}
+	for clip := c.clipRect; clip != nil; clip = clip.Next {
+		if !clip.Rect.Contains(p) {
+			return false
+		}
+	}
+	return true
 }
 
 // TransformPoint applies the current offset to a point
@@ -135,6 +150,8 @@ type MockRenderContext struct {
 	ClipRectPops     int
 	OffsetPushes     []geometry.Point
 	OffsetPops       int
+	// Cursor is where the cursor was last set, in absolute coordinates
+	Cursor geometry.Point
 }
 
 type DrawCellCall struct {
@@ -151,8 +168,8 @@ type DrawTextCall struct {
 }
 
 type PaintBorderCall struct {
-	Rect  geometry.Rect
-	Style style.Style
+	Rect   geometry.Rect
+	Border style.Border
 }
 
 func NewMockRenderContext(size geometry.Size) *MockRenderContext {
@@ -199,10 +216,10 @@ func (c *MockRenderContext) DrawText(pos geometry.Point, text string, s style.St
 	})
 }
 
-func (c *MockRenderContext) PaintBorder(rect geometry.Rect, s style.Style) {
+func (c *MockRenderContext) PaintBorder(rect geometry.Rect, border style.Border) {
 	c.PaintBorderCalls = append(c.PaintBorderCalls, PaintBorderCall{
-		Rect:  rect,
-		Style: s,
+		Rect:   rect,
+		Border: border,
 	})
 }
 
@@ -225,3 +242,8 @@ func (c *MockRenderContext) PopOffset() {
 	c.OffsetPops++
 	c.BaseRenderContext.PopOffset()
 }
+
+func (c *MockRenderContext) SetCursor(x, y int) {
+	tx, ty := c.TransformPoint(x, y)
+	c.Cursor = geometry.Point{X: tx, Y: ty}
+}
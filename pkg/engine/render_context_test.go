@@ -110,9 +110,14 @@ func TestBaseRenderContextOffset(t *testing.T) {
 	}
 
 	// Test popping offsets
+	ctx.PopOffset()
+	if ctx.offset != offset1 {
+		t.Errorf("Offset not restored after pop, got %v, want %v", ctx.offset, offset1)
+	}
+
 	ctx.PopOffset()
 	if ctx.offset != (geometry.Point{}) {
-		t.Error("Offset not cleared after pop")
+		t.Error("Offset not cleared after popping every push")
 	}
 
 	// Test popping empty offset
@@ -161,6 +166,39 @@ func TestBaseRenderContextIsInClipRect(t *testing.T) {
 	}
 }
 
+func TestBaseRenderContextNestedClipRect(t *testing.T) {
+	ctx := NewBaseRenderContext(capabilities.Capabilities{}, geometry.Size{})
+	ctx.PushClipRect(geometry.NewRect(0, 0, 10, 10))
+
+	// Clip rects are relative to the offset they are pushed under
+	ctx.PushOffset(geometry.Point{X: 5, Y: 5})
+	ctx.PushClipRect(geometry.NewRect(0, 0, 10, 10))
+
+	tests := []struct {
+		x, y     int
+		expected bool
+		name     string
+	}{
+		{0, 0, true, "origin of both clips"},
+		{4, 4, true, "inside both clips"},
+		{5, 5, false, "inside inner clip, outside outer clip"},
+		{-1, -1, false, "inside outer clip, outside inner clip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ctx.IsInClipRect(tt.x, tt.y); got != tt.expected {
+				t.Errorf("IsInClipRect(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.expected)
+			}
+		})
+	}
+
+	ctx.PopClipRect()
+	if !ctx.IsInClipRect(-1, -1) {
+		t.Error("Expected outer clip only after pop")
+	}
+}
+
 func TestBaseRenderContextTransformPoint(t *testing.T) {
 	ctx := NewBaseRenderContext(capabilities.Capabilities{}, geometry.Size{})
 
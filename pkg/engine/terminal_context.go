@@ -10,6 +10,7 @@ import (
 	"github.com/watzon/tide/pkg/core/capabilities"
 	"github.com/watzon/tide/pkg/core/color"
 	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
 	"github.com/watzon/tide/pkg/core/style"
 )
 
@@ -21,13 +22,8 @@ type TerminalContext struct {
 
 func NewTerminalContext(term *terminal.Terminal) *TerminalContext {
 	ctx := &TerminalContext{
-		BaseRenderContext: NewBaseRenderContext(capabilities.Capabilities{
-			ColorMode:        capabilities.ColorTrueColor,
-			SupportsItalic:   true,
-			SupportsBold:     true,
-			SupportsKeyboard: true,
-		}, term.Size()),
-		term: term,
+		BaseRenderContext: NewBaseRenderContext(terminalCapabilities(term.Capabilities()), term.Size()),
+		term:              term,
 	}
 
 	// Set initial clip rect to full terminal size
@@ -37,9 +33,38 @@ func NewTerminalContext(term *terminal.Terminal) *TerminalContext {
 	return ctx
 }
 
+// terminalCapabilities translates what the terminal reports into render
+// capabilities
+func terminalCapabilities(caps terminal.Capabilities) capabilities.Capabilities {
+	return capabilities.Capabilities{
+		ColorMode:             colorMode(caps.ColorMode),
+		SupportsItalic:        caps.Italic,
+		SupportsBold:          true,
+		SupportsUnderline:     true,
+		SupportsStrikethrough: caps.Strikethrough,
+		SupportsUnicode:       caps.Unicode,
+		SupportsMouse:         caps.Mouse,
+		SupportsKeyboard:      true,
+	}
+}
+
+func colorMode(mode terminal.ColorMode) capabilities.ColorMode {
+	switch mode {
+	case terminal.ColorTrueColor:
+		return capabilities.ColorTrueColor
+	case terminal.Color256:
+		return capabilities.Color256
+	case terminal.Color16:
+		return capabilities.Color16
+	default:
+		return capabilities.ColorNone
+	}
+}
+
 // Basic drawing operations
 func (t *TerminalContext) Clear() {
 	t.term.Clear()
+	t.term.SetCursor(-1, -1)
 }
 
 func (t *TerminalContext) Present() error {
@@ -60,8 +85,11 @@ func (t *TerminalContext) DrawStyledCell(x, y int, ch rune, fg, bg color.Color,
 		return
 	}
 	tx, ty := t.TransformPoint(x, y)
+	t.term.DrawStyledCell(tx, ty, ch, fg, bg, styleMask(s))
+}
 
-	// Convert style.Style to terminal.StyleMask
+// styleMask converts the attributes of a style to a terminal.StyleMask
+func styleMask(s style.Style) terminal.StyleMask {
 	var mask terminal.StyleMask
 	if s.Bold {
 		mask |= terminal.StyleBold
@@ -72,27 +100,51 @@ func (t *TerminalContext) DrawStyledCell(x, y int, ch rune, fg, bg color.Color,
 	if s.Underline {
 		mask |= terminal.StyleUnderline
 	}
-
-	t.term.DrawStyledCell(tx, ty, ch, fg, bg, mask)
+	if s.StrikeThrough {
+		mask |= terminal.StyleStrikethrough
+	}
+	return mask
 }
 
-// Text operations
+// DrawText draws a line of text from pos a grapheme cluster at a time. A
+// cluster that doesn't fit in the bounds and clip rect is left out whole.
 func (t *TerminalContext) DrawText(pos geometry.Point, text string, s style.Style) {
-	if !t.IsInClipRect(pos.X, pos.Y) {
-		return
+	mask := styleMask(s)
+	x := pos.X
+	for _, cluster := range grapheme.Clusters(text) {
+		if t.fits(x, pos.Y, cluster.Width) {
+			tx, ty := t.TransformPoint(x, pos.Y)
+			t.term.DrawText(tx, ty, cluster.Text, s.ForegroundColor, s.BackgroundColor, mask)
+		}
+		x += cluster.Width
 	}
-	tx, ty := t.TransformPoint(pos.X, pos.Y)
-	t.term.DrawText(tx, ty, text, s.ForegroundColor, s.BackgroundColor, terminal.StyleMask(0))
 }
 
-// Box model operations
-func (t *TerminalContext) PaintBorder(rect geometry.Rect, s style.Style) {
-	// Transform rect according to current offset and clipping
-	tRect := t.TransformRect(rect)
-	if tRect.IsEmpty() {
+// fits reports whether the cells from x to x+width on row y are all in
+// the bounds and clip rect
+func (t *TerminalContext) fits(x, y, width int) bool {
+	last := x + width - 1
+	return t.IsInBounds(x, y) && t.IsInClipRect(x, y) && t.IsInBounds(last, y) && t.IsInClipRect(last, y)
+}
+
+// SetCursor shows the cursor at x, y once the frame is presented. A
+// position outside the clip rect leaves the cursor where it was.
+func (t *TerminalContext) SetCursor(x, y int) {
+	if !t.IsInBounds(x, y) || !t.IsInClipRect(x, y) {
 		return
 	}
-	t.term.DrawBorder(tRect, s)
+	tx, ty := t.TransformPoint(x, y)
+	t.term.SetCursor(tx, ty)
+}
+
+// Box model operations
+
+// PaintBorder draws the border cell by cell so it is offset and clipped
+// like any other drawing
+func (t *TerminalContext) PaintBorder(rect geometry.Rect, border style.Border) {
+	border.Walk(rect, t.capabilities.SupportsUnicode, func(x, y int, ch rune) {
+		t.DrawStyledCell(x, y, ch, border.ForegroundColor, border.BackgroundColor, border.Style)
+	})
 }
 
 // Helper methods
@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/capabilities"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/style"
+)
+
+func TestTerminalCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		caps terminal.Capabilities
+		want capabilities.Capabilities
+	}{
+		{
+			name: "plain terminal",
+			caps: terminal.Capabilities{ColorMode: terminal.ColorNone},
+			want: capabilities.Capabilities{
+				ColorMode:         capabilities.ColorNone,
+				SupportsBold:      true,
+				SupportsUnderline: true,
+				SupportsKeyboard:  true,
+			},
+		},
+		{
+			name: "modern terminal",
+			caps: terminal.Capabilities{
+				ColorMode:     terminal.ColorTrueColor,
+				Unicode:       true,
+				Italic:        true,
+				Strikethrough: true,
+				Mouse:         true,
+			},
+			want: capabilities.Capabilities{
+				ColorMode:             capabilities.ColorTrueColor,
+				SupportsItalic:        true,
+				SupportsBold:          true,
+				SupportsUnderline:     true,
+				SupportsStrikethrough: true,
+				SupportsUnicode:       true,
+				SupportsMouse:         true,
+				SupportsKeyboard:      true,
+			},
+		},
+		{
+			name: "256 colors",
+			caps: terminal.Capabilities{ColorMode: terminal.Color256},
+			want: capabilities.Capabilities{
+				ColorMode:         capabilities.Color256,
+				SupportsBold:      true,
+				SupportsUnderline: true,
+				SupportsKeyboard:  true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := terminalCapabilities(tt.caps); got != tt.want {
+				t.Errorf("terminalCapabilities() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTerminalContext_DrawText(t *testing.T) {
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("failed to initialize screen: %v", err)
+	}
+	term, err := terminal.NewWithScreen(screen, terminal.DefaultConfig())
+	if err != nil {
+		t.Fatalf("failed to create terminal: %v", err)
+	}
+	defer term.Shutdown()
+	term.EnableUnicode()
+	term.EnableCombiningChars()
+
+	ctx := NewTerminalContext(term)
+	ctx.PushClipRect(geometry.NewRect(0, 0, 3, 1))
+	ctx.DrawText(geometry.Point{}, "日本語", style.Style{ForegroundColor: color.White, Bold: true, StrikeThrough: true})
+	ctx.PopClipRect()
+	ctx.DrawText(geometry.Point{Y: 1}, "🇯🇵!", style.Style{})
+	if err := ctx.Present(); err != nil {
+		t.Fatalf("Present() error = %v", err)
+	}
+
+	mainc, _, s, _ := screen.GetContent(0, 0)
+	if mainc != '日' {
+		t.Errorf("got %q at 0, 0, want '日'", mainc)
+	}
+	if _, _, attrs := s.Decompose(); attrs&tcell.AttrBold == 0 || attrs&tcell.AttrStrikeThrough == 0 {
+		t.Errorf("got attributes %v, want bold and strikethrough", attrs)
+	}
+	if mainc, _, _, _ := screen.GetContent(2, 0); mainc != ' ' {
+		t.Errorf("got %q at 2, 0, want the clipped character left out", mainc)
+	}
+
+	mainc, combc, _, _ := screen.GetContent(0, 1)
+	if got := string(mainc) + string(combc); got != "🇯🇵" {
+		t.Errorf("got %q at 0, 1, want the whole flag", got)
+	}
+	if mainc, _, _, _ := screen.GetContent(2, 1); mainc != '!' {
+		t.Errorf("got %q at 2, 1, want '!' after the flag", mainc)
+	}
+}
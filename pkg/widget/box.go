@@ -18,33 +18,26 @@ func (b *Box) AppendChild(child Widget) {
 	b.children = append(b.children, child)
 }
 
+// GetChildren returns the child widgets of the box
+func (b *Box) GetChildren() []Widget {
+	return b.children
+}
+
 func (b *Box) Build(context BuildContext) Widget {
 	return b
 }
 
+// CreateRenderObject creates the box's render object. The render objects of
+// the children are attached by the box's element.
 func (b *Box) CreateRenderObject() RenderObject {
 	box := NewBaseRenderBox()
 	box.WithStyle(b.GetStyle())
-
-	// Create render objects for children
-	for _, child := range b.children {
-		childRenderObj := child.CreateRenderObject()
-		box.AppendChild(childRenderObj)
-	}
-
 	return box
 }
 
 func (b *Box) UpdateRenderObject(renderObject RenderObject) {
 	if box, ok := renderObject.(*BaseRenderBox); ok {
 		box.WithStyle(b.GetStyle())
-
-		// Update children's render objects
-		for i, child := range b.children {
-			if i < len(box.Children()) {
-				child.UpdateRenderObject(box.Children()[i])
-			}
-		}
 	}
 }
 
@@ -55,18 +48,9 @@ func (r *BaseRenderBox) Paint(context engine.RenderContext) {
 	r.PaintContent(context) // This should call Paint on all children
 }
 
+// PaintContent paints each child at the offset layout placed it at
 func (r *BaseRenderBox) PaintContent(context engine.RenderContext) {
-	// Get content area
-	contentRect := r.ContentRect()
-
-	// Push offset for children
-	context.PushOffset(contentRect.Min)
-
-	// Paint each child
 	for _, child := range r.children {
-		child.Paint(context)
+		paintChild(context, child)
 	}
-
-	// Pop offset after painting children
-	context.PopOffset()
 }
@@ -26,6 +26,9 @@ type BuildContext interface {
 	// Invalidation
 	MarkNeedsBuild()
 
+	// Threading
+	Post(fn func())
+
 	// Rendering
 	RenderContext() engine.RenderContext
 }
@@ -58,12 +61,21 @@ func (c *ElementBuildContext) Element() Element {
 	return c.element
 }
 
-// Layout information
+// Size returns the size the element was last laid out at. Elements without
+// a render object report their widget's size.
 func (c *ElementBuildContext) Size() geometry.Size {
+	if renderObject := c.element.RenderObject(); renderObject != nil {
+		return renderObject.Size()
+	}
 	return c.Widget().GetSize()
 }
 
+// Constraints returns the constraints the element was last laid out with.
+// Elements without a render object report their widget's constraints.
 func (c *ElementBuildContext) Constraints() Constraints {
+	if renderObject := c.element.RenderObject(); renderObject != nil {
+		return renderObject.Constraints()
+	}
 	return c.Widget().GetConstraints()
 }
 
@@ -72,7 +84,19 @@ func (c *ElementBuildContext) MarkNeedsBuild() {
 	c.element.MarkNeedsBuild()
 }
 
-// Rendering
+// Post runs fn on the build goroutine. Background goroutines use it to
+// update state safely. It is only valid on a tree with a build owner, as
+// every tree an App runs has; without one there is no build goroutine and
+// fn is dropped.
+func (c *ElementBuildContext) Post(fn func()) {
+	if owner := c.element.Owner(); owner != nil {
+		owner.Post(fn)
+	}
+}
+
+// RenderContext returns the render context the element is painted with.
+// An ancestor implementing RenderContextProvider takes precedence over the
+// context of the build owner.
 func (c *ElementBuildContext) RenderContext() engine.RenderContext {
 	// Walk up the tree to find the nearest RenderContext
 	current := c.element
@@ -82,6 +106,10 @@ func (c *ElementBuildContext) RenderContext() engine.RenderContext {
 		}
 		current = current.Parent()
 	}
+
+	if owner := c.element.Owner(); owner != nil {
+		return owner.RenderContext()
+	}
 	return nil
 }
 
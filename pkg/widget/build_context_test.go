@@ -113,3 +113,56 @@ func TestElementBuildContext_RenderContext(t *testing.T) {
 
 	assert.Equal(t, mockRenderCtx, ctx.RenderContext())
 }
+
+func TestElementBuildContext_Post(t *testing.T) {
+	t.Run("never runs inline without an owner", func(t *testing.T) {
+		element := &MockElement{}
+		element.widget = &MockWidget{}
+		ctx := NewElementBuildContext(element)
+
+		ran := false
+		ctx.Post(func() { ran = true })
+		assert.False(t, ran)
+	})
+
+	t.Run("queues on the owner", func(t *testing.T) {
+		owner := NewBuildOwner()
+		root := NewElement(&MockWidget{})
+		owner.MountRoot(root)
+
+		ran := false
+		root.BuildContext().Post(func() { ran = true })
+		assert.False(t, ran)
+
+		owner.FlushPosted()
+		assert.True(t, ran)
+	})
+}
+
+func TestElementBuildContext_LayoutInformation(t *testing.T) {
+	owner := NewBuildOwner()
+	root := NewElement(NewText("hello"))
+	owner.MountRoot(root)
+	ctx := root.BuildContext()
+
+	// Nothing has been laid out yet
+	assert.Equal(t, geometry.Size{}, ctx.Size())
+
+	constraints := NewConstraints(geometry.Size{}, geometry.Size{Width: 40, Height: 10})
+	root.RenderObject().Layout(constraints)
+
+	assert.Equal(t, geometry.Size{Width: 5, Height: 1}, ctx.Size())
+	assert.Equal(t, constraints, ctx.Constraints())
+}
+
+func TestElementBuildContext_OwnerRenderContext(t *testing.T) {
+	owner := NewBuildOwner()
+	root := NewElement(&MockWidget{})
+	owner.MountRoot(root)
+	assert.Nil(t, root.BuildContext().RenderContext())
+
+	renderCtx := engine.NewMockRenderContext(geometry.Size{Width: 80, Height: 24})
+	owner.SetRenderContext(renderCtx)
+	assert.Equal(t, renderCtx, root.BuildContext().RenderContext())
+	assert.Equal(t, renderCtx.Capabilities(), root.BuildContext().RenderContext().Capabilities())
+}
@@ -0,0 +1,342 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// BuildOwner collects elements that need rebuilding and rebuilds them in
+// batches, shallowest first, so many SetState calls between frames result
+// in a single rebuild per element.
+//
+// The owner also defines the build goroutine: elements, states and render
+// objects attached to it must only be touched from the goroutine that calls
+// BuildScope. Other goroutines hand work over with Post.
+type BuildOwner struct {
+	lock      sync.Mutex
+	dirty     []Element
+	scheduled map[Element]bool
+	posted    []func()
+
+	// Elements removed from the tree during the current build scope. They
+	// are unmounted when the scope ends unless a global key reclaims them.
+	inactive    []Element
+	inactiveSet map[Element]bool
+
+	globalKeys map[*GlobalKey]Element
+
+	renderContext engine.RenderContext
+	clipboard     Clipboard
+	focus         *FocusManager
+
+	onBuildScheduled func()
+}
+
+// ownerAssigner is implemented by elements that can be given a build owner
+type ownerAssigner interface {
+	assignOwner(*BuildOwner)
+}
+
+// rebuildable is implemented by elements that can be forced to rebuild
+type rebuildable interface {
+	rebuild()
+}
+
+// NewBuildOwner creates a build owner with no scheduled work
+func NewBuildOwner() *BuildOwner {
+	return &BuildOwner{
+		scheduled:   make(map[Element]bool),
+		inactiveSet: make(map[Element]bool),
+		globalKeys:  make(map[*GlobalKey]Element),
+		clipboard:   &memoryClipboard{},
+		focus:       newFocusManager(),
+	}
+}
+
+// OnBuildScheduled registers a callback invoked whenever a new element is
+// scheduled or a task is posted. It may be called from any goroutine.
+func (o *BuildOwner) OnBuildScheduled(callback func()) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.onBuildScheduled = callback
+}
+
+// SetRenderContext sets the render context the tree is painted with, which
+// build contexts expose to builders
+func (o *BuildOwner) SetRenderContext(ctx engine.RenderContext) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.renderContext = ctx
+}
+
+// RenderContext returns the render context the tree is painted with, or nil
+func (o *BuildOwner) RenderContext() engine.RenderContext {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.renderContext
+}
+
+// SetClipboard sets the clipboard text widgets copy to and paste from.
+// Until one is set the owner keeps clipboard text in memory.
+func (o *BuildOwner) SetClipboard(clipboard Clipboard) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.clipboard = clipboard
+}
+
+// Clipboard returns the clipboard widgets copy to and paste from
+func (o *BuildOwner) Clipboard() Clipboard {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.clipboard
+}
+
+// FocusManager returns the manager tracking keyboard focus in the tree.
+// Like the tree, it must only be used from the build goroutine.
+func (o *BuildOwner) FocusManager() *FocusManager {
+	return o.focus
+}
+
+// MountRoot attaches the owner to a root element and mounts it
+func (o *BuildOwner) MountRoot(root Element) {
+	if assigner, ok := root.(ownerAssigner); ok {
+		assigner.assignOwner(o)
+	}
+	root.Mount(nil)
+}
+
+// ScheduleBuildFor queues an element for the next build scope. Scheduling
+// an element that is already queued is a no-op. Safe for concurrent use.
+func (o *BuildOwner) ScheduleBuildFor(element Element) {
+	o.lock.Lock()
+	if o.scheduled[element] {
+		o.lock.Unlock()
+		return
+	}
+	o.scheduled[element] = true
+	o.dirty = append(o.dirty, element)
+	callback := o.onBuildScheduled
+	o.lock.Unlock()
+
+	if callback != nil {
+		callback()
+	}
+}
+
+// Post queues fn to run on the build goroutine during the next FlushPosted.
+// Tasks run in the order they were posted. Safe for concurrent use.
+func (o *BuildOwner) Post(fn func()) {
+	if fn == nil {
+		return
+	}
+
+	o.lock.Lock()
+	o.posted = append(o.posted, fn)
+	callback := o.onBuildScheduled
+	o.lock.Unlock()
+
+	if callback != nil {
+		callback()
+	}
+}
+
+// FlushPosted runs every task posted so far and returns how many ran. Tasks
+// posted while flushing run on the next flush. Must be called from the build
+// goroutine.
+func (o *BuildOwner) FlushPosted() int {
+	o.lock.Lock()
+	tasks := o.posted
+	o.posted = nil
+	o.lock.Unlock()
+
+	for _, task := range tasks {
+		task()
+	}
+	return len(tasks)
+}
+
+// HasDirtyElements reports whether any element is waiting to be rebuilt
+func (o *BuildOwner) HasDirtyElements() bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return len(o.dirty) > 0
+}
+
+// BuildScope rebuilds every scheduled element, parents before children, and
+// returns the number of elements rebuilt. Elements scheduled while the scope
+// runs, such as children updated by their parent, are rebuilt in the same
+// scope. Must be called from the build goroutine.
+func (o *BuildOwner) BuildScope() int {
+	count := 0
+	for {
+		batch := o.takeDirty(nil)
+		if len(batch) == 0 {
+			o.finalizeTree()
+			return count
+		}
+		count += o.rebuildBatch(batch)
+	}
+}
+
+// buildScopeFor rebuilds the scheduled elements inside root's subtree and
+// leaves the rest queued for the next build scope. It lets a subtree build
+// in the middle of layout.
+func (o *BuildOwner) buildScopeFor(root Element) int {
+	count := 0
+	for {
+		batch := o.takeDirty(root)
+		if len(batch) == 0 {
+			o.finalizeTree()
+			return count
+		}
+		count += o.rebuildBatch(batch)
+	}
+}
+
+// rebuildBatch rebuilds a batch of elements, parents before children
+func (o *BuildOwner) rebuildBatch(batch []Element) int {
+	sort.SliceStable(batch, func(i, j int) bool {
+		return batch[i].Depth() < batch[j].Depth()
+	})
+
+	count := 0
+	for _, element := range batch {
+		o.lock.Lock()
+		delete(o.scheduled, element)
+		inactive := o.inactiveSet[element]
+		o.lock.Unlock()
+
+		if inactive {
+			continue
+		}
+
+		if r, ok := element.(rebuildable); ok {
+			r.rebuild()
+		} else {
+			element.RebuildIfNeeded()
+		}
+		count++
+	}
+	return count
+}
+
+// takeDirty removes and returns the scheduled elements inside root's
+// subtree, or all of them when root is nil
+func (o *BuildOwner) takeDirty(root Element) []Element {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if root == nil {
+		batch := o.dirty
+		o.dirty = nil
+		return batch
+	}
+
+	var batch, rest []Element
+	for _, element := range o.dirty {
+		if isAncestor(root, element) {
+			batch = append(batch, element)
+		} else {
+			rest = append(rest, element)
+		}
+	}
+	o.dirty = rest
+	return batch
+}
+
+// deactivate queues an element removed from the tree for unmounting at the
+// end of the build scope. Its subtree stops depending on inherited widgets
+// right away.
+func (o *BuildOwner) deactivate(element Element) {
+	deactivateDependencies(element)
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !o.inactiveSet[element] {
+		o.inactiveSet[element] = true
+		o.inactive = append(o.inactive, element)
+	}
+}
+
+// finalizeTree unmounts every element that is still inactive
+func (o *BuildOwner) finalizeTree() {
+	o.lock.Lock()
+	inactive := o.inactive
+	remaining := o.inactiveSet
+	o.inactive = nil
+	o.inactiveSet = make(map[Element]bool)
+	o.lock.Unlock()
+
+	for _, element := range inactive {
+		if remaining[element] {
+			element.Unmount()
+		}
+	}
+}
+
+func (o *BuildOwner) registerGlobalKey(key *GlobalKey, element Element) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	o.globalKeys[key] = element
+	key.owner = o
+}
+
+func (o *BuildOwner) unregisterGlobalKey(key *GlobalKey, element Element) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if o.globalKeys[key] == element {
+		delete(o.globalKeys, key)
+	}
+}
+
+func (o *BuildOwner) elementForKey(key *GlobalKey) Element {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	return o.globalKeys[key]
+}
+
+// takeElementForKey detaches the element mounted for a global key from its
+// current position so it can be moved under parent and updated to w. It
+// returns nil if the element cannot be moved there.
+func (o *BuildOwner) takeElementForKey(key *GlobalKey, w Widget, parent Element) Element {
+	element := o.elementForKey(key)
+	if element == nil || !canUpdate(element.Widget(), w) || isAncestor(element, parent) {
+		return nil
+	}
+	if _, ok := element.(reparentable); !ok {
+		return nil
+	}
+
+	o.lock.Lock()
+	wasInactive := o.inactiveSet[element]
+	delete(o.inactiveSet, element)
+	o.lock.Unlock()
+	if wasInactive {
+		return element
+	}
+
+	// The element is still mounted elsewhere, so its old parent lets go of it
+	forgetter, ok := element.Parent().(childForgetter)
+	if !ok {
+		return nil
+	}
+	forgetter.forgetChild(element)
+	return element
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingWidget is a StatefulWidget whose state records every build
+type countingWidget struct {
+	BaseWidget
+	name  string
+	child Widget
+	log   *[]string
+	state *countingState
+}
+
+func (w *countingWidget) CreateState() State {
+	w.state = &countingState{}
+	return w.state
+}
+
+type countingState struct {
+	BaseState
+	builds int
+}
+
+func (s *countingState) Build(context BuildContext) Widget {
+	w := s.Widget().(*countingWidget)
+	s.builds++
+	if w.log != nil {
+		*w.log = append(*w.log, w.name)
+	}
+	return w.child
+}
+
+func TestBuildOwner_CoalescesSetState(t *testing.T) {
+	owner := NewBuildOwner()
+	w := &countingWidget{child: &MockWidget{}}
+	root := NewElement(w)
+	owner.MountRoot(root)
+	assert.Equal(t, 1, w.state.builds)
+
+	for i := 0; i < 100; i++ {
+		w.state.SetState(nil)
+	}
+	assert.True(t, owner.HasDirtyElements())
+
+	owner.BuildScope()
+	assert.Equal(t, 2, w.state.builds)
+	assert.False(t, owner.HasDirtyElements())
+
+	// Nothing left to do
+	assert.Equal(t, 0, owner.BuildScope())
+}
+
+func TestBuildOwner_RebuildsParentsFirst(t *testing.T) {
+	var log []string
+	child := &countingWidget{name: "child", child: &MockWidget{}, log: &log}
+	parent := &countingWidget{name: "parent", child: child, log: &log}
+
+	owner := NewBuildOwner()
+	root := NewElement(parent)
+	owner.MountRoot(root)
+	assert.Equal(t, 1, root.Children()[0].Depth())
+	log = nil
+
+	// Schedule the child first; the parent must still build before it
+	child.state.SetState(nil)
+	parent.state.SetState(nil)
+	owner.BuildScope()
+
+	assert.Equal(t, []string{"parent", "child"}, log)
+	assert.Equal(t, 2, child.state.builds)
+}
+
+func TestBuildOwner_ConcurrentSetState(t *testing.T) {
+	owner := NewBuildOwner()
+	var scheduled atomic.Int32
+	owner.OnBuildScheduled(func() {
+		scheduled.Add(1)
+	})
+
+	w := &countingWidget{child: &MockWidget{}}
+	owner.MountRoot(NewElement(w))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				w.state.SetState(nil)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Only the first request queues the element
+	assert.Equal(t, int32(1), scheduled.Load())
+
+	owner.BuildScope()
+	assert.Equal(t, 2, w.state.builds)
+}
+
+func TestBuildOwner_MountRootAssignsOwner(t *testing.T) {
+	owner := NewBuildOwner()
+	parent := &countingWidget{child: &MockWidget{}}
+	root := NewElement(parent)
+	owner.MountRoot(root)
+
+	assert.Equal(t, owner, root.Owner())
+	assert.Equal(t, 0, root.Depth())
+	for _, child := range root.Children() {
+		assert.Equal(t, owner, child.Owner())
+	}
+}
+
+func TestBuildOwner_Post(t *testing.T) {
+	t.Run("runs tasks in order on flush", func(t *testing.T) {
+		owner := NewBuildOwner()
+		var log []int
+		for i := 0; i < 3; i++ {
+			owner.Post(func() { log = append(log, i) })
+		}
+		assert.Empty(t, log)
+
+		assert.Equal(t, 3, owner.FlushPosted())
+		assert.Equal(t, []int{0, 1, 2}, log)
+		assert.Equal(t, 0, owner.FlushPosted())
+	})
+
+	t.Run("defers tasks posted while flushing", func(t *testing.T) {
+		owner := NewBuildOwner()
+		ran := false
+		owner.Post(func() {
+			owner.Post(func() { ran = true })
+		})
+
+		assert.Equal(t, 1, owner.FlushPosted())
+		assert.False(t, ran)
+		assert.Equal(t, 1, owner.FlushPosted())
+		assert.True(t, ran)
+	})
+
+	t.Run("marshals state updates from other goroutines", func(t *testing.T) {
+		owner := NewBuildOwner()
+		var scheduled atomic.Int32
+		owner.OnBuildScheduled(func() {
+			scheduled.Add(1)
+		})
+
+		w := &countingWidget{child: &MockWidget{}}
+		root := NewElement(w)
+		owner.MountRoot(root)
+		ctx := root.BuildContext()
+
+		counter := 0
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					ctx.Post(func() {
+						w.state.SetState(func() { counter++ })
+					})
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(1000), scheduled.Load())
+
+		owner.FlushPosted()
+		owner.BuildScope()
+		assert.Equal(t, 1000, counter)
+		assert.Equal(t, 2, w.state.builds)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "github.com/watzon/tide/pkg/core/color"
+
+// Button is a bordered label that runs a function when it is activated:
+// clicked, or Enter or Space pressed while it has focus. It shows as
+// focused with the focused style, which defaults to the style's
+// Focused(), as hovered in bold and flashes its colors swapped when
+// pressed. Hover is only seen when the terminal reports mouse motion.
+type Button struct {
+	BaseWidget
+	config controlConfig
+}
+
+// NewButton creates a button showing label that calls onPressed. A button
+// without onPressed is disabled.
+func NewButton(label string, onPressed func()) *Button {
+	return &Button{
+		BaseWidget: BaseWidget{
+			style: NewWidgetStyle().
+				WithBorder(BorderRounded, color.Gray, EdgeInsets{Top: 1, Right: 1, Bottom: 1, Left: 1}).
+				WithPadding(EdgeInsets{Left: 1, Right: 1}),
+		},
+		config: controlConfig{
+			label:    label,
+			center:   true,
+			activate: onPressed,
+			disabled: onPressed == nil,
+		},
+	}
+}
+
+// WithFocusNode sets the node that gives the button focus. Without one the
+// button creates its own.
+func (w *Button) WithFocusNode(node *FocusNode) *Button {
+	w.config.focusNode = node
+	return w
+}
+
+// WithDisabled sets whether the button is disabled. A disabled button is
+// drawn with the style's Disabled(), can't take focus and ignores input.
+func (w *Button) WithDisabled(disabled bool) *Button {
+	w.config.disabled = disabled || w.config.activate == nil
+	return w
+}
+
+// WithFocusedStyle sets the style the button has while it has focus
+func (w *Button) WithFocusedStyle(style WidgetStyle) *Button {
+	w.config.focusedStyle = &style
+	return w
+}
+
+func (w *Button) WithStyle(style WidgetStyle) *Button {
+	w.style = style
+	return w
+}
+
+func (w *Button) CreateState() State {
+	return &controlState{}
+}
+
+func (w *Button) controlConfig() controlConfig {
+	return w.config
+}
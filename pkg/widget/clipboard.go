@@ -0,0 +1,37 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "sync"
+
+// Clipboard reads and writes the text clipboard. *terminal.Terminal
+// implements it with the system clipboard.
+type Clipboard interface {
+	GetClipboard() (string, error)
+	SetClipboard(content string) error
+}
+
+// memoryClipboard keeps clipboard text in memory, for trees that aren't
+// given a system clipboard
+type memoryClipboard struct {
+	lock    sync.Mutex
+	content string
+}
+
+func (c *memoryClipboard) GetClipboard() (string, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.content, nil
+}
+
+func (c *memoryClipboard) SetClipboard(content string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.content = content
+	return nil
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"time"
+
+	"github.com/watzon/tide/pkg/core/color"
+)
+
+// pressedFlash is how long a control shows as pressed after it is
+// activated. Terminals don't report releasing a button in every mouse
+// mode, so the pressed state is a flash rather than lasting until then.
+const pressedFlash = 150 * time.Millisecond
+
+// controlConfig holds what Button, Checkbox, Switch and Radio are
+// configured with
+type controlConfig struct {
+	focusNode    *FocusNode
+	disabled     bool
+	focusedStyle *WidgetStyle
+	label        string
+	// indicator returns the glyphs shown before the label, using unicode
+	// characters or only ascii ones. Both must take the same width.
+	indicator func(unicode bool) string
+	// center centers the label in the control, as buttons do
+	center   bool
+	activate func()
+	// group is the group of a radio, which value selects
+	group *RadioGroup
+	value string
+}
+
+// control is implemented by the interactive controls
+type control interface {
+	StatefulWidget
+	GetStyle() WidgetStyle
+	controlConfig() controlConfig
+}
+
+// controlState tracks the focus, hover and pressed states of a control and
+// keeps a radio in its group
+type controlState struct {
+	BaseState
+	focus            focusBinding
+	hovered, pressed bool
+	// presses counts activations, so only the flash of the latest one ends
+	// the pressed state
+	presses     int
+	group       *RadioGroup
+	member      *radioMember
+	removeGroup func()
+	disposed    bool
+}
+
+func (s *controlState) InitState() {
+	s.bind(s.Widget().(control).controlConfig())
+}
+
+func (s *controlState) Dispose() {
+	s.disposed = true
+	s.focus.unbind()
+	s.leaveGroup()
+}
+
+func (s *controlState) Build(context BuildContext) Widget {
+	w := s.Widget().(control)
+	config := w.controlConfig()
+	focus := s.bind(config)
+	view := &controlView{
+		BaseWidget: BaseWidget{style: s.style(w.GetStyle(), config, focus)},
+		config:     config,
+		focus:      focus,
+		activate:   s.activate,
+		onHover:    s.hover,
+	}
+	if s.group != nil {
+		view.step = s.step
+	}
+	return view
+}
+
+// bind attaches the control's focus node, which can't take focus while the
+// control is disabled, and keeps a radio in its group. It returns the
+// focus node in use.
+func (s *controlState) bind(config controlConfig) *FocusNode {
+	focus := s.focus.bind(config.focusNode, s.Element(), s.changed)
+	focus.SetCanRequestFocus(!config.disabled)
+
+	if config.group != s.group {
+		s.leaveGroup()
+		if config.group != nil {
+			s.group = config.group
+			s.member = &radioMember{}
+			s.removeGroup = s.group.AddListener(s.changed)
+		}
+	}
+	if s.group != nil {
+		s.member.value, s.member.focus = config.value, focus
+		if config.disabled {
+			s.group.leave(s.member)
+		} else {
+			s.group.join(s.member)
+		}
+	}
+	return focus
+}
+
+func (s *controlState) leaveGroup() {
+	if s.group == nil {
+		return
+	}
+	s.group.leave(s.member)
+	s.removeGroup()
+	s.group, s.member, s.removeGroup = nil, nil, nil
+}
+
+func (s *controlState) changed() {
+	if !s.disposed {
+		s.SetState(nil)
+	}
+}
+
+// activate presses the control and runs what it does
+func (s *controlState) activate() {
+	config := s.Widget().(control).controlConfig()
+	if config.disabled {
+		return
+	}
+	s.press()
+	if config.activate != nil {
+		config.activate()
+	}
+}
+
+// press shows the control as pressed for a moment
+func (s *controlState) press() {
+	s.presses++
+	press := s.presses
+	post := s.Context().Post
+	s.SetState(func() {
+		s.pressed = true
+	})
+	time.AfterFunc(pressedFlash, func() {
+		post(func() {
+			if s.disposed || s.presses != press {
+				return
+			}
+			s.SetState(func() {
+				s.pressed = false
+			})
+		})
+	})
+}
+
+func (s *controlState) hover(hovered bool) {
+	if s.disposed || hovered == s.hovered {
+		return
+	}
+	s.SetState(func() {
+		s.hovered = hovered
+	})
+}
+
+// step moves a radio's selection along its group
+func (s *controlState) step(direction int) {
+	if s.group != nil {
+		s.group.step(s.member, direction)
+	}
+}
+
+// style returns the style the control is painted with in its current
+// state. Room is always kept for the border of the focused style, so
+// moving focus doesn't move anything.
+func (s *controlState) style(base WidgetStyle, config controlConfig, focus *FocusNode) WidgetStyle {
+	focused := base.Focused()
+	if config.focusedStyle != nil {
+		focused = *config.focusedStyle
+	}
+
+	style := base
+	switch {
+	case config.disabled:
+		style = base.Disabled()
+	case focus.HasFocus():
+		style = focused
+	}
+	style.BorderWidth = style.BorderWidth.Max(focused.BorderWidth)
+
+	switch {
+	case config.disabled:
+	case s.pressed:
+		style = pressedStyle(style)
+	case s.hovered:
+		style = style.WithBold(true)
+	}
+	return style
+}
+
+// pressedStyle swaps the colors of style, showing text on a transparent
+// background in black
+func pressedStyle(style WidgetStyle) WidgetStyle {
+	background := style.BackgroundColor
+	if background.A == 0 {
+		background = color.Black
+	}
+	return style.WithForeground(background).WithBackground(style.ForegroundColor)
+}
+
+// controlView is the widget a control builds, which creates the render
+// object painting it
+type controlView struct {
+	BaseWidget
+	config   controlConfig
+	focus    *FocusNode
+	activate func()
+	onHover  func(hovered bool)
+	step     func(direction int)
+}
+
+func (w *controlView) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *controlView) CreateRenderObject() RenderObject {
+	r := &RenderControl{}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *controlView) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderControl); ok {
+		r.style = w.style
+		r.config = w.config
+		r.focus = w.focus
+		r.activate = w.activate
+		r.onHover = w.onHover
+		r.step = w.step
+	}
+}
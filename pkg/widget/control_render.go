@@ -0,0 +1,117 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// RenderControl paints the indicator and label of a control inside its
+// box, and activates the control from keys and clicks
+type RenderControl struct {
+	BaseRenderBox
+	config   controlConfig
+	focus    *FocusNode
+	activate func()
+	onHover  func(hovered bool)
+	// step moves a radio along its group, and is nil for other controls
+	step func(direction int)
+}
+
+func (r *RenderControl) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	// The unicode and ascii indicators take the same width
+	content := geometry.Size{Width: grapheme.Width(r.content(true)), Height: 1}
+	r.size = constraints.Constrain(r.outerSize(content))
+	return r.size
+}
+
+// content returns the indicator and label the control shows
+func (r *RenderControl) content(unicode bool) string {
+	if r.config.indicator == nil {
+		return r.config.label
+	}
+	indicator := r.config.indicator(unicode)
+	if r.config.label == "" {
+		return indicator
+	}
+	return indicator + " " + r.config.label
+}
+
+func (r *RenderControl) Paint(context engine.RenderContext) {
+	r.PaintBackground(context)
+	r.PaintBorder(context)
+
+	rect := r.ContentRect()
+	if rect.IsEmpty() {
+		return
+	}
+	text := r.content(context.Capabilities().SupportsUnicode)
+	size := rect.Size()
+	x, y := rect.Min.X, rect.Min.Y+(size.Height-1)/2
+	if r.config.center {
+		x += max(0, (size.Width-grapheme.Width(text))/2)
+	}
+	context.PushClipRect(rect)
+	drawString(context, x, y, text, r.style)
+	context.PopClipRect()
+}
+
+// HandleEvent activates the control on Enter or Space while it has focus
+// and on a click, which also focuses it. The arrow keys move a radio along
+// its group.
+func (r *RenderControl) HandleEvent(event terminal.Event) bool {
+	if r.config.disabled {
+		return false
+	}
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+		return r.handleKey(ev)
+	case terminal.MouseEvent:
+		if ev.Motion || ev.Buttons&tcell.ButtonPrimary == 0 {
+			return false
+		}
+		if r.focus != nil {
+			r.focus.RequestFocus()
+		}
+		r.activate()
+		return true
+	}
+	return false
+}
+
+func (r *RenderControl) handleKey(ev terminal.KeyEvent) bool {
+	if ev.Key == tcell.KeyEnter || ev.Key == tcell.KeyRune && ev.Rune == ' ' {
+		r.activate()
+		return true
+	}
+	if r.step == nil {
+		return false
+	}
+	switch ev.Key {
+	case tcell.KeyUp, tcell.KeyLeft:
+		r.step(-1)
+	case tcell.KeyDown, tcell.KeyRight:
+		r.step(1)
+	default:
+		return false
+	}
+	return true
+}
+
+// HandleHover shows the control as hovered while the pointer is over it
+func (r *RenderControl) HandleHover(hovered bool) {
+	if r.onHover != nil {
+		r.onHover(hovered)
+	}
+}
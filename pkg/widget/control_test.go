@@ -0,0 +1,282 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// controlTree is a mounted tree of controls laid out at a fixed size
+type controlTree struct {
+	owner *BuildOwner
+	root  Element
+	size  geometry.Size
+}
+
+func mountControls(w Widget, width, height int) *controlTree {
+	tree := &controlTree{
+		owner: NewBuildOwner(),
+		root:  NewElement(w),
+		size:  geometry.Size{Width: width, Height: height},
+	}
+	tree.owner.MountRoot(tree.root)
+	tree.pump()
+	return tree
+}
+
+// pump runs posted work, rebuilds and lays the tree out again
+func (c *controlTree) pump() {
+	c.owner.FlushPosted()
+	c.owner.BuildScope()
+	LayoutIfNeeded(c.root.RenderObject(), NewConstraints(geometry.Size{}, c.size))
+}
+
+// send dispatches events to the tree as the app does, pumping after each
+func (c *controlTree) send(events ...terminal.Event) {
+	for _, event := range events {
+		c.owner.FocusManager().DispatchEvent(c.root.RenderObject(), event)
+		c.pump()
+	}
+}
+
+// lines paints the tree and returns its lines without trailing space
+func (c *controlTree) lines() []string {
+	m := NewMockRenderContext()
+	c.root.RenderObject().Paint(m)
+	lines := make([]string, c.root.RenderObject().Size().Height)
+	for y := range lines {
+		lines[y] = strings.TrimRight(screenRow(m, y, c.size.Width), " ")
+	}
+	return lines
+}
+
+// control returns the render object of the only control in the tree
+func (c *controlTree) control() *RenderControl {
+	return c.root.RenderObject().(*RenderControl)
+}
+
+var (
+	enterKey = keyOf(tcell.KeyEnter)
+	spaceKey = runeOf(' ')
+)
+
+func TestButton(t *testing.T) {
+	presses := 0
+	tree := mountControls(NewButton("OK", func() { presses++ }), 10, 5)
+	r := tree.control()
+
+	t.Run("paints a bordered label", func(t *testing.T) {
+		assert.Equal(t, geometry.Size{Width: 6, Height: 3}, r.Size())
+		assert.Equal(t, []string{"╭────╮", "│ OK │", "╰────╯"}, tree.lines())
+	})
+
+	t.Run("keys need focus", func(t *testing.T) {
+		tree.send(enterKey, spaceKey)
+		assert.Equal(t, 0, presses)
+
+		assert.True(t, tree.owner.FocusManager().NextFocus())
+		tree.pump()
+		tree.send(enterKey, spaceKey, runeOf('x'))
+		assert.Equal(t, 2, presses)
+		assert.Equal(t, []string{"┌────┐", "│ OK │", "└────┘"}, tree.lines(), "focus draws the Focused() border")
+	})
+
+	t.Run("a click focuses and presses", func(t *testing.T) {
+		r.focus.Unfocus()
+		tree.send(click(2, 1))
+		assert.Equal(t, 3, presses)
+		assert.True(t, r.focus.HasFocus())
+
+		tree.send(terminal.MouseEvent{Position: geometry.Point{X: 2, Y: 1}, Buttons: tcell.ButtonPrimary, Motion: true})
+		assert.Equal(t, 3, presses, "dragging over the button doesn't press it")
+	})
+
+	t.Run("the pressed state flashes", func(t *testing.T) {
+		tree.send(enterKey)
+		assert.Equal(t, color.White, r.Style().BackgroundColor)
+		assert.Equal(t, color.Black, r.Style().ForegroundColor)
+
+		time.Sleep(pressedFlash + 50*time.Millisecond)
+		tree.pump()
+		assert.Equal(t, color.White, r.Style().ForegroundColor)
+	})
+
+	t.Run("hover shows in bold", func(t *testing.T) {
+		r.HandleHover(true)
+		tree.pump()
+		assert.True(t, r.Style().Bold)
+		r.HandleHover(false)
+		tree.pump()
+		assert.False(t, r.Style().Bold)
+	})
+}
+
+func TestButton_Disabled(t *testing.T) {
+	tests := []struct {
+		name   string
+		button *Button
+	}{
+		{"without onPressed", NewButton("Go", nil)},
+		{"with WithDisabled", NewButton("Go", func() { t.Error("disabled button pressed") }).WithDisabled(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := mountControls(tt.button, 10, 3)
+			r := tree.control()
+
+			assert.False(t, tree.owner.FocusManager().NextFocus())
+			assert.False(t, DispatchEvent(r, click(2, 1)))
+			assert.False(t, r.focus.HasFocus())
+
+			r.HandleHover(true)
+			tree.pump()
+			assert.Equal(t, NewButton("", nil).GetStyle().Disabled().ForegroundColor, r.Style().ForegroundColor)
+			assert.False(t, r.Style().Bold)
+		})
+	}
+}
+
+// checkboxHost shows a checkbox and a switch whose values its state holds
+type checkboxHost struct {
+	BaseWidget
+}
+
+func (w *checkboxHost) CreateState() State {
+	return &checkboxHostState{}
+}
+
+type checkboxHostState struct {
+	BaseState
+	checked, on bool
+}
+
+func (s *checkboxHostState) Build(context BuildContext) Widget {
+	plain := NewWidgetStyle()
+	return NewColumn(
+		NewCheckbox("Agree", s.checked, func(checked bool) {
+			s.SetState(func() { s.checked = checked })
+		}).WithFocusedStyle(plain),
+		NewSwitch("Wi-Fi", s.on, func(on bool) {
+			s.SetState(func() { s.on = on })
+		}).WithFocusedStyle(plain),
+	)
+}
+
+func TestCheckboxAndSwitch(t *testing.T) {
+	tree := mountControls(&checkboxHost{}, 20, 2)
+	assert.Equal(t, []string{"[ ] Agree", "[○ ] Wi-Fi"}, tree.lines())
+
+	tree.send(click(0, 0), click(1, 1))
+	assert.Equal(t, []string{"[✓] Agree", "[ ●] Wi-Fi"}, tree.lines())
+
+	tree.owner.FocusManager().PreviousFocus()
+	tree.pump()
+	tree.send(spaceKey)
+	assert.Equal(t, []string{"[ ] Agree", "[ ●] Wi-Fi"}, tree.lines())
+}
+
+func TestCheckbox_KeepsRoomForFocus(t *testing.T) {
+	tree := mountControls(NewCheckbox("Agree", true, func(bool) {}), 20, 5)
+	r := tree.control()
+	assert.Equal(t, geometry.Size{Width: 11, Height: 3}, r.Size())
+	assert.Equal(t, []string{"", " [✓] Agree", ""}, tree.lines())
+
+	r.focus.RequestFocus()
+	tree.pump()
+	assert.Equal(t, geometry.Size{Width: 11, Height: 3}, r.Size())
+	assert.Equal(t, []string{"┌─────────┐", "│[✓] Agree│", "└─────────┘"}, tree.lines())
+}
+
+// radioHost shows a column of radios of one group
+type radioHost struct {
+	BaseWidget
+	group    *RadioGroup
+	disabled string
+}
+
+func (w *radioHost) CreateState() State {
+	return &radioHostState{}
+}
+
+type radioHostState struct {
+	BaseState
+}
+
+func (s *radioHostState) Build(context BuildContext) Widget {
+	w := s.Widget().(*radioHost)
+	plain := NewWidgetStyle()
+	var radios []Widget
+	for _, value := range []string{"a", "b", "c", "d"} {
+		radios = append(radios, NewRadio(strings.ToUpper(value), value, w.group).
+			WithDisabled(value == w.disabled).
+			WithFocusedStyle(plain))
+	}
+	return NewColumn(radios...)
+}
+
+func TestRadio(t *testing.T) {
+	group := NewRadioGroup("b")
+	changes := 0
+	group.AddListener(func() { changes++ })
+	tree := mountControls(&radioHost{group: group, disabled: "c"}, 10, 4)
+
+	assert.Equal(t, []string{"( ) A", "(•) B", "( ) C", "( ) D"}, tree.lines())
+
+	t.Run("a click selects", func(t *testing.T) {
+		tree.send(click(0, 0))
+		assert.Equal(t, "a", group.Value())
+		assert.Equal(t, []string{"(•) A", "( ) B", "( ) C", "( ) D"}, tree.lines())
+
+		tree.send(click(0, 2))
+		assert.Equal(t, "a", group.Value(), "disabled radios can't be selected")
+	})
+
+	t.Run("arrows move along the group", func(t *testing.T) {
+		var values []string
+		for _, key := range []tcell.Key{tcell.KeyDown, tcell.KeyDown, tcell.KeyDown, tcell.KeyUp, tcell.KeyLeft} {
+			tree.send(keyOf(key))
+			values = append(values, group.Value())
+		}
+		assert.Equal(t, []string{"b", "d", "a", "d", "b"}, values)
+
+		focused := tree.owner.FocusManager().Focused()
+		assert.Equal(t, "b", findRadioMember(group, focused).value, "focus follows the selection")
+		assert.Equal(t, 6, changes)
+	})
+
+	t.Run("selecting from outside rebuilds", func(t *testing.T) {
+		group.Select("c")
+		tree.pump()
+		assert.Equal(t, []string{"( ) A", "( ) B", "(•) C", "( ) D"}, tree.lines())
+	})
+}
+
+func findRadioMember(group *RadioGroup, node *FocusNode) *radioMember {
+	for _, m := range group.radios {
+		if m.focus == node {
+			return m
+		}
+	}
+	return nil
+}
+
+func TestRadio_LeavesGroupWhenUnmounted(t *testing.T) {
+	group := NewRadioGroup("")
+	tree := mountControls(&radioHost{group: group}, 10, 4)
+	assert.Len(t, group.radios, 4)
+
+	tree.root.Unmount()
+	assert.Empty(t, group.radios)
+	assert.Empty(t, group.listeners)
+}
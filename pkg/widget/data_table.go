@@ -0,0 +1,252 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "github.com/watzon/tide/pkg/core/color"
+
+// SelectionMode is how many rows of a table can be selected at once
+type SelectionMode int
+
+const (
+	SelectionNone SelectionMode = iota
+	SelectionSingle
+	SelectionMulti
+)
+
+// DataColumn describes a column of a DataTable
+type DataColumn struct {
+	Label string
+	// Width sizes the column like a grid track. Flexible columns share
+	// the width left over and cut their text off rather than growing.
+	Width    TrackSize
+	Sortable bool
+}
+
+// NewDataColumn creates an auto sized column headed by label
+func NewDataColumn(label string) DataColumn {
+	return DataColumn{Label: label, Width: TrackAuto()}
+}
+
+// WithWidth sets how the column is sized
+func (c DataColumn) WithWidth(width TrackSize) DataColumn {
+	c.Width = width
+	return c
+}
+
+// WithSortable sets whether clicking the column's header sorts by it
+func (c DataColumn) WithSortable(sortable bool) DataColumn {
+	c.Sortable = sortable
+	return c
+}
+
+// DataCellBuilder returns the text of the cell at row and column
+type DataCellBuilder func(row, column int) string
+
+// DataTable shows rows of text under a header naming its columns. Cells
+// are only asked for while their row is in view, so a table can hold any
+// number of rows. Auto sized columns fit their header and the first rows;
+// text that doesn't fit its cell is cut off with an ellipsis. Dragging the
+// border to the right of a header resizes its column.
+//
+// The table scrolls with PgUp/PgDn, Home/End and the mouse wheel, and the
+// left and right keys scroll a column at a time. With a selection mode the
+// up and down keys move a cursor row, and space or a click toggles rows in
+// multi selection. Keys only reach the table while it has focus, which a
+// click gives it. Clicking a sortable header asks for the rows to be
+// sorted; the table shows whichever sort it is given.
+type DataTable struct {
+	BaseWidget
+	columns        []DataColumn
+	rowCount       int
+	cell           DataCellBuilder
+	controller     *ScrollController
+	focusNode      *FocusNode
+	sortColumn     int
+	sortAscending  bool
+	onSort         func(column int, ascending bool)
+	selectionMode  SelectionMode
+	onSelect       func(rows []int)
+	onResize       func(column, width int)
+	stickyHeader   bool
+	stickyColumn   bool
+	headerStyle    WidgetStyle
+	selectionStyle WidgetStyle
+	cursorStyle    WidgetStyle
+}
+
+// NewDataTable creates a table of rowCount rows whose cells are returned by
+// cell
+func NewDataTable(columns []DataColumn, rowCount int, cell DataCellBuilder) *DataTable {
+	return &DataTable{
+		BaseWidget:     BaseWidget{style: NewWidgetStyle()},
+		columns:        columns,
+		rowCount:       max(0, rowCount),
+		cell:           cell,
+		sortColumn:     -1,
+		stickyHeader:   true,
+		headerStyle:    NewWidgetStyle().WithBold(true),
+		selectionStyle: NewWidgetStyle().WithBackground(color.Blue),
+		cursorStyle:    NewWidgetStyle().WithBackground(color.Gray),
+	}
+}
+
+// WithController sets the controller that reads and changes the vertical
+// offset. Without one the table creates its own.
+func (t *DataTable) WithController(controller *ScrollController) *DataTable {
+	t.controller = controller
+	return t
+}
+
+// WithFocusNode sets the node that gives the table focus. Without one the
+// table creates its own.
+func (t *DataTable) WithFocusNode(node *FocusNode) *DataTable {
+	t.focusNode = node
+	return t
+}
+
+// WithSort marks the rows as sorted by column, which shows in its header.
+// A negative column means the rows aren't sorted.
+func (t *DataTable) WithSort(column int, ascending bool) *DataTable {
+	t.sortColumn = column
+	t.sortAscending = ascending
+	return t
+}
+
+// WithOnSort sets the function called when a sortable header is clicked.
+// Clicking the sorted column again reverses the order.
+func (t *DataTable) WithOnSort(onSort func(column int, ascending bool)) *DataTable {
+	t.onSort = onSort
+	return t
+}
+
+// WithSelectionMode sets how many rows can be selected
+func (t *DataTable) WithSelectionMode(mode SelectionMode) *DataTable {
+	t.selectionMode = mode
+	return t
+}
+
+// WithOnSelectionChanged sets the function called with the selected rows,
+// in order, whenever they change
+func (t *DataTable) WithOnSelectionChanged(onSelect func(rows []int)) *DataTable {
+	t.onSelect = onSelect
+	return t
+}
+
+// WithOnColumnResize sets the function called when a column is resized by
+// dragging its border
+func (t *DataTable) WithOnColumnResize(onResize func(column, width int)) *DataTable {
+	t.onResize = onResize
+	return t
+}
+
+// WithStickyHeader sets whether the header stays in view while the rows
+// scroll. It does by default.
+func (t *DataTable) WithStickyHeader(sticky bool) *DataTable {
+	t.stickyHeader = sticky
+	return t
+}
+
+// WithStickyFirstColumn sets whether the first column stays in view while
+// the other columns scroll sideways
+func (t *DataTable) WithStickyFirstColumn(sticky bool) *DataTable {
+	t.stickyColumn = sticky
+	return t
+}
+
+// WithHeaderStyle sets the style of the header row
+func (t *DataTable) WithHeaderStyle(style WidgetStyle) *DataTable {
+	t.headerStyle = style
+	return t
+}
+
+// WithSelectionStyle sets the style of selected rows
+func (t *DataTable) WithSelectionStyle(style WidgetStyle) *DataTable {
+	t.selectionStyle = style
+	return t
+}
+
+func (t *DataTable) WithStyle(style WidgetStyle) *DataTable {
+	t.style = style
+	return t
+}
+
+func (t *DataTable) CreateState() State {
+	return &dataTableState{}
+}
+
+// dataTableState repaints the table whenever it scrolls or its selection
+// changes
+type dataTableState struct {
+	BaseState
+	binding scrollBinding
+	focus   focusBinding
+}
+
+func (s *dataTableState) InitState() {
+	s.bind()
+	s.focus.bind(s.Widget().(*DataTable).focusNode, s.Element(), s.changed)
+}
+
+func (s *dataTableState) Dispose() {
+	s.binding.unbind()
+	s.focus.unbind()
+}
+
+func (s *dataTableState) Build(context BuildContext) Widget {
+	t := s.Widget().(*DataTable)
+	return &dataTableView{
+		table:      t,
+		controller: s.bind(),
+		focus:      s.focus.bind(t.focusNode, s.Element(), s.changed),
+		onChange:   s.changed,
+	}
+}
+
+// bind listens to the widget's controller and returns it
+func (s *dataTableState) bind() *ScrollController {
+	return s.binding.bind(s.Widget().(*DataTable).controller, s.changed, s.Context().Post)
+}
+
+func (s *dataTableState) changed() {
+	s.SetState(nil)
+}
+
+// dataTableView is the widget the table builds, which creates the render
+// object painting it
+type dataTableView struct {
+	BaseWidget
+	table      *DataTable
+	controller *ScrollController
+	focus      *FocusNode
+	onChange   func()
+}
+
+func (w *dataTableView) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *dataTableView) CreateRenderObject() RenderObject {
+	r := &RenderDataTable{
+		cell:     newLineRenderObject(),
+		resized:  make(map[int]int),
+		selected: make(map[int]bool),
+		resizing: -1,
+	}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *dataTableView) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderDataTable); ok {
+		r.style = w.table.style
+		r.table = w.table
+		r.controller = w.controller
+		r.focus = w.focus
+		r.onChange = w.onChange
+		r.items = nil
+		r.cursor = max(0, min(r.cursor, w.table.rowCount-1))
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// HandleEvent moves the cursor, scrolls, sorts and resizes columns. Keys
+// are only handled while the table has focus.
+func (r *RenderDataTable) HandleEvent(event terminal.Event) bool {
+	if r.controller == nil {
+		return false
+	}
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+		return r.handleKey(ev)
+	case terminal.MouseEvent:
+		return r.handleMouse(ev)
+	}
+	return false
+}
+
+func (r *RenderDataTable) handleKey(ev terminal.KeyEvent) bool {
+	mode := r.table.selectionMode
+	switch {
+	case ev.Key == tcell.KeyLeft:
+		return r.scrollColumns(-1)
+	case ev.Key == tcell.KeyRight:
+		return r.scrollColumns(1)
+	case ev.Key == tcell.KeyRune && ev.Rune == ' ' && mode == SelectionMulti:
+		return r.toggle(r.cursor)
+	}
+
+	if mode != SelectionNone {
+		if target, ok := r.cursorTarget(ev.Key); ok {
+			return r.moveCursor(target)
+		}
+	}
+	return scrollForEvent(r.controller, AxisVertical, ev)
+}
+
+// cursorTarget returns the row a key moves the cursor to
+func (r *RenderDataTable) cursorTarget(key tcell.Key) (int, bool) {
+	page := max(1, r.bodyHeight())
+	switch key {
+	case tcell.KeyUp:
+		return r.cursor - 1, true
+	case tcell.KeyDown:
+		return r.cursor + 1, true
+	case tcell.KeyPgUp:
+		return r.cursor - page, true
+	case tcell.KeyPgDn:
+		return r.cursor + page, true
+	case tcell.KeyHome:
+		return 0, true
+	case tcell.KeyEnd:
+		return r.table.rowCount - 1, true
+	}
+	return 0, false
+}
+
+// handleMouse scrolls on the wheel, resizes a column while its border is
+// dragged, and focuses the table for a click on its header or rows
+func (r *RenderDataTable) handleMouse(ev terminal.MouseEvent) bool {
+	switch {
+	case ev.Buttons&(tcell.WheelLeft|tcell.WheelRight) != 0:
+		return r.scrollSideways(wheelDelta(ev.Buttons))
+	case ev.Buttons&(tcell.WheelUp|tcell.WheelDown) != 0:
+		return scrollForEvent(r.controller, AxisVertical, ev)
+	case ev.Buttons&tcell.ButtonPrimary == 0:
+		return false
+	case ev.Motion:
+		return r.drag(ev.Position.X)
+	}
+
+	r.resizing = -1
+	row, ok := r.rowAtLine(ev.Position.Y)
+	if !ok || ev.Position.X >= r.viewWidth() {
+		return false
+	}
+	if r.focus != nil {
+		r.focus.RequestFocus()
+	}
+	if row == headerRow {
+		return r.clickHeader(ev.Position.X)
+	}
+	if r.table.selectionMode == SelectionNone {
+		return false
+	}
+	moved := r.moveCursor(row)
+	if r.table.selectionMode == SelectionMulti {
+		return r.toggle(row) || moved
+	}
+	return moved
+}
+
+// clickHeader starts resizing when a column border is clicked, and
+// otherwise sorts by a sortable column
+func (r *RenderDataTable) clickHeader(x int) bool {
+	if column := r.borderAt(x); column >= 0 {
+		r.resizing = column
+		r.dragStart, r.dragWidth = x, r.widths[column]
+		return true
+	}
+
+	column := r.columnAt(x)
+	t := r.table
+	if column < 0 || !t.columns[column].Sortable || t.onSort == nil {
+		return false
+	}
+	t.onSort(column, !(column == t.sortColumn && t.sortAscending))
+	return true
+}
+
+// columnAt returns the column shown at x, or -1 over a gap
+func (r *RenderDataTable) columnAt(x int) int {
+	for column := range r.widths {
+		start := r.columnX(column)
+		if x >= start && x < start+r.widths[column] && r.visibleAt(column, x) {
+			return column
+		}
+	}
+	return -1
+}
+
+// borderAt returns the column whose right border is at x, or -1
+func (r *RenderDataTable) borderAt(x int) int {
+	for column := range r.widths {
+		if x == r.columnX(column)+r.widths[column] && r.visibleAt(column, x) {
+			return column
+		}
+	}
+	return -1
+}
+
+// visibleAt reports whether a column may paint at x, rather than passing
+// underneath the sticky column
+func (r *RenderDataTable) visibleAt(column, x int) bool {
+	return r.columnClip(column, 0).Contains(geometry.Point{X: x})
+}
+
+// drag resizes the column whose border is held to follow the pointer
+func (r *RenderDataTable) drag(x int) bool {
+	if r.resizing < 0 {
+		return false
+	}
+	width := max(1, r.dragWidth+x-r.dragStart)
+	if width == r.resized[r.resizing] {
+		return true
+	}
+	r.resized[r.resizing] = width
+	if r.table.onResize != nil {
+		r.table.onResize(r.resizing, width)
+	}
+	r.changed()
+	return true
+}
+
+// scrollColumns scrolls sideways until the next or previous column is at
+// the left edge of the scrolled area
+func (r *RenderDataTable) scrollColumns(direction int) bool {
+	first := 0
+	if r.table.stickyColumn {
+		first = 1
+	}
+	if first >= len(r.offsets) {
+		return false
+	}
+
+	base := r.offsets[first]
+	target := r.scrollX
+	for column := first; column < len(r.offsets); column++ {
+		start := r.offsets[column] - base
+		if direction > 0 && start > r.scrollX {
+			target = start
+			break
+		}
+		if direction < 0 && start < r.scrollX {
+			target = start
+		}
+	}
+	return r.scrollSideways(target - r.scrollX)
+}
+
+// scrollSideways scrolls the columns by delta cells, reporting whether they
+// moved
+func (r *RenderDataTable) scrollSideways(delta int) bool {
+	scrollX := max(0, min(r.scrollX+delta, r.maxScrollX()))
+	if scrollX == r.scrollX {
+		return false
+	}
+	r.scrollX = scrollX
+	r.changed()
+	return true
+}
+
+// moveCursor moves the cursor to row, clamped to the rows there are, and
+// scrolls it into view. A single selection follows the cursor.
+func (r *RenderDataTable) moveCursor(row int) bool {
+	if r.table.rowCount == 0 {
+		return false
+	}
+	row = max(0, min(row, r.table.rowCount-1))
+	if row == r.cursor && (r.table.selectionMode != SelectionSingle || r.selected[row]) {
+		return false
+	}
+
+	r.cursor = row
+	line := row
+	if !r.table.stickyHeader {
+		line++
+	}
+	r.controller.ShowRange(line, 1)
+	if r.table.selectionMode == SelectionSingle {
+		r.selected = map[int]bool{row: true}
+		r.selectionChanged()
+	}
+	r.changed()
+	return true
+}
+
+// toggle adds row to or removes it from a multi selection
+func (r *RenderDataTable) toggle(row int) bool {
+	if row < 0 || row >= r.table.rowCount {
+		return false
+	}
+	if r.selected[row] {
+		delete(r.selected, row)
+	} else {
+		r.selected[row] = true
+	}
+	r.selectionChanged()
+	r.changed()
+	return true
+}
+
+// isSelected reports whether row is selected
+func (r *RenderDataTable) isSelected(row int) bool {
+	return r.selected[row]
+}
+
+// Selected returns the selected rows in order
+func (r *RenderDataTable) Selected() []int {
+	rows := make([]int, 0, len(r.selected))
+	for row := range r.selected {
+		if row < r.table.rowCount {
+			rows = append(rows, row)
+		}
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+func (r *RenderDataTable) selectionChanged() {
+	if r.table.onSelect != nil {
+		r.table.onSelect(r.Selected())
+	}
+}
+
+func (r *RenderDataTable) changed() {
+	if r.onChange != nil {
+		r.onChange()
+	}
+}
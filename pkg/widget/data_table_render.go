@@ -0,0 +1,308 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// autoSizeRows is how many rows, from the top, auto sized columns are
+// measured against, so sizing never visits every row
+const autoSizeRows = 100
+
+// headerRow stands for the header where a row index is expected
+const headerRow = -1
+
+// RenderDataTable paints the rows of a table that are in view, one cell at
+// a time through a reused TextRenderObject
+type RenderDataTable struct {
+	BaseRenderObject
+	table      *DataTable
+	controller *ScrollController
+	focus      *FocusNode
+	// onChange asks for the table to be painted again
+	onChange func()
+
+	// items are the measured content widths of the columns
+	items   []trackItem
+	widths  []int
+	offsets []int
+	resized map[int]int
+	// scrollX is how far the columns are scrolled sideways
+	scrollX int
+	bar     bool
+
+	cursor   int
+	selected map[int]bool
+
+	// resizing is the column whose border is being dragged, or -1
+	resizing             int
+	dragStart, dragWidth int
+	cell                 *TextRenderObject
+}
+
+func (r *RenderDataTable) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	lines := r.table.rowCount + 1
+	height := constraints.Constrain(geometry.Size{Height: lines}).Height
+	r.bar = lines > height && constraints.MaxSize.Width > 1
+
+	available := deflateExtent(constraints.MaxSize.Width, r.barWidth())
+	r.widths = sizeTracks(r.tracks(), r.contentItems(), available, 1)
+	r.offsets = trackOffsets(r.widths, 1)
+	r.size = constraints.Constrain(geometry.Size{Width: r.tableWidth() + r.barWidth(), Height: height})
+
+	r.controller.updateExtents(r.bodyHeight(), r.contentLines())
+	r.scrollX = max(0, min(r.scrollX, r.maxScrollX()))
+	return r.size
+}
+
+// tracks returns the size of each column, with resized columns fixed at
+// the width they were dragged to
+func (r *RenderDataTable) tracks() []TrackSize {
+	tracks := make([]TrackSize, len(r.table.columns))
+	for i, column := range r.table.columns {
+		tracks[i] = column.Width
+		if width, ok := r.resized[i]; ok {
+			tracks[i] = TrackFixed(width)
+		}
+	}
+	return tracks
+}
+
+// contentItems measures every header, and the first rows of inflexible
+// columns. Flexible columns cut their text off instead of growing for it.
+func (r *RenderDataTable) contentItems() []trackItem {
+	if r.items != nil {
+		return r.items
+	}
+
+	t := r.table
+	r.items = make([]trackItem, len(t.columns))
+	for i, column := range t.columns {
+		// Leave room for the sort indicator
+		width := textWidth(column.Label)
+		if column.Sortable {
+			width += 2
+		}
+		if !column.Width.isFlexible() && t.cell != nil {
+			for row := 0; row < min(t.rowCount, autoSizeRows); row++ {
+				width = max(width, textWidth(t.cell(row, i)))
+			}
+		}
+		r.items[i] = trackItem{start: i, span: 1, size: width}
+	}
+	return r.items
+}
+
+func (r *RenderDataTable) barWidth() int {
+	if r.bar {
+		return 1
+	}
+	return 0
+}
+
+// tableWidth returns the width of every column and the gaps between them
+func (r *RenderDataTable) tableWidth() int {
+	return trackExtent(r.widths, 0, len(r.widths), 1)
+}
+
+// viewWidth returns the width the columns are shown in
+func (r *RenderDataTable) viewWidth() int {
+	return max(0, r.size.Width-r.barWidth())
+}
+
+func (r *RenderDataTable) maxScrollX() int {
+	return max(0, r.tableWidth()-r.viewWidth())
+}
+
+// headerHeight returns the lines kept for the sticky header
+func (r *RenderDataTable) headerHeight() int {
+	if r.table.stickyHeader {
+		return 1
+	}
+	return 0
+}
+
+// bodyHeight returns the lines the scrolled content is shown in
+func (r *RenderDataTable) bodyHeight() int {
+	return max(0, r.size.Height-r.headerHeight())
+}
+
+// contentLines returns the lines that scroll: the rows, and the header
+// when it isn't sticky
+func (r *RenderDataTable) contentLines() int {
+	return r.table.rowCount + 1 - r.headerHeight()
+}
+
+// rowAtLine returns the row shown on line y of the table, headerRow for the
+// header, or false when the line is past the last row
+func (r *RenderDataTable) rowAtLine(y int) (int, bool) {
+	top := r.headerHeight()
+	if y < top {
+		return headerRow, y >= 0
+	}
+	row := r.controller.Offset() + y - top
+	if !r.table.stickyHeader {
+		row--
+	}
+	return row, row < r.table.rowCount
+}
+
+// stickyWidth returns the width kept for the sticky first column and its
+// gap
+func (r *RenderDataTable) stickyWidth() int {
+	if !r.table.stickyColumn || len(r.widths) == 0 {
+		return 0
+	}
+	return r.widths[0] + 1
+}
+
+// columnX returns where a column starts in the table's coordinates
+func (r *RenderDataTable) columnX(column int) int {
+	if column == 0 && r.table.stickyColumn {
+		return 0
+	}
+	return r.offsets[column] - r.scrollX
+}
+
+// columnClip returns the part of a line a column may paint in, so
+// scrolled columns pass underneath the sticky one
+func (r *RenderDataTable) columnClip(column, y int) geometry.Rect {
+	left := 0
+	if column > 0 {
+		left = r.stickyWidth()
+	}
+	return geometry.Rect{
+		Min: geometry.Point{X: left, Y: y},
+		Max: geometry.Point{X: max(left, r.viewWidth()), Y: y + 1},
+	}
+}
+
+func (r *RenderDataTable) Paint(context engine.RenderContext) {
+	unicode := context.Capabilities().SupportsUnicode
+	for y := 0; y < r.size.Height; y++ {
+		if row, ok := r.rowAtLine(y); ok {
+			r.paintLine(context, row, y, unicode)
+		}
+	}
+
+	if r.bar {
+		context.PushOffset(geometry.Point{Y: r.headerHeight()})
+		size := geometry.Size{Width: r.size.Width, Height: r.bodyHeight()}
+		paintScrollbar(context, AxisVertical, size, r.controller, r.style)
+		context.PopOffset()
+	}
+}
+
+// paintLine paints the header or a row on line y
+func (r *RenderDataTable) paintLine(context engine.RenderContext, row, y int, unicode bool) {
+	s := r.rowStyle(row)
+	paintBackground(context, s, geometry.NewRect(0, y, r.viewWidth(), 1))
+
+	for column := range r.table.columns {
+		clip := r.columnClip(column, y)
+		x := r.columnX(column)
+		text := r.cellText(row, column, unicode)
+		r.paintCell(context, text, geometry.NewRect(x, y, r.widths[column], 1), clip, s, unicode)
+
+		if row == headerRow {
+			separator := '|'
+			if unicode {
+				separator = '│'
+			}
+			end := geometry.Point{X: x + r.widths[column], Y: y}
+			if clip.Contains(end) {
+				context.DrawCell(end.X, end.Y, separator, s.ForegroundColor, s.BackgroundColor)
+			}
+		}
+	}
+}
+
+// rowStyle returns the style a row, or the header, is painted with
+func (r *RenderDataTable) rowStyle(row int) WidgetStyle {
+	switch {
+	case row == headerRow:
+		return r.table.headerStyle
+	case r.isSelected(row):
+		return r.table.selectionStyle
+	case r.table.selectionMode == SelectionMulti && row == r.cursor:
+		return r.table.cursorStyle
+	}
+	return r.style
+}
+
+// cellText returns the text of a cell, with a sort indicator after the
+// label of the sorted column
+func (r *RenderDataTable) cellText(row, column int, unicode bool) string {
+	t := r.table
+	if row != headerRow {
+		if t.cell == nil {
+			return ""
+		}
+		return t.cell(row, column)
+	}
+	if column != t.sortColumn {
+		return t.columns[column].Label
+	}
+
+	up, down := "▲", "▼"
+	if !unicode {
+		up, down = "^", "v"
+	}
+	if t.sortAscending {
+		return t.columns[column].Label + " " + up
+	}
+	return t.columns[column].Label + " " + down
+}
+
+// paintCell lays out the shared text render object for one cell and paints
+// it within clip
+func (r *RenderDataTable) paintCell(context engine.RenderContext, text string, rect, clip geometry.Rect, s WidgetStyle, unicode bool) {
+	width := rect.Size().Width
+	r.cell.style = s
+	r.cell.content = ellipsize(text, width, unicode)
+	r.cell.Layout(NewConstraints(geometry.Size{Width: width, Height: 1}, geometry.Size{Width: width, Height: 1}))
+
+	context.PushClipRect(clip)
+	context.PushOffset(rect.Min)
+	r.cell.Paint(context)
+	context.PopOffset()
+	context.PopClipRect()
+}
+
+// ellipsize cuts the first line of text down to width cells, ending it
+// with an ellipsis when anything was cut off
+func ellipsize(text string, width int, unicode bool) string {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+
+	ellipsis := []rune("…")
+	if !unicode {
+		ellipsis = []rune("...")
+	}
+	if width <= len(ellipsis) {
+		return string(ellipsis[:max(0, width)])
+	}
+	return string(runes[:width-len(ellipsis)]) + string(ellipsis)
+}
+
+// textWidth returns the cells taken by the first line of text
+func textWidth(text string) int {
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[:i]
+	}
+	return utf8.RuneCountInString(text)
+}
@@ -0,0 +1,263 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// sampleTable creates a table of rows with a fixed, an auto and a
+// flexible column, counting the cells asked for by column
+func sampleTable(rows int, calls map[int]int) *DataTable {
+	columns := []DataColumn{
+		NewDataColumn("ID").WithWidth(TrackFixed(4)),
+		NewDataColumn("Name").WithSortable(true),
+		NewDataColumn("Note").WithWidth(TrackFr(1)),
+	}
+	return NewDataTable(columns, rows, func(row, column int) string {
+		calls[column]++
+		switch column {
+		case 0:
+			return fmt.Sprint(row)
+		case 1:
+			return fmt.Sprintf("name%d", row)
+		}
+		return strings.Repeat("note", 10)
+	})
+}
+
+// layoutDataTable mounts a table, focuses it and lays it out in a viewport
+// of the given size
+func layoutDataTable(table *DataTable, width, height int) (*BuildOwner, *RenderDataTable) {
+	owner := NewBuildOwner()
+	root := NewElement(table)
+	owner.MountRoot(root)
+	r := root.RenderObject().(*RenderDataTable)
+	r.focus.RequestFocus()
+	owner.BuildScope()
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	return owner, r
+}
+
+// paintTable paints a table and returns its rows as text
+func paintTable(r *RenderDataTable) []string {
+	m := NewMockRenderContext()
+	r.Paint(m)
+	lines := make([]string, r.Size().Height)
+	for y := range lines {
+		lines[y] = screenRow(m, y, r.Size().Width)
+	}
+	return lines
+}
+
+func TestDataTable_Layout(t *testing.T) {
+	calls := map[int]int{}
+	_, r := layoutDataTable(sampleTable(100_000, calls), 30, 5)
+
+	// The auto column fits the first rows, the flexible one takes the rest
+	// less the gaps and the scrollbar
+	assert.Equal(t, []int{4, 6, 17}, r.widths)
+	assert.Equal(t, geometry.Size{Width: 30, Height: 5}, r.Size())
+	assert.Equal(t, 100_000, r.controller.ContentExtent())
+	assert.Equal(t, 4, r.controller.ViewportExtent())
+	assert.Equal(t, autoSizeRows, calls[1])
+	assert.Zero(t, calls[2], "flexible columns are not measured")
+}
+
+func TestDataTable_Paint(t *testing.T) {
+	calls := map[int]int{}
+	table := sampleTable(100_000, calls).WithSort(1, true)
+	_, r := layoutDataTable(table, 30, 5)
+	r.controller.JumpTo(50_000)
+
+	lines := paintTable(r)
+
+	assert.Equal(t, "ID  │Name ▲│Note              ", lines[0])
+	assert.Equal(t, "500… name5… notenotenotenote…░", lines[1], "the header stays on top")
+	assert.Equal(t, 4, calls[2], "only rows in view are asked for")
+}
+
+func TestDataTable_PaintSelection(t *testing.T) {
+	table := sampleTable(10, map[int]int{}).WithSelectionMode(SelectionSingle)
+	_, r := layoutDataTable(table, 30, 5)
+	DispatchEvent(r, terminal.KeyEvent{Key: tcell.KeyDown})
+
+	m := NewMockRenderContext()
+	r.Paint(m)
+
+	assert.Equal(t, color.Blue, m.cells[geometry.Point{X: 0, Y: 2}].Bg)
+	assert.Equal(t, color.Blue, m.cells[geometry.Point{X: 28, Y: 2}].Bg, "the whole row is filled")
+	assert.NotEqual(t, color.Blue, m.cells[geometry.Point{X: 0, Y: 1}].Bg)
+}
+
+func TestDataTable_StickyFirstColumn(t *testing.T) {
+	table := sampleTable(10, map[int]int{}).WithStickyFirstColumn(true)
+	table.columns[2] = NewDataColumn("Note")
+	_, r := layoutDataTable(table, 20, 11)
+
+	assert.True(t, DispatchEvent(r, terminal.KeyEvent{Key: tcell.KeyRight}))
+	assert.Equal(t, 7, r.scrollX)
+
+	lines := paintTable(r)
+	assert.Equal(t, "ID  │Note           ", lines[0])
+	assert.Equal(t, "0    notenotenotenot", lines[1])
+}
+
+func TestDataTable_Sort(t *testing.T) {
+	type call struct {
+		column    int
+		ascending bool
+	}
+	tests := []struct {
+		name  string
+		table func(*DataTable) *DataTable
+		x     int
+		want  []call
+	}{
+		{"sortable header", func(t *DataTable) *DataTable { return t }, 6, []call{{1, true}}},
+		{"sorted column reverses", func(t *DataTable) *DataTable { return t.WithSort(1, true) }, 6, []call{{1, false}}},
+		{"unsortable header", func(t *DataTable) *DataTable { return t }, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []call
+			table := tt.table(sampleTable(10, map[int]int{})).
+				WithOnSort(func(column int, ascending bool) { calls = append(calls, call{column, ascending}) })
+			_, r := layoutDataTable(table, 30, 5)
+
+			DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: tt.x}})
+			assert.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+func TestDataTable_Selection(t *testing.T) {
+	key := func(k tcell.Key) terminal.Event { return terminal.KeyEvent{Key: k} }
+	space := terminal.KeyEvent{Key: tcell.KeyRune, Rune: ' '}
+	click := func(y int) terminal.Event {
+		return terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 1, Y: y}}
+	}
+
+	tests := []struct {
+		name   string
+		mode   SelectionMode
+		events []terminal.Event
+		want   []int
+		offset int
+	}{
+		{"none scrolls", SelectionNone, []terminal.Event{key(tcell.KeyDown), click(2)}, []int{}, 1},
+		{"single follows the cursor", SelectionSingle, []terminal.Event{key(tcell.KeyDown), key(tcell.KeyDown)}, []int{2}, 0},
+		{"single scrolls to the cursor", SelectionSingle, []terminal.Event{key(tcell.KeyEnd)}, []int{99}, 96},
+		{"single click", SelectionSingle, []terminal.Event{click(3)}, []int{2}, 0},
+		{"multi toggles", SelectionMulti, []terminal.Event{space, key(tcell.KeyDown), key(tcell.KeyDown), space}, []int{0, 2}, 0},
+		{"multi click twice", SelectionMulti, []terminal.Event{click(2), click(3), click(2)}, []int{2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var changes [][]int
+			table := sampleTable(100, map[int]int{}).
+				WithSelectionMode(tt.mode).
+				WithOnSelectionChanged(func(rows []int) { changes = append(changes, rows) })
+			_, r := layoutDataTable(table, 30, 5)
+
+			for _, event := range tt.events {
+				DispatchEvent(r, event)
+			}
+
+			assert.Equal(t, tt.want, r.Selected())
+			assert.Equal(t, tt.offset, r.controller.Offset())
+			if len(tt.want) > 0 {
+				assert.Equal(t, tt.want, changes[len(changes)-1])
+			}
+		})
+	}
+}
+
+func TestDataTable_KeysNeedFocus(t *testing.T) {
+	keys := []terminal.Event{
+		terminal.KeyEvent{Key: tcell.KeyDown},
+		terminal.KeyEvent{Key: tcell.KeyRight},
+		terminal.KeyEvent{Key: tcell.KeyPgDn},
+		terminal.KeyEvent{Key: tcell.KeyEnd},
+		terminal.KeyEvent{Key: tcell.KeyRune, Rune: ' '},
+	}
+	table := sampleTable(100, map[int]int{}).WithSelectionMode(SelectionMulti)
+	owner, r := layoutDataTable(table, 12, 5)
+	r.focus.Unfocus()
+	owner.BuildScope()
+
+	for _, event := range keys {
+		assert.False(t, DispatchEvent(r, event))
+	}
+	assert.Equal(t, 0, r.cursor)
+	assert.Equal(t, 0, r.scrollX)
+	assert.Equal(t, 0, r.controller.Offset())
+	assert.Empty(t, r.Selected())
+
+	t.Run("a click focuses the table", func(t *testing.T) {
+		DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 1, Y: 0}})
+		owner.BuildScope()
+
+		assert.True(t, r.focus.HasFocus())
+		assert.True(t, DispatchEvent(r, terminal.KeyEvent{Key: tcell.KeyDown}))
+		assert.Equal(t, 1, r.cursor)
+	})
+
+	t.Run("the wheel scrolls without focus", func(t *testing.T) {
+		r.focus.Unfocus()
+		owner.BuildScope()
+
+		assert.True(t, DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.WheelDown}))
+		assert.Equal(t, 3, r.controller.Offset())
+	})
+}
+
+func TestDataTable_ResizeColumn(t *testing.T) {
+	var resized []int
+	table := sampleTable(10, map[int]int{}).
+		WithOnColumnResize(func(column, width int) { resized = append(resized, column, width) })
+	owner, r := layoutDataTable(table, 30, 5)
+
+	// The border after Name is at x = 4 + 1 + 6
+	DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 11}})
+	DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 14}, Motion: true})
+	owner.BuildScope()
+	r.Layout(r.Constraints())
+
+	assert.Equal(t, []int{1, 9}, resized)
+	assert.Equal(t, []int{4, 9, 14}, r.widths)
+}
+
+func TestEllipsize(t *testing.T) {
+	tests := []struct {
+		text    string
+		width   int
+		unicode bool
+		want    string
+	}{
+		{"short", 10, true, "short"},
+		{"exactly", 7, true, "exactly"},
+		{"too long", 5, true, "too …"},
+		{"too long", 5, false, "to..."},
+		{"too long", 1, true, "…"},
+		{"too long", 0, true, ""},
+		{"first\nsecond", 10, true, "first"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ellipsize(tt.text, tt.width, tt.unicode), "%q in %d", tt.text, tt.width)
+	}
+}
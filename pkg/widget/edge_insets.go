@@ -5,7 +5,10 @@
 
 package widget
 
-import "github.com/watzon/tide/internal/utils"
+import (
+	"github.com/watzon/tide/internal/utils"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
 
 // EdgeInsets represents spacing measurements for all four edges
 type EdgeInsets struct {
@@ -119,3 +122,16 @@ func (e EdgeInsets) Clamp(min, max int) EdgeInsets {
 		Left:   utils.ClampInt(e.Left, min, max),
 	}
 }
+
+// Deflate shrinks a rectangle by the insets. A rectangle too small for the
+// insets becomes empty rather than inverted.
+func (e EdgeInsets) Deflate(rect geometry.Rect) geometry.Rect {
+	minPoint := geometry.Point{X: rect.Min.X + e.Left, Y: rect.Min.Y + e.Top}
+	return geometry.Rect{
+		Min: minPoint,
+		Max: geometry.Point{
+			X: max(minPoint.X, rect.Max.X-e.Right),
+			Y: max(minPoint.Y, rect.Max.Y-e.Bottom),
+		},
+	}
+}
@@ -32,6 +32,10 @@ type Element interface {
 	LayoutPhase()
 	NeedsLayout() bool
 	MarkNeedsLayout()
+
+	// Scheduling
+	Depth() int
+	Owner() *BuildOwner
 }
 
 // BaseElement provides common element functionality
@@ -43,6 +47,78 @@ type BaseElement struct {
 	dirty        bool
 	mounted      bool
 	needsLayout  bool
+
+	// component is true when the widget builds into a different widget,
+	// in which case the element renders through its child
+	component bool
+
+	// built is set once the element has built for the first time
+	built bool
+
+	// Inherited widgets this element depends on
+	dependencies []*inheritedElement
+	// lostDependencies is set when the dependencies were dropped because
+	// the element left the tree, so it looks them up again if it returns
+	lostDependencies bool
+
+	// Scheduling
+	owner *BuildOwner
+	depth int
+
+	// self is the outermost element embedding this BaseElement, so children
+	// and build contexts see the concrete element type
+	self Element
+}
+
+// element returns the outermost element embedding e
+func (e *BaseElement) element() Element {
+	if e.self != nil {
+		return e.self
+	}
+	return e
+}
+
+// builder is implemented by elements that know how to build their subtree
+type builder interface {
+	Build()
+}
+
+// performBuild runs Build on the outermost element so embedding elements
+// get their own build behaviour
+func (e *BaseElement) performBuild() {
+	if b, ok := e.element().(builder); ok {
+		b.Build()
+		return
+	}
+	e.Build()
+}
+
+// attach records the element's position in the tree and inherits the
+// parent's build owner
+func (e *BaseElement) attach(parent Element) {
+	e.parent = parent
+	e.mounted = true
+	e.depth = 0
+	if parent != nil {
+		e.depth = parent.Depth() + 1
+		if owner := parent.Owner(); owner != nil {
+			e.owner = owner
+		}
+	}
+	e.registerGlobalKey()
+}
+
+// assignOwner sets the build owner of a root element before it is mounted
+func (e *BaseElement) assignOwner(owner *BuildOwner) {
+	e.owner = owner
+}
+
+func (e *BaseElement) Depth() int {
+	return e.depth
+}
+
+func (e *BaseElement) Owner() *BuildOwner {
+	return e.owner
 }
 
 func (e *BaseElement) Parent() Element {
@@ -57,7 +133,12 @@ func (e *BaseElement) Widget() Widget {
 	return e.widget
 }
 
+// RenderObject returns the render object that paints this element. Elements
+// whose widget builds into another widget defer to their child.
 func (e *BaseElement) RenderObject() RenderObject {
+	if e.component && len(e.children) > 0 {
+		return e.children[0].RenderObject()
+	}
 	return e.renderObject
 }
 
@@ -66,8 +147,7 @@ func (e *BaseElement) Mount(parent Element) {
 		return
 	}
 
-	e.parent = parent
-	e.mounted = true
+	e.attach(parent)
 
 	// Create render object
 	e.renderObject = e.widget.CreateRenderObject()
@@ -81,6 +161,9 @@ func (e *BaseElement) Unmount() {
 		return
 	}
 
+	e.unregisterGlobalKey()
+	e.clearDependencies()
+
 	// Unmount children first
 	for _, child := range e.children {
 		child.Unmount()
@@ -100,43 +183,49 @@ func (e *BaseElement) Build() {
 	// Build new widget
 	newWidget := e.widget.Build(e.BuildContext())
 	if newWidget == e.widget {
-		// If the widget returns itself, don't create a new child
+		// Widgets that return themselves render directly, laying out any
+		// children they hold
+		if multi, ok := e.widget.(MultiChildWidget); ok {
+			e.children = e.updateChildren(e.children, multi.GetChildren())
+			e.syncRenderChildren()
+		}
 		e.dirty = false
+		e.built = true
 		return
 	}
+	e.component = true
 
 	// Update or create child element
-	if len(e.children) > 0 {
-		e.children[0].Update(newWidget)
-	} else {
-		child := NewElement(newWidget)
-		e.children = append(e.children, child)
-		child.Mount(e)
-	}
+	e.updateSingleChild(newWidget)
 
 	e.dirty = false
+	e.built = true
 }
 
+// Update points the element at a new widget. Widgets that cannot update this
+// element, because their type or key differs, replace it in the parent.
 func (e *BaseElement) Update(newWidget Widget) {
-	// Check if widget types are different using type assertion
-	_, oldIsMock := e.widget.(*MockWidget)
-	_, newIsMock := newWidget.(*MockWidget)
-
-	if oldIsMock != newIsMock {
-		// Replace entire element if widget type changes
-		if e.parent != nil {
-			newElement := NewElement(newWidget)
-			e.parent.ReplaceChild(e, newElement)
-		}
+	if !canUpdate(e.widget, newWidget) {
+		e.replaceWith(newWidget)
 		return
 	}
 
 	e.widget = newWidget
 	e.widget.UpdateRenderObject(e.renderObject)
+	markNeedsLayout(e.renderObject)
 	e.MarkNeedsBuild()
 }
 
+// MarkNeedsBuild schedules the element for rebuilding. Elements attached to
+// a BuildOwner are queued there and rebuilt on the next build scope; it is
+// safe to call from any goroutine in that case. Unowned elements are marked
+// dirty along with their ancestors.
 func (e *BaseElement) MarkNeedsBuild() {
+	if e.owner != nil {
+		e.owner.ScheduleBuildFor(e.element())
+		return
+	}
+
 	e.dirty = true
 	// Propagate to parent if needed
 	if e.parent != nil {
@@ -146,23 +235,37 @@ func (e *BaseElement) MarkNeedsBuild() {
 
 func (e *BaseElement) RebuildIfNeeded() {
 	if e.dirty {
-		e.Build()
+		e.performBuild()
 	}
 }
 
+// rebuild is called by the BuildOwner on the build goroutine
+func (e *BaseElement) rebuild() {
+	e.dirty = true
+	e.performBuild()
+}
+
 func (e *BaseElement) BuildContext() BuildContext {
-	return &ElementBuildContext{element: e}
+	return &ElementBuildContext{element: e.element()}
+}
+
+// replaceWith swaps the element for a new one built from newWidget
+func (e *BaseElement) replaceWith(newWidget Widget) {
+	if e.parent != nil {
+		e.parent.ReplaceChild(e.element(), NewElement(newWidget))
+	}
 }
 
 func (e *BaseElement) ReplaceChild(old, new Element) {
 	for i, child := range e.children {
 		if child == old {
 			// Unmount old child
-			old.Unmount()
+			e.deactivateChild(old)
 
 			// Mount new child
 			e.children[i] = new
-			new.Mount(e)
+			new.Mount(e.element())
+			e.childRenderObjectChanged()
 
 			// Mark parent as needing rebuild
 			e.MarkNeedsBuild()
@@ -171,14 +274,27 @@ func (e *BaseElement) ReplaceChild(old, new Element) {
 	}
 }
 
+// elementFactory is implemented by widgets that need their own kind of
+// element
+type elementFactory interface {
+	createElement() Element
+}
+
 // NewElement creates the appropriate element type for a widget
 func NewElement(widget Widget) Element {
+	if factory, ok := widget.(elementFactory); ok {
+		return factory.createElement()
+	}
+
 	switch w := widget.(type) {
 	case StatefulWidget:
 		return NewStatefulElement(w)
+	case InheritedWidget:
+		return newInheritedElement(w)
 	default:
 		elem := &BaseElement{}
 		elem.widget = widget
+		elem.self = elem
 		return elem
 	}
 }
@@ -243,6 +359,7 @@ type baseStatefulElement struct {
 func NewStatefulElement(widget StatefulWidget) StatefulElement {
 	elem := &baseStatefulElement{}
 	elem.widget = widget
+	elem.self = elem
 	elem.state = widget.CreateState()
 	return elem
 }
@@ -256,14 +373,14 @@ func (e *baseStatefulElement) Mount(parent Element) {
 		return
 	}
 
-	e.parent = parent
-	e.mounted = true
+	e.attach(parent)
 
 	// Create render object
 	e.renderObject = e.widget.CreateRenderObject()
 
 	// Initialize state
 	e.state.MountState(e)
+	e.state.InitState()
 
 	// Initial build
 	e.Build()
@@ -274,6 +391,9 @@ func (e *baseStatefulElement) Unmount() {
 		return
 	}
 
+	e.unregisterGlobalKey()
+	e.clearDependencies()
+
 	// Dispose state
 	e.state.Dispose()
 
@@ -296,22 +416,29 @@ func (e *baseStatefulElement) Build() {
 
 	// Build using state
 	newWidget := e.state.Build(e.BuildContext())
+	e.component = true
 
 	// Update or create child element
-	if len(e.children) > 0 {
-		e.children[0].Update(newWidget)
-	} else {
-		child := NewElement(newWidget)
-		e.children = append(e.children, child)
-		child.Mount(e)
-	}
+	e.updateSingleChild(newWidget)
 
 	e.dirty = false
+	e.built = true
 }
 
+// Update points the element and its state at a new widget, keeping the
+// state alive. Widgets that cannot update this element replace it.
 func (e *baseStatefulElement) Update(newWidget Widget) {
-	// Update widget reference
-	e.widget = newWidget.(StatefulWidget)
+	statefulWidget, ok := newWidget.(StatefulWidget)
+	if !ok || !canUpdate(e.widget, newWidget) {
+		e.replaceWith(newWidget)
+		return
+	}
+
+	e.widget = statefulWidget
+	if updater, ok := e.state.(widgetUpdater); ok {
+		updater.updateWidget(statefulWidget)
+	}
 	e.widget.UpdateRenderObject(e.renderObject)
+	markNeedsLayout(e.renderObject)
 	e.MarkNeedsBuild()
 }
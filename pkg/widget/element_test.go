@@ -198,10 +198,77 @@ func TestBaseElement_MarkNeedsLayout(t *testing.T) {
 	assert.True(t, parent.needsLayout)
 }
 
+// factoryWidget creates the element it was given
+type factoryWidget struct {
+	BaseWidget
+	element Element
+}
+
+func (w *factoryWidget) createElement() Element {
+	return w.element
+}
+
 func TestNewElement(t *testing.T) {
 	widget := &MockWidget{}
 	element := NewElement(widget)
 
 	assert.NotNil(t, element)
 	assert.Equal(t, widget, element.Widget())
+
+	t.Run("widgets create their own elements", func(t *testing.T) {
+		custom := &BaseElement{}
+		assert.Same(t, custom, NewElement(&factoryWidget{element: custom}))
+		assert.IsType(t, &layoutBuilderElement{}, NewElement(NewLayoutBuilder(nil)))
+		assert.IsType(t, &listViewElement{}, NewElement(NewListViewBuilder(0, nil)))
+	})
+}
+
+// initWidget is a StatefulWidget whose state overrides InitState and
+// Dispose, recording the order of its lifecycle calls
+type initWidget struct {
+	BaseWidget
+	state *initState
+}
+
+func (w *initWidget) CreateState() State {
+	w.state = &initState{}
+	return w.state
+}
+
+type initState struct {
+	BaseState
+	calls []string
+}
+
+func (s *initState) InitState() {
+	// MountState has run, so the state knows its element and widget
+	if s.Element() != nil && s.Widget() != nil && s.Context() != nil {
+		s.calls = append(s.calls, "init")
+	} else {
+		s.calls = append(s.calls, "init before mount")
+	}
+}
+
+func (s *initState) Dispose() {
+	s.calls = append(s.calls, "dispose")
+}
+
+func (s *initState) Build(context BuildContext) Widget {
+	s.calls = append(s.calls, "build")
+	return &MockWidget{}
+}
+
+func TestStatefulElement_InitState(t *testing.T) {
+	w := &initWidget{}
+	owner := NewBuildOwner()
+	element := NewElement(w)
+	owner.MountRoot(element)
+	assert.Equal(t, []string{"init", "build"}, w.state.calls, "InitState runs once mounted and before the first build")
+
+	w.state.SetState(nil)
+	owner.BuildScope()
+	assert.Equal(t, []string{"init", "build", "build"}, w.state.calls, "a rebuild doesn't run InitState again")
+
+	element.Unmount()
+	assert.Equal(t, []string{"init", "build", "build", "dispose"}, w.state.calls)
 }
@@ -0,0 +1,166 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"slices"
+
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// EventHandler is implemented by render objects that respond to input.
+// HandleEvent reports whether the event was consumed. The position of a
+// mouse event is relative to the render object handling it.
+type EventHandler interface {
+	HandleEvent(event terminal.Event) bool
+}
+
+// HoverHandler is implemented by render objects that change while the
+// pointer is over them. HandleHover is called when the pointer enters or
+// leaves the render object.
+type HoverHandler interface {
+	HandleHover(hovered bool)
+}
+
+// HoverTracker remembers which hover handlers the pointer is over, so
+// they can be told when it leaves. The app updates it with the position
+// of every mouse event; the pointer only moves without a button held when
+// the terminal reports motion.
+type HoverTracker struct {
+	hovered []HoverHandler
+}
+
+// Update moves the pointer to position in the render tree under root,
+// telling the handlers it left and then the handlers it entered
+func (t *HoverTracker) Update(root RenderObject, position geometry.Point) {
+	var hovered []HoverHandler
+	if root != nil {
+		for _, target := range HitTest(root, position) {
+			if handler, ok := target.(HoverHandler); ok {
+				hovered = append(hovered, handler)
+			}
+		}
+	}
+
+	previous := t.hovered
+	t.hovered = hovered
+	for _, handler := range previous {
+		if !slices.Contains(hovered, handler) {
+			handler.HandleHover(false)
+			markHovered(handler)
+		}
+	}
+	for _, handler := range hovered {
+		if !slices.Contains(previous, handler) {
+			handler.HandleHover(true)
+			markHovered(handler)
+		}
+	}
+}
+
+// markHovered marks a handler whose hover changed as needing layout
+func markHovered(handler HoverHandler) {
+	if renderObject, ok := handler.(RenderObject); ok {
+		markNeedsLayout(renderObject)
+	}
+}
+
+// handle offers an event to a render object and marks it as needing layout
+// if it consumes the event, since handlers change their render object
+// directly
+func handle(target RenderObject, event terminal.Event) bool {
+	handler, ok := target.(EventHandler)
+	if !ok || !handler.HandleEvent(event) {
+		return false
+	}
+	markNeedsLayout(target)
+	return true
+}
+
+// DispatchEvent delivers an input event to the render tree under root and
+// reports whether a render object consumed it. Mouse events go to the
+// render objects under the pointer, deepest first. Other events are
+// offered to every handler in the tree, children before their parents and
+// later children before earlier ones, until one consumes them. Key events
+// should go through FocusManager.DispatchEvent so focus decides who sees
+// them first. The render object that consumes the event is marked as
+// needing layout.
+func DispatchEvent(root RenderObject, event terminal.Event) bool {
+	if root == nil {
+		return false
+	}
+	if mouse, ok := event.(terminal.MouseEvent); ok {
+		return dispatchMouse(root, mouse)
+	}
+	return dispatchToTree(root, event, nil)
+}
+
+// DispatchEvent delivers an input event to the render tree under root like
+// the package DispatchEvent, except that key events go to the focused node
+// first: to the render object of its element, then up through those of
+// the element's ancestors. Only a key none of them consumes is offered to
+// the rest of the tree.
+func (m *FocusManager) DispatchEvent(root RenderObject, event terminal.Event) bool {
+	key, ok := event.(terminal.KeyEvent)
+	if !ok || root == nil || m.focused == nil || m.focused.element == nil {
+		return DispatchEvent(root, event)
+	}
+
+	offered := make(map[RenderObject]bool)
+	for element := m.focused.element; element != nil; element = element.Parent() {
+		target := element.RenderObject()
+		if target == nil || offered[target] {
+			continue
+		}
+		offered[target] = true
+		if handle(target, key) {
+			return true
+		}
+	}
+	return dispatchToTree(root, key, offered)
+}
+
+// dispatchMouse offers a mouse event to the hit render objects, translating
+// its position into each one's coordinates
+func dispatchMouse(root RenderObject, event terminal.MouseEvent) bool {
+	path := HitTest(root, event.Position)
+	if len(path) == 0 {
+		return false
+	}
+
+	// positions[i] is the pointer relative to path[i]
+	positions := make([]terminal.MouseEvent, len(path))
+	local := event
+	for i := len(path) - 1; i >= 0; i-- {
+		if i < len(path)-1 {
+			local.Position = local.Position.Sub(childOffset(path[i]))
+		}
+		positions[i] = local
+	}
+
+	for i, target := range path {
+		if handle(target, positions[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatchToTree offers an event to the handlers of a subtree, deepest and
+// last painted first, skipping those already offered it
+func dispatchToTree(node RenderObject, event terminal.Event, offered map[RenderObject]bool) bool {
+	children := node.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		if dispatchToTree(children[i], event, offered) {
+			return true
+		}
+	}
+	if offered[node] {
+		return false
+	}
+	return handle(node, event)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// recordingHandler is a render object that records the events it gets
+type recordingHandler struct {
+	BaseRenderObject
+	name    string
+	consume bool
+	log     *[]string
+	events  []terminal.Event
+}
+
+func newRecordingHandler(name string, size geometry.Size, consume bool, log *[]string) *recordingHandler {
+	r := &recordingHandler{name: name, consume: consume, log: log}
+	r.size = size
+	return r
+}
+
+func (r *recordingHandler) HandleEvent(event terminal.Event) bool {
+	*r.log = append(*r.log, r.name)
+	r.events = append(r.events, event)
+	return r.consume
+}
+
+func TestDispatchEvent(t *testing.T) {
+	t.Run("mouse events go to the hit path with local positions", func(t *testing.T) {
+		var log []string
+		root := newRecordingHandler("root", geometry.Size{Width: 10, Height: 10}, false, &log)
+		child := newRecordingHandler("child", geometry.Size{Width: 4, Height: 4}, false, &log)
+		other := newRecordingHandler("other", geometry.Size{Width: 4, Height: 4}, false, &log)
+		root.SetChildren([]RenderObject{child, other})
+		setChildOffset(child, geometry.Point{X: 2, Y: 3})
+		setChildOffset(other, geometry.Point{X: 6, Y: 6})
+
+		event := terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 3, Y: 5}}
+		assert.False(t, DispatchEvent(root, event))
+
+		assert.Equal(t, []string{"child", "root"}, log)
+		assert.Equal(t, geometry.Point{X: 1, Y: 2}, child.events[0].(terminal.MouseEvent).Position)
+		assert.Equal(t, geometry.Point{X: 3, Y: 5}, root.events[0].(terminal.MouseEvent).Position)
+	})
+
+	t.Run("other events go children first until consumed", func(t *testing.T) {
+		var log []string
+		root := newRecordingHandler("root", geometry.Size{}, false, &log)
+		first := newRecordingHandler("first", geometry.Size{}, true, &log)
+		second := newRecordingHandler("second", geometry.Size{}, false, &log)
+		root.SetChildren([]RenderObject{first, second})
+
+		assert.True(t, DispatchEvent(root, terminal.KeyEvent{Key: tcell.KeyEnter}))
+		assert.Equal(t, []string{"second", "first"}, log)
+	})
+
+	t.Run("the consuming render object needs layout", func(t *testing.T) {
+		var log []string
+		root := newRecordingHandler("root", geometry.Size{}, false, &log)
+		first := newRecordingHandler("first", geometry.Size{}, true, &log)
+		second := newRecordingHandler("second", geometry.Size{}, false, &log)
+		root.SetChildren([]RenderObject{first, second})
+		for _, node := range []RenderObject{first, second, root} {
+			LayoutIfNeeded(node, NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 10}))
+		}
+
+		assert.True(t, DispatchEvent(root, terminal.KeyEvent{Key: tcell.KeyEnter}))
+		assert.False(t, first.laidOut)
+		assert.False(t, root.laidOut)
+		assert.True(t, second.laidOut)
+	})
+
+	t.Run("unconsumed events change nothing", func(t *testing.T) {
+		var log []string
+		root := newRecordingHandler("root", geometry.Size{Width: 10, Height: 10}, false, &log)
+		LayoutIfNeeded(root, NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 10}))
+		PaintRoot(NewMockRenderContext(), root)
+
+		assert.False(t, DispatchEvent(root, terminal.MouseEvent{Motion: true, Position: geometry.Point{X: 1, Y: 1}}))
+		assert.True(t, root.laidOut)
+		assert.False(t, NeedsPaint(root))
+	})
+
+	t.Run("nil root", func(t *testing.T) {
+		assert.False(t, DispatchEvent(nil, terminal.KeyEvent{Key: tcell.KeyEnter}))
+	})
+}
+
+// handlerWidget shows a render object that handles events
+type handlerWidget struct {
+	BaseWidget
+	r RenderObject
+}
+
+func (w *handlerWidget) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *handlerWidget) CreateRenderObject() RenderObject {
+	return w.r
+}
+
+func (w *handlerWidget) UpdateRenderObject(renderObject RenderObject) {}
+
+func TestFocusManager_DispatchEvent(t *testing.T) {
+	var log []string
+	greedy := newRecordingHandler("greedy", geometry.Size{}, true, &log)
+	text := NewTextEditingController("")
+	node := NewFocusNode()
+	owner := NewBuildOwner()
+	root := NewElement(NewColumn(
+		NewTextInput().WithController(text).WithFocusNode(node),
+		&handlerWidget{r: greedy},
+	))
+	owner.MountRoot(root)
+	m := owner.FocusManager()
+
+	t.Run("without focus keys go to every handler", func(t *testing.T) {
+		assert.True(t, m.DispatchEvent(root.RenderObject(), runeOf('x')))
+		assert.Equal(t, []string{"greedy"}, log)
+		assert.Equal(t, "", text.Text())
+	})
+
+	t.Run("the focused node sees keys first", func(t *testing.T) {
+		log = nil
+		node.RequestFocus()
+		for _, ch := range "ha" {
+			assert.True(t, m.DispatchEvent(root.RenderObject(), runeOf(ch)))
+		}
+		assert.Equal(t, "ha", text.Text())
+		assert.Empty(t, log)
+	})
+
+	t.Run("keys the focused node leaves go to the rest", func(t *testing.T) {
+		assert.True(t, m.DispatchEvent(root.RenderObject(), keyOf(tcell.KeyF1)))
+		assert.Equal(t, []string{"greedy"}, log)
+	})
+
+	t.Run("mouse events ignore focus", func(t *testing.T) {
+		log = nil
+		assert.False(t, m.DispatchEvent(root.RenderObject(), click(50, 50)))
+		assert.Empty(t, log)
+	})
+}
+
+// hoverRecorder records when the pointer enters and leaves it
+type hoverRecorder struct {
+	recordingHandler
+}
+
+func (r *hoverRecorder) HandleHover(hovered bool) {
+	state := "leave"
+	if hovered {
+		state = "enter"
+	}
+	*r.log = append(*r.log, r.name+" "+state)
+}
+
+func TestHoverTracker(t *testing.T) {
+	var log []string
+	root := &hoverRecorder{*newRecordingHandler("root", geometry.Size{Width: 10, Height: 10}, false, &log)}
+	left := &hoverRecorder{*newRecordingHandler("left", geometry.Size{Width: 4, Height: 4}, false, &log)}
+	right := &hoverRecorder{*newRecordingHandler("right", geometry.Size{Width: 4, Height: 4}, false, &log)}
+	root.SetChildren([]RenderObject{left, right})
+	setChildOffset(right, geometry.Point{X: 5})
+
+	var tracker HoverTracker
+	moves := []struct {
+		to   geometry.Point
+		want []string
+	}{
+		{geometry.Point{X: 1, Y: 1}, []string{"left enter", "root enter"}},
+		{geometry.Point{X: 2, Y: 2}, nil},
+		{geometry.Point{X: 6, Y: 1}, []string{"left leave", "right enter"}},
+		{geometry.Point{X: 6, Y: 8}, []string{"right leave"}},
+		{geometry.Point{X: 20, Y: 20}, []string{"root leave"}},
+	}
+	for _, move := range moves {
+		log = nil
+		tracker.Update(root, move.to)
+		assert.Equal(t, move.want, log, "moving to %v", move.to)
+	}
+}
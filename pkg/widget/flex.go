@@ -0,0 +1,527 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// Axis is the direction a layout places its children along
+type Axis int
+
+const (
+	AxisHorizontal Axis = iota
+	AxisVertical
+)
+
+// main returns the extent of a size along the axis
+func (a Axis) main(size geometry.Size) int {
+	if a == AxisVertical {
+		return size.Height
+	}
+	return size.Width
+}
+
+// cross returns the extent of a size across the axis
+func (a Axis) cross(size geometry.Size) int {
+	if a == AxisVertical {
+		return size.Width
+	}
+	return size.Height
+}
+
+// size builds a size from extents along and across the axis
+func (a Axis) size(main, cross int) geometry.Size {
+	if a == AxisVertical {
+		return geometry.Size{Width: cross, Height: main}
+	}
+	return geometry.Size{Width: main, Height: cross}
+}
+
+// point builds a point from positions along and across the axis
+func (a Axis) point(main, cross int) geometry.Point {
+	if a == AxisVertical {
+		return geometry.Point{X: cross, Y: main}
+	}
+	return geometry.Point{X: main, Y: cross}
+}
+
+// MainAxisAlignment controls how children are placed along the main axis
+// when they don't fill it
+type MainAxisAlignment int
+
+const (
+	// MainAxisStart places children at the start of the main axis
+	MainAxisStart MainAxisAlignment = iota
+	// MainAxisEnd places children at the end of the main axis
+	MainAxisEnd
+	// MainAxisCenter places children in the middle of the main axis
+	MainAxisCenter
+	// MainAxisSpaceBetween puts the free space evenly between children
+	MainAxisSpaceBetween
+	// MainAxisSpaceAround puts the free space evenly between children and
+	// half that space before the first and after the last child
+	MainAxisSpaceAround
+	// MainAxisSpaceEvenly puts the free space evenly between children and
+	// before the first and after the last child
+	MainAxisSpaceEvenly
+)
+
+// distribute returns the space before the first of count children and the
+// extra space between children when free cells are left over
+func (a MainAxisAlignment) distribute(free, count int) (leading, between int) {
+	switch a {
+	case MainAxisEnd:
+		return free, 0
+	case MainAxisCenter:
+		return free / 2, 0
+	case MainAxisSpaceBetween:
+		if count > 1 {
+			return 0, free / (count - 1)
+		}
+	case MainAxisSpaceAround:
+		if count > 0 {
+			return free / count / 2, free / count
+		}
+	case MainAxisSpaceEvenly:
+		return free / (count + 1), free / (count + 1)
+	}
+	return 0, 0
+}
+
+// CrossAxisAlignment controls how children are placed along the cross axis
+type CrossAxisAlignment int
+
+const (
+	// CrossAxisStart aligns children with the start of the cross axis
+	CrossAxisStart CrossAxisAlignment = iota
+	// CrossAxisEnd aligns children with the end of the cross axis
+	CrossAxisEnd
+	// CrossAxisCenter centers children on the cross axis
+	CrossAxisCenter
+	// CrossAxisStretch makes children fill the cross axis
+	CrossAxisStretch
+)
+
+// offset returns a child's position across the axis given the space it
+// leaves free
+func (a CrossAxisAlignment) offset(free int) int {
+	switch a {
+	case CrossAxisEnd:
+		return free
+	case CrossAxisCenter:
+		return free / 2
+	default:
+		return 0
+	}
+}
+
+// MainAxisSize controls how much space a flex takes up along its main axis
+type MainAxisSize int
+
+const (
+	// MainAxisSizeMax takes up all the space the constraints allow
+	MainAxisSizeMax MainAxisSize = iota
+	// MainAxisSizeMin shrinks to fit the children
+	MainAxisSizeMin
+)
+
+// FlexFit controls whether a flexible child must fill the space it is given
+type FlexFit int
+
+const (
+	// FlexFitTight forces the child to fill its share of the free space
+	FlexFitTight FlexFit = iota
+	// FlexFitLoose lets the child be smaller than its share
+	FlexFitLoose
+)
+
+// Flex lays out its children in a line along an axis. Children wrapped in
+// Expanded or Flexible share the space left over once the other children
+// have been laid out, in proportion to their flex factors.
+type Flex struct {
+	BaseWidget
+	direction          Axis
+	mainAxisAlignment  MainAxisAlignment
+	crossAxisAlignment CrossAxisAlignment
+	mainAxisSize       MainAxisSize
+	spacing            int
+	children           []Widget
+}
+
+// NewFlex creates a flex that lays out children along direction
+func NewFlex(direction Axis, children ...Widget) *Flex {
+	return &Flex{
+		BaseWidget: BaseWidget{
+			style: NewWidgetStyle(),
+		},
+		direction: direction,
+		children:  children,
+	}
+}
+
+// NewRow creates a flex that lays out children from left to right
+func NewRow(children ...Widget) *Flex {
+	return NewFlex(AxisHorizontal, children...)
+}
+
+// NewColumn creates a flex that lays out children from top to bottom
+func NewColumn(children ...Widget) *Flex {
+	return NewFlex(AxisVertical, children...)
+}
+
+func (f *Flex) AppendChild(child Widget) {
+	f.children = append(f.children, child)
+}
+
+// GetChildren returns the child widgets of the flex
+func (f *Flex) GetChildren() []Widget {
+	return f.children
+}
+
+// Direction returns the main axis of the flex
+func (f *Flex) Direction() Axis {
+	return f.direction
+}
+
+// WithMainAxisAlignment sets how children are placed along the main axis
+func (f *Flex) WithMainAxisAlignment(alignment MainAxisAlignment) *Flex {
+	f.mainAxisAlignment = alignment
+	return f
+}
+
+// WithCrossAxisAlignment sets how children are placed along the cross axis
+func (f *Flex) WithCrossAxisAlignment(alignment CrossAxisAlignment) *Flex {
+	f.crossAxisAlignment = alignment
+	return f
+}
+
+// WithMainAxisSize sets how much space the flex takes up along its main axis
+func (f *Flex) WithMainAxisSize(size MainAxisSize) *Flex {
+	f.mainAxisSize = size
+	return f
+}
+
+// WithSpacing sets the number of cells left between adjacent children
+func (f *Flex) WithSpacing(spacing int) *Flex {
+	f.spacing = max(0, spacing)
+	return f
+}
+
+// WithStyle sets the style of the flex, whose padding and border surround
+// the children
+func (f *Flex) WithStyle(style WidgetStyle) *Flex {
+	f.style = style
+	return f
+}
+
+func (f *Flex) Build(context BuildContext) Widget {
+	return f
+}
+
+// CreateRenderObject creates the flex's render object. The render objects of
+// the children are attached by the flex's element.
+func (f *Flex) CreateRenderObject() RenderObject {
+	r := NewRenderFlex(f.direction)
+	f.UpdateRenderObject(r)
+	return r
+}
+
+func (f *Flex) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderFlex); ok {
+		r.WithStyle(f.style)
+		r.direction = f.direction
+		r.mainAxisAlignment = f.mainAxisAlignment
+		r.crossAxisAlignment = f.crossAxisAlignment
+		r.mainAxisSize = f.mainAxisSize
+		r.spacing = f.spacing
+	}
+}
+
+// Flexible makes a child of a Flex share the flex's free space. The share
+// is the child's flex factor divided by the total of all flex factors.
+type Flexible struct {
+	BaseWidget
+	child Widget
+	flex  int
+	fit   FlexFit
+}
+
+// NewFlexible creates a flexible child with a flex factor of 1 that may be
+// smaller than its share of the free space
+func NewFlexible(child Widget) *Flexible {
+	return &Flexible{child: child, flex: 1, fit: FlexFitLoose}
+}
+
+// NewExpanded creates a flexible child with a flex factor of 1 that fills
+// its share of the free space
+func NewExpanded(child Widget) *Flexible {
+	return &Flexible{child: child, flex: 1, fit: FlexFitTight}
+}
+
+// NewSpacer creates an empty expanded child that takes up its share of the
+// free space, pushing its siblings apart
+func NewSpacer() *Flexible {
+	return NewExpanded(nil)
+}
+
+// WithFlex sets the flex factor. A factor of zero makes the child inflexible.
+func (f *Flexible) WithFlex(flex int) *Flexible {
+	f.flex = max(0, flex)
+	return f
+}
+
+// WithFit sets whether the child must fill its share of the free space
+func (f *Flexible) WithFit(fit FlexFit) *Flexible {
+	f.fit = fit
+	return f
+}
+
+// GetChildren returns the wrapped child, if any
+func (f *Flexible) GetChildren() []Widget {
+	if f.child == nil {
+		return nil
+	}
+	return []Widget{f.child}
+}
+
+func (f *Flexible) Build(context BuildContext) Widget {
+	return f
+}
+
+func (f *Flexible) CreateRenderObject() RenderObject {
+	r := &renderProxy{}
+	f.UpdateRenderObject(r)
+	return r
+}
+
+func (f *Flexible) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderProxy); ok {
+		r.style = f.style
+		data, ok := r.parentData.(*FlexParentData)
+		if !ok {
+			data = &FlexParentData{}
+			r.parentData = data
+		}
+		data.Flex = f.flex
+		data.Fit = f.fit
+	}
+}
+
+// FlexParentData is the parent data of a child of a RenderFlex
+type FlexParentData struct {
+	BoxParentData
+	// Flex is the child's share of the free space. Zero means the child is
+	// inflexible.
+	Flex int
+	// Fit is whether the child must fill its share
+	Fit FlexFit
+}
+
+// flexFactor returns the flex factor and fit of a child render object. Zero
+// means the child is inflexible.
+func flexFactor(child RenderObject) (int, FlexFit) {
+	if data, ok := child.ParentData().(*FlexParentData); ok {
+		return data.Flex, data.Fit
+	}
+	return 0, FlexFitTight
+}
+
+// RenderFlex lays out its children in a line along its main axis inside
+// the box model of its style
+type RenderFlex struct {
+	BaseRenderBox
+	direction          Axis
+	mainAxisAlignment  MainAxisAlignment
+	crossAxisAlignment CrossAxisAlignment
+	mainAxisSize       MainAxisSize
+	spacing            int
+
+	// overflow is how many cells the children extend past the main axis
+	overflow int
+}
+
+// NewRenderFlex creates a render flex laying out along direction
+func NewRenderFlex(direction Axis) *RenderFlex {
+	return &RenderFlex{
+		BaseRenderBox: *NewBaseRenderBox(),
+		direction:     direction,
+	}
+}
+
+// Overflow returns how many cells the children extend past the end of the
+// main axis after the last layout, or zero if they fit
+func (r *RenderFlex) Overflow() int {
+	return r.overflow
+}
+
+// HasOverflow reports whether the children did not fit along the main axis
+func (r *RenderFlex) HasOverflow() bool {
+	return r.overflow > 0
+}
+
+func (r *RenderFlex) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	content := r.layoutFlex(r.contentConstraints(constraints))
+	r.size = constraints.Constrain(r.outerSize(content))
+	return r.size
+}
+
+// flexLine records the outcome of sizing the children along the main axis
+type flexLine struct {
+	sizes     []geometry.Size
+	used      int
+	crossSize int
+}
+
+// layoutFlex lays out and positions the children within the content
+// constraints and returns the content size
+func (r *RenderFlex) layoutFlex(constraints Constraints) geometry.Size {
+	maxMain := r.direction.main(constraints.MaxSize)
+	bounded := maxMain < math.MaxInt32
+
+	line := r.measureChildren(constraints, bounded)
+
+	mainSize := max(line.used, r.direction.main(constraints.MinSize))
+	if r.mainAxisSize == MainAxisSizeMax && bounded {
+		mainSize = maxMain
+	}
+	mainSize = min(mainSize, maxMain)
+
+	crossSize := max(line.crossSize, r.direction.cross(constraints.MinSize))
+	if r.crossAxisAlignment == CrossAxisStretch && r.direction.cross(constraints.MaxSize) < math.MaxInt32 {
+		crossSize = r.direction.cross(constraints.MaxSize)
+	}
+	crossSize = min(crossSize, r.direction.cross(constraints.MaxSize))
+
+	r.overflow = max(0, line.used-mainSize)
+	r.position(line, mainSize, crossSize)
+	return r.direction.size(mainSize, crossSize)
+}
+
+// measureChildren lays out the inflexible children first, then divides the
+// remaining space between the flexible ones
+func (r *RenderFlex) measureChildren(constraints Constraints, bounded bool) flexLine {
+	line := flexLine{sizes: make([]geometry.Size, len(r.children))}
+	line.used = r.spacing * max(0, len(r.children)-1)
+
+	totalFlex := 0
+	for i, child := range r.children {
+		flex, _ := flexFactor(child)
+		if flex > 0 && bounded {
+			totalFlex += flex
+			continue
+		}
+		line.sizes[i] = LayoutIfNeeded(child, r.childConstraints(constraints, 0, math.MaxInt32))
+		line.used += r.direction.main(line.sizes[i])
+	}
+
+	if totalFlex > 0 {
+		free := max(0, r.direction.main(constraints.MaxSize)-line.used)
+		line.used += r.layoutFlexible(constraints, line.sizes, free, totalFlex)
+	}
+
+	for _, size := range line.sizes {
+		line.crossSize = max(line.crossSize, r.direction.cross(size))
+	}
+	return line
+}
+
+// layoutFlexible lays out the flexible children with their share of the
+// free space and returns the space they used. Cells that don't divide
+// evenly go to the first flexible children.
+func (r *RenderFlex) layoutFlexible(constraints Constraints, sizes []geometry.Size, free, totalFlex int) int {
+	used := 0
+	remainder := free
+	for _, child := range r.children {
+		if flex, _ := flexFactor(child); flex > 0 {
+			remainder -= free * flex / totalFlex
+		}
+	}
+
+	for i, child := range r.children {
+		flex, fit := flexFactor(child)
+		if flex == 0 {
+			continue
+		}
+		share := free * flex / totalFlex
+		if remainder > 0 {
+			share++
+			remainder--
+		}
+
+		minMain := 0
+		if fit == FlexFitTight {
+			minMain = share
+		}
+		sizes[i] = LayoutIfNeeded(child, r.childConstraints(constraints, minMain, share))
+		used += r.direction.main(sizes[i])
+	}
+	return used
+}
+
+// childConstraints returns the constraints for a child given its main axis
+// bounds
+func (r *RenderFlex) childConstraints(constraints Constraints, minMain, maxMain int) Constraints {
+	maxCross := r.direction.cross(constraints.MaxSize)
+	minCross := 0
+	if r.crossAxisAlignment == CrossAxisStretch && maxCross < math.MaxInt32 {
+		minCross = maxCross
+	}
+	return Constraints{
+		MinSize: r.direction.size(minMain, minCross),
+		MaxSize: r.direction.size(maxMain, maxCross),
+	}
+}
+
+// position works out each child's offset from the main axis alignment and
+// the cross axis alignment
+func (r *RenderFlex) position(line flexLine, mainSize, crossSize int) {
+	leading, between := r.mainAxisAlignment.distribute(max(0, mainSize-line.used), len(r.children))
+
+	origin := r.ContentRect().Min
+	main := leading
+	for i, size := range line.sizes {
+		offset := r.direction.point(main, r.crossAxisAlignment.offset(crossSize-r.direction.cross(size)))
+		setChildOffset(r.children[i], origin.Add(offset))
+		main += r.direction.main(size) + r.spacing + between
+	}
+}
+
+// intrinsicExtent measures the flex along axis given the extent across it.
+// Along the main axis the children's extents add up; across it the largest
+// child wins, with each child measured for an unbounded main axis.
+func (r *RenderFlex) intrinsicExtent(axis Axis, extent int, minimum bool) int {
+	measure := intrinsicMeasure(axis, minimum)
+	return r.intrinsicWith(axis, extent, func(extent int) int {
+		if axis != r.direction {
+			return maxIntrinsic(r.children, math.MaxInt32, measure)
+		}
+		total := r.spacing * max(0, len(r.children)-1)
+		for _, child := range r.children {
+			total += measure(child, extent)
+		}
+		return total
+	})
+}
+
+func (r *RenderFlex) MinIntrinsicWidth(height int) int {
+	return r.intrinsicExtent(AxisHorizontal, height, true)
+}
+
+func (r *RenderFlex) MaxIntrinsicWidth(height int) int {
+	return r.intrinsicExtent(AxisHorizontal, height, false)
+}
+
+func (r *RenderFlex) MinIntrinsicHeight(width int) int {
+	return r.intrinsicExtent(AxisVertical, width, true)
+}
+
+func (r *RenderFlex) MaxIntrinsicHeight(width int) int {
+	return r.intrinsicExtent(AxisVertical, width, false)
+}
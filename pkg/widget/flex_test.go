@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// layoutFlex mounts a flex widget and lays it out within maxSize
+func layoutFlex(f *Flex, maxSize geometry.Size) *RenderFlex {
+	elem := NewElement(f)
+	elem.Mount(nil)
+	r := elem.RenderObject().(*RenderFlex)
+	r.Layout(NewConstraints(geometry.Size{}, maxSize))
+	return r
+}
+
+// mainOffsets returns the main axis position of every child
+func mainOffsets(r *RenderFlex) []int {
+	points := childOffsets(r)
+	offsets := make([]int, len(points))
+	for i, offset := range points {
+		offsets[i] = r.direction.main(geometry.Size{Width: offset.X, Height: offset.Y})
+	}
+	return offsets
+}
+
+func TestFlex_MainAxisAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment MainAxisAlignment
+		want      []int
+	}{
+		{"start", MainAxisStart, []int{0, 2}},
+		{"end", MainAxisEnd, []int{5, 7}},
+		{"center", MainAxisCenter, []int{2, 4}},
+		{"space between", MainAxisSpaceBetween, []int{0, 7}},
+		{"space around", MainAxisSpaceAround, []int{1, 5}},
+		{"space evenly", MainAxisSpaceEvenly, []int{1, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := NewRow(NewText("aa"), NewText("bbb")).WithMainAxisAlignment(tt.alignment)
+			r := layoutFlex(row, geometry.Size{Width: 10, Height: 5})
+
+			assert.Equal(t, tt.want, mainOffsets(r))
+			assert.Equal(t, geometry.Size{Width: 10, Height: 1}, r.Size())
+		})
+	}
+}
+
+func TestFlex_CrossAxisAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment CrossAxisAlignment
+		wantX     int
+		wantWidth int
+	}{
+		{"start", CrossAxisStart, 0, 2},
+		{"end", CrossAxisEnd, 8, 2},
+		{"center", CrossAxisCenter, 4, 2},
+		{"stretch", CrossAxisStretch, 0, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column := NewColumn(NewText("ab"), NewText("cdefghijkl")).WithCrossAxisAlignment(tt.alignment)
+			r := layoutFlex(column, geometry.Size{Width: 10, Height: 5})
+
+			assert.Equal(t, tt.wantX, childOffsets(r)[0].X)
+			assert.Equal(t, 1, childOffsets(r)[1].Y)
+			assert.Equal(t, tt.wantWidth, r.children[0].Size().Width)
+		})
+	}
+}
+
+func TestFlex_FlexibleChildren(t *testing.T) {
+	t.Run("expanded fills the free space", func(t *testing.T) {
+		row := NewRow(NewText("ab"), NewExpanded(NewText("x")), NewText("cd"))
+		r := layoutFlex(row, geometry.Size{Width: 10, Height: 1})
+
+		assert.Equal(t, 6, r.children[1].Size().Width)
+		assert.Equal(t, []int{0, 2, 8}, mainOffsets(r))
+	})
+
+	t.Run("free space is shared by flex factor", func(t *testing.T) {
+		row := NewRow(
+			NewExpanded(NewText("a")),
+			NewExpanded(NewText("b")).WithFlex(2),
+		)
+		r := layoutFlex(row, geometry.Size{Width: 10, Height: 1})
+
+		// 10 cells split 1:2 leaves one cell over, which goes to the first child
+		assert.Equal(t, 4, r.children[0].Size().Width)
+		assert.Equal(t, 6, r.children[1].Size().Width)
+	})
+
+	t.Run("flexible may be smaller than its share", func(t *testing.T) {
+		row := NewRow(NewFlexible(NewText("x")), NewText("end"))
+		r := layoutFlex(row, geometry.Size{Width: 10, Height: 1})
+
+		assert.Equal(t, 1, r.children[0].Size().Width)
+		assert.Equal(t, []int{0, 1}, mainOffsets(r))
+	})
+
+	t.Run("spacer pushes siblings apart", func(t *testing.T) {
+		row := NewRow(NewText("a"), NewSpacer(), NewText("b"))
+		r := layoutFlex(row, geometry.Size{Width: 10, Height: 1})
+
+		assert.Equal(t, []int{0, 1, 9}, mainOffsets(r))
+	})
+
+	t.Run("unbounded main axis lays flexible children out loosely", func(t *testing.T) {
+		column := NewColumn(NewExpanded(NewText("a")), NewText("b"))
+		r := layoutFlex(column, ConstraintsUnbounded.MaxSize)
+
+		assert.Equal(t, 1, r.children[0].Size().Height)
+		assert.Equal(t, []int{0, 1}, mainOffsets(r))
+		assert.Equal(t, 2, r.Size().Height)
+	})
+}
+
+func TestFlex_Sizing(t *testing.T) {
+	t.Run("main axis size min shrinks to the children", func(t *testing.T) {
+		row := NewRow(NewText("ab"), NewText("c")).WithMainAxisSize(MainAxisSizeMin)
+		r := layoutFlex(row, geometry.Size{Width: 10, Height: 5})
+
+		assert.Equal(t, geometry.Size{Width: 3, Height: 1}, r.Size())
+	})
+
+	t.Run("spacing separates children", func(t *testing.T) {
+		row := NewRow(NewText("a"), NewText("b"), NewText("c")).
+			WithSpacing(2).
+			WithMainAxisSize(MainAxisSizeMin)
+		r := layoutFlex(row, geometry.Size{Width: 20, Height: 1})
+
+		assert.Equal(t, []int{0, 3, 6}, mainOffsets(r))
+		assert.Equal(t, 7, r.Size().Width)
+	})
+
+	t.Run("padding surrounds the children", func(t *testing.T) {
+		style := NewWidgetStyle()
+		style.Padding = NewEdgeInsets(1, 1, 1, 1)
+		column := NewColumn(NewText("ab")).WithStyle(style).WithMainAxisSize(MainAxisSizeMin)
+		r := layoutFlex(column, geometry.Size{Width: 10, Height: 10})
+
+		assert.Equal(t, geometry.Size{Width: 4, Height: 3}, r.Size())
+		assert.Equal(t, geometry.Point{X: 1, Y: 1}, childOffset(r.children[0]))
+	})
+
+	t.Run("overflow is detected", func(t *testing.T) {
+		row := NewRow(NewText("aaaaa"), NewText("bbbbb"))
+		r := layoutFlex(row, geometry.Size{Width: 6, Height: 1})
+
+		assert.True(t, r.HasOverflow())
+		assert.Equal(t, 4, r.Overflow())
+		assert.Equal(t, 6, r.Size().Width)
+
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 1}))
+		assert.False(t, r.HasOverflow())
+	})
+}
+
+func TestFlex_Paint(t *testing.T) {
+	row := NewRow(NewText("ab"), NewSpacer(), NewColumn(NewText("c"), NewText("d")))
+	r := layoutFlex(row, geometry.Size{Width: 6, Height: 2})
+
+	ctx := NewMockRenderContext()
+	r.Paint(ctx)
+
+	assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 0, Y: 0}].Rune)
+	assert.Equal(t, 'b', ctx.cells[geometry.Point{X: 1, Y: 0}].Rune)
+	assert.Equal(t, 'c', ctx.cells[geometry.Point{X: 5, Y: 0}].Rune)
+	assert.Equal(t, 'd', ctx.cells[geometry.Point{X: 5, Y: 1}].Rune)
+	assert.Equal(t, geometry.Point{}, ctx.offset)
+}
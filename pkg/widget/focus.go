@@ -0,0 +1,300 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "sort"
+
+// FocusNode is a place keyboard focus can rest. A widget taking key input
+// attaches a node while it is mounted and only handles keys while its node
+// has focus. At most one node of a tree has focus at a time. Like the rest
+// of the tree, a node must only be used from the build goroutine.
+type FocusNode struct {
+	manager   *FocusManager
+	element   Element
+	canFocus  bool
+	listeners []*focusListener
+	// wantsFocus is set when focus is requested before the node is
+	// attached, so it takes focus as soon as it is
+	wantsFocus bool
+}
+
+// focusListener wraps a listener so it can be found again for removal
+type focusListener struct {
+	fn func()
+}
+
+// NewFocusNode creates a node that can take focus
+func NewFocusNode() *FocusNode {
+	return &FocusNode{canFocus: true}
+}
+
+// HasFocus reports whether the node has focus
+func (n *FocusNode) HasFocus() bool {
+	return n.manager != nil && n.manager.focused == n
+}
+
+// RequestFocus moves focus to the node. A node that isn't attached yet
+// takes focus when it is.
+func (n *FocusNode) RequestFocus() {
+	if !n.canFocus {
+		return
+	}
+	if n.manager == nil {
+		n.wantsFocus = true
+		return
+	}
+	n.manager.setFocus(n)
+}
+
+// Unfocus removes focus from the node if it has it
+func (n *FocusNode) Unfocus() {
+	n.wantsFocus = false
+	if n.HasFocus() {
+		n.manager.setFocus(nil)
+	}
+}
+
+// CanRequestFocus reports whether the node can take focus
+func (n *FocusNode) CanRequestFocus() bool {
+	return n.canFocus
+}
+
+// SetCanRequestFocus sets whether the node can take focus, as disabled
+// controls can't. A node that can't is skipped by traversal and loses
+// focus if it has it.
+func (n *FocusNode) SetCanRequestFocus(canFocus bool) {
+	n.canFocus = canFocus
+	if !canFocus {
+		n.Unfocus()
+	}
+}
+
+// NextFocus moves focus to the node after this one in tree order, wrapping
+// around, and reports whether it moved
+func (n *FocusNode) NextFocus() bool {
+	return n.manager != nil && n.manager.traverse(n, 1)
+}
+
+// PreviousFocus moves focus to the node before this one in tree order,
+// wrapping around, and reports whether it moved
+func (n *FocusNode) PreviousFocus() bool {
+	return n.manager != nil && n.manager.traverse(n, -1)
+}
+
+// AddListener registers fn to be called whenever the node gains or loses
+// focus and returns a function that removes it again
+func (n *FocusNode) AddListener(fn func()) (remove func()) {
+	listener := &focusListener{fn: fn}
+	n.listeners = append(n.listeners, listener)
+	return func() {
+		for i, l := range n.listeners {
+			if l == listener {
+				n.listeners = append(n.listeners[:i:i], n.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (n *FocusNode) notify() {
+	for _, listener := range append([]*focusListener(nil), n.listeners...) {
+		listener.fn()
+	}
+}
+
+// attach adds the node to the focus manager of element's owner. Without
+// an owner the node stays detached and can't take focus.
+func (n *FocusNode) attach(element Element) {
+	owner := element.Owner()
+	if owner == nil || n.manager == owner.focus {
+		n.element = element
+		return
+	}
+	n.detach()
+	n.element = element
+	n.manager = owner.focus
+	n.manager.nodes = append(n.manager.nodes, n)
+	if n.wantsFocus {
+		n.wantsFocus = false
+		n.RequestFocus()
+	}
+}
+
+// detach removes the node from its focus manager, dropping focus if it
+// has it
+func (n *FocusNode) detach() {
+	m := n.manager
+	if m == nil {
+		return
+	}
+	if m.focused == n {
+		m.setFocus(nil)
+	}
+	for i, node := range m.nodes {
+		if node == n {
+			m.nodes = append(m.nodes[:i:i], m.nodes[i+1:]...)
+			break
+		}
+	}
+	n.manager = nil
+	n.element = nil
+}
+
+// focusBinding keeps a listener on the focus node a widget was given, or
+// on a node of its own when it has none, and keeps the node attached to
+// the widget's element
+type focusBinding struct {
+	node    *FocusNode
+	owned   *FocusNode
+	element Element
+	remove  func()
+}
+
+// bind attaches node, or the owned node if it is nil, to element and
+// listens to it, moving the listener over if the node changed. It returns
+// the node in use.
+func (b *focusBinding) bind(node *FocusNode, element Element, listener func()) *FocusNode {
+	if node == nil {
+		if b.owned == nil {
+			b.owned = NewFocusNode()
+		}
+		node = b.owned
+	}
+	if node != b.node {
+		b.unbind()
+		b.node = node
+		b.remove = node.AddListener(listener)
+	}
+	b.element = element
+	node.attach(element)
+	return node
+}
+
+// unbind removes the listener and detaches the node if it is still
+// attached to the element it was bound for
+func (b *focusBinding) unbind() {
+	if b.remove != nil {
+		b.remove()
+		b.remove = nil
+	}
+	if b.node != nil && b.node.element == b.element {
+		b.node.detach()
+	}
+}
+
+// FocusManager tracks the focus nodes attached to a tree and which of them
+// has focus
+type FocusManager struct {
+	focused *FocusNode
+	nodes   []*FocusNode
+}
+
+func newFocusManager() *FocusManager {
+	return &FocusManager{}
+}
+
+// Focused returns the node with focus, or nil
+func (m *FocusManager) Focused() *FocusNode {
+	return m.focused
+}
+
+// NextFocus moves focus to the next node in tree order, or to the first
+// node when none has focus, and reports whether it moved
+func (m *FocusManager) NextFocus() bool {
+	return m.traverse(m.focused, 1)
+}
+
+// PreviousFocus moves focus to the previous node in tree order, or to the
+// last node when none has focus, and reports whether it moved
+func (m *FocusManager) PreviousFocus() bool {
+	return m.traverse(m.focused, -1)
+}
+
+// setFocus moves focus to node, telling both nodes involved
+func (m *FocusManager) setFocus(node *FocusNode) {
+	previous := m.focused
+	if previous == node {
+		return
+	}
+	m.focused = node
+	if previous != nil {
+		previous.notify()
+	}
+	if node != nil {
+		node.notify()
+	}
+}
+
+// traverse moves focus from node to the next node that can take it in
+// direction, wrapping around
+func (m *FocusManager) traverse(from *FocusNode, direction int) bool {
+	nodes := m.ordered()
+	start := -1
+	for i, node := range nodes {
+		if node == from {
+			start = i
+		}
+	}
+	if start < 0 && direction < 0 {
+		start = len(nodes)
+	}
+
+	for step := 1; step <= len(nodes); step++ {
+		i := ((start+step*direction)%len(nodes) + len(nodes)) % len(nodes)
+		if node := nodes[i]; node != from && node.canFocus {
+			m.setFocus(node)
+			return true
+		}
+	}
+	return false
+}
+
+// ordered returns the attached nodes in the order their elements appear in
+// the tree
+func (m *FocusManager) ordered() []*FocusNode {
+	nodes := append([]*FocusNode(nil), m.nodes...)
+	paths := make(map[*FocusNode][]int, len(nodes))
+	for _, node := range nodes {
+		paths[node] = treePath(node.element)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return comparePaths(paths[nodes[i]], paths[nodes[j]]) < 0
+	})
+	return nodes
+}
+
+// treePath returns the index of element among its siblings, and of each
+// of its ancestors among theirs, from the root down
+func treePath(element Element) []int {
+	var path []int
+	for element != nil {
+		parent := element.Parent()
+		if parent == nil {
+			break
+		}
+		for i, child := range parent.Children() {
+			if child == element {
+				path = append(path, i)
+				break
+			}
+		}
+		element = parent
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// comparePaths orders tree paths depth first, parents before children
+func comparePaths(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return len(a) - len(b)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mountFocusNodes mounts a column of inputs using nodes, in order
+func mountFocusNodes(nodes ...*FocusNode) (*BuildOwner, Element) {
+	children := make([]Widget, len(nodes))
+	for i, node := range nodes {
+		children[i] = NewTextInput().WithFocusNode(node)
+	}
+	owner := NewBuildOwner()
+	root := NewElement(NewColumn(children...))
+	owner.MountRoot(root)
+	return owner, root
+}
+
+func TestFocusManager_Traversal(t *testing.T) {
+	a, b, c := NewFocusNode(), NewFocusNode(), NewFocusNode()
+	owner, _ := mountFocusNodes(a, b, c)
+	m := owner.FocusManager()
+
+	t.Run("forward wraps around", func(t *testing.T) {
+		var order []*FocusNode
+		for i := 0; i < 4; i++ {
+			assert.True(t, m.NextFocus())
+			order = append(order, m.Focused())
+		}
+		assert.Equal(t, []*FocusNode{a, b, c, a}, order)
+	})
+
+	t.Run("backward wraps around", func(t *testing.T) {
+		assert.True(t, m.PreviousFocus())
+		assert.Same(t, c, m.Focused())
+		assert.True(t, c.PreviousFocus())
+		assert.Same(t, b, m.Focused())
+	})
+
+	t.Run("skips nodes that can't take focus", func(t *testing.T) {
+		a.RequestFocus()
+		b.SetCanRequestFocus(false)
+		assert.True(t, m.NextFocus())
+		assert.Same(t, c, m.Focused())
+
+		b.RequestFocus()
+		assert.Same(t, c, m.Focused())
+		b.SetCanRequestFocus(true)
+	})
+
+	t.Run("backward from nothing starts at the last node", func(t *testing.T) {
+		m.Focused().Unfocus()
+		assert.Nil(t, m.Focused())
+		assert.True(t, m.PreviousFocus())
+		assert.Same(t, c, m.Focused())
+	})
+}
+
+func TestFocusNode_Listeners(t *testing.T) {
+	a, b := NewFocusNode(), NewFocusNode()
+	mountFocusNodes(a, b)
+
+	var events []string
+	a.AddListener(func() { events = append(events, "a") })
+	remove := b.AddListener(func() { events = append(events, "b") })
+
+	a.RequestFocus()
+	a.RequestFocus()
+	b.RequestFocus()
+	remove()
+	a.RequestFocus()
+
+	assert.Equal(t, []string{"a", "a", "b", "a"}, events)
+}
+
+func TestFocusNode_RequestBeforeAttach(t *testing.T) {
+	node := NewFocusNode()
+	node.RequestFocus()
+	assert.False(t, node.HasFocus())
+
+	owner, _ := mountFocusNodes(NewFocusNode(), node)
+	assert.True(t, node.HasFocus())
+	assert.Same(t, node, owner.FocusManager().Focused())
+}
+
+func TestFocusNode_DetachDropsFocus(t *testing.T) {
+	node := NewFocusNode()
+	owner, root := mountFocusNodes(node)
+	node.RequestFocus()
+
+	root.Unmount()
+	assert.False(t, node.HasFocus())
+	assert.Nil(t, owner.FocusManager().Focused())
+	assert.False(t, node.NextFocus())
+}
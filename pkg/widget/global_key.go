@@ -0,0 +1,193 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"fmt"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// GlobalKey identifies a single element across the whole tree. A widget
+// keyed with a GlobalKey keeps its element, state and subtree when it moves
+// to a different parent within one build, and the key gives access to the
+// element while it is mounted. Lookups must happen on the build goroutine.
+type GlobalKey struct {
+	label string
+	owner *BuildOwner
+}
+
+// NewGlobalKey creates a global key. The label is only used for debugging.
+func NewGlobalKey(label string) *GlobalKey {
+	return &GlobalKey{label: label}
+}
+
+func (k *GlobalKey) String() string {
+	if k.label != "" {
+		return fmt.Sprintf("[GlobalKey %s]", k.label)
+	}
+	return fmt.Sprintf("[GlobalKey %p]", k)
+}
+
+// CurrentElement returns the element the key is mounted at, or nil
+func (k *GlobalKey) CurrentElement() Element {
+	if k.owner == nil {
+		return nil
+	}
+	return k.owner.elementForKey(k)
+}
+
+// CurrentState returns the state of the keyed stateful widget, or nil
+func (k *GlobalKey) CurrentState() State {
+	if element, ok := k.CurrentElement().(StatefulElement); ok {
+		return element.State()
+	}
+	return nil
+}
+
+// CurrentRenderObject returns the render object of the keyed widget, or nil
+func (k *GlobalKey) CurrentRenderObject() RenderObject {
+	if element := k.CurrentElement(); element != nil {
+		return element.RenderObject()
+	}
+	return nil
+}
+
+// CurrentRect returns where the keyed widget was laid out, relative to the
+// root of the tree. It reports false when the key is not mounted.
+func (k *GlobalKey) CurrentRect() (geometry.Rect, bool) {
+	element := k.CurrentElement()
+	if element == nil || element.RenderObject() == nil {
+		return geometry.Rect{}, false
+	}
+
+	origin := renderOrigin(element)
+	size := element.RenderObject().Size()
+	return geometry.Rect{
+		Min: origin,
+		Max: geometry.Point{X: origin.X + size.Width, Y: origin.Y + size.Height},
+	}, true
+}
+
+// renderOrigin returns the position of an element's render object relative
+// to the root render object
+func renderOrigin(element Element) geometry.Point {
+	origin, _ := renderOffsetWithin(element, nil)
+	return origin
+}
+
+// renderOffsetWithin returns the position of an element's render object
+// relative to ancestor, one of the render objects above it, or to the root
+// when ancestor is nil. It reports false when ancestor is not above it.
+func renderOffsetWithin(element Element, ancestor RenderObject) (geometry.Point, bool) {
+	var origin geometry.Point
+	child := element.RenderObject()
+	for node := element.Parent(); child != ancestor; node = node.Parent() {
+		if node == nil {
+			return origin, ancestor == nil
+		}
+		parent := node.RenderObject()
+		if parent == nil || parent == child {
+			// Component elements share their child's render object
+			continue
+		}
+		origin = origin.Add(childOffset(child))
+		child = parent
+	}
+	return origin, true
+}
+
+// registerGlobalKey records the element in its owner's key registry when
+// its widget has a global key
+func (e *BaseElement) registerGlobalKey() {
+	key, ok := e.widget.GetKey().(*GlobalKey)
+	if !ok || e.owner == nil {
+		return
+	}
+	e.owner.registerGlobalKey(key, e.element())
+}
+
+// unregisterGlobalKey removes the element from its owner's key registry
+func (e *BaseElement) unregisterGlobalKey() {
+	key, ok := e.widget.GetKey().(*GlobalKey)
+	if !ok || e.owner == nil {
+		return
+	}
+	e.owner.unregisterGlobalKey(key, e.element())
+}
+
+// childForgetter is implemented by elements that can drop a child which
+// has been moved elsewhere in the tree
+type childForgetter interface {
+	forgetChild(child Element)
+}
+
+// forgetChild removes a child without unmounting it
+func (e *BaseElement) forgetChild(child Element) {
+	for i, c := range e.children {
+		if c == child {
+			e.children = append(e.children[:i:i], e.children[i+1:]...)
+			e.childRenderObjectChanged()
+			return
+		}
+	}
+}
+
+// reparentable is implemented by elements that can move to a new parent
+// while staying mounted
+type reparentable interface {
+	reparent(parent Element)
+}
+
+// reparent attaches a mounted element to a new parent and updates the
+// depth of its subtree. Elements in the subtree that depended on inherited
+// widgets rebuild to find the ones above their new position.
+func (e *BaseElement) reparent(parent Element) {
+	e.parent = parent
+	e.updateDepth(parent.Depth() + 1)
+	reactivateDependencies(e.element())
+}
+
+// depthUpdater is implemented by elements whose depth can be corrected
+// after their subtree moved
+type depthUpdater interface {
+	updateDepth(depth int)
+}
+
+func (e *BaseElement) updateDepth(depth int) {
+	e.depth = depth
+	for _, child := range e.children {
+		if updater, ok := child.(depthUpdater); ok {
+			updater.updateDepth(depth + 1)
+		}
+	}
+}
+
+// retakeGlobalKey moves the element mounted for a global key under this
+// element so it can be updated to w. It returns nil when there is no such
+// element or it cannot be updated to w.
+func (e *BaseElement) retakeGlobalKey(key *GlobalKey, w Widget) Element {
+	if e.owner == nil {
+		return nil
+	}
+
+	element := e.owner.takeElementForKey(key, w, e.element())
+	if element == nil {
+		return nil
+	}
+	element.(reparentable).reparent(e.element())
+	return element
+}
+
+// isAncestor reports whether ancestor is node or one of its ancestors
+func isAncestor(ancestor, node Element) bool {
+	for n := node; n != nil; n = n.Parent() {
+		if n == ancestor {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// splitWidget shows a keyed panel in either its left or right pane
+type splitWidget struct {
+	BaseWidget
+	panel *countingWidget
+	state *splitState
+}
+
+func (w *splitWidget) CreateState() State {
+	w.state = &splitState{left: true}
+	return w.state
+}
+
+type splitState struct {
+	BaseState
+	left bool
+}
+
+func (s *splitState) Build(context BuildContext) Widget {
+	panel := s.Widget().(*splitWidget).panel
+	left, right := NewBox(), NewBox()
+	if s.left {
+		left.AppendChild(panel)
+	} else {
+		right.AppendChild(panel)
+	}
+
+	split := NewBox()
+	split.AppendChild(left)
+	split.AppendChild(right)
+	return split
+}
+
+func newKeyedPanel(key *GlobalKey) *countingWidget {
+	panel := &countingWidget{child: NewText("panel")}
+	panel.WithKey(key)
+	return panel
+}
+
+// pane returns the element of the left (0) or right (1) pane
+func pane(root Element, index int) Element {
+	return root.Children()[0].Children()[index]
+}
+
+func TestGlobalKey_Lookup(t *testing.T) {
+	key := NewGlobalKey("panel")
+	assert.Nil(t, key.CurrentElement())
+	assert.Nil(t, key.CurrentState())
+
+	owner := NewBuildOwner()
+	panel := newKeyedPanel(key)
+	box := NewBox()
+	box.AppendChild(panel)
+	root := NewElement(box)
+	owner.MountRoot(root)
+
+	element := root.Children()[0]
+	assert.Same(t, element, key.CurrentElement())
+	assert.Same(t, panel.state, key.CurrentState())
+	assert.Same(t, element.RenderObject(), key.CurrentRenderObject())
+	assert.Contains(t, key.String(), "panel")
+
+	// Removing the widget unregisters the key once the build scope ends
+	box.children = nil
+	owner.ScheduleBuildFor(root)
+	owner.BuildScope()
+	assert.Nil(t, key.CurrentElement())
+	assert.Nil(t, key.CurrentRenderObject())
+}
+
+func TestGlobalKey_Reparent(t *testing.T) {
+	key := NewGlobalKey("panel")
+	panel := newKeyedPanel(key)
+	split := &splitWidget{panel: panel}
+
+	owner := NewBuildOwner()
+	root := NewElement(split)
+	owner.MountRoot(root)
+
+	element := key.CurrentElement()
+	state := key.CurrentState()
+	assert.Same(t, pane(root, 0), element.Parent())
+
+	move := func(left bool) {
+		split.state.SetState(func() { split.state.left = left })
+		owner.BuildScope()
+	}
+
+	t.Run("to a later pane", func(t *testing.T) {
+		move(false)
+
+		assert.Same(t, element, key.CurrentElement())
+		assert.Same(t, state, key.CurrentState())
+		assert.Same(t, pane(root, 1), element.Parent())
+		assert.Equal(t, pane(root, 1).Depth()+1, element.Depth())
+		assert.Empty(t, pane(root, 0).Children())
+		assert.Equal(t, []RenderObject{element.RenderObject()}, pane(root, 1).RenderObject().Children())
+		assert.Empty(t, pane(root, 0).RenderObject().Children())
+	})
+
+	t.Run("to an earlier pane", func(t *testing.T) {
+		move(true)
+
+		assert.Same(t, element, key.CurrentElement())
+		assert.Same(t, state, key.CurrentState())
+		assert.Same(t, pane(root, 0), element.Parent())
+		assert.Empty(t, pane(root, 1).Children())
+		assert.Equal(t, []RenderObject{element.RenderObject()}, pane(root, 0).RenderObject().Children())
+		assert.Empty(t, pane(root, 1).RenderObject().Children())
+	})
+
+	// The panel was never torn down
+	assert.Equal(t, 1, len(element.Children()))
+	assert.NotNil(t, element.(StatefulElement).State())
+}
+
+func TestGlobalKey_CurrentRect(t *testing.T) {
+	key := NewGlobalKey("")
+	box := NewBox()
+	box.WithStyle(NewWidgetStyle().WithPadding(NewEdgeInsets(1, 0, 0, 2)))
+	box.AppendChild(newKeyedPanel(key))
+
+	owner := NewBuildOwner()
+	root := NewElement(box)
+	owner.MountRoot(root)
+
+	_, ok := key.CurrentRect()
+	assert.True(t, ok)
+
+	root.RenderObject().Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 5}))
+
+	rect, ok := key.CurrentRect()
+	assert.True(t, ok)
+	assert.Equal(t, geometry.NewRect(2, 1, 5, 1), rect)
+
+	_, ok = NewGlobalKey("unmounted").CurrentRect()
+	assert.False(t, ok)
+}
@@ -0,0 +1,646 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"strings"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// GridAlignment controls how a child is placed within its grid cell along
+// one axis
+type GridAlignment int
+
+const (
+	// GridStretch makes the child fill the cell
+	GridStretch GridAlignment = iota
+	// GridStart places the child at the start of the cell
+	GridStart
+	// GridEnd places the child at the end of the cell
+	GridEnd
+	// GridCenter places the child in the middle of the cell
+	GridCenter
+)
+
+// Grid lays out its children in rows and columns. Track sizes may be fixed,
+// flexible, sized to their content or bounded by a minimum and maximum.
+// Children wrapped in GridItem choose their cell, span or named area; the
+// others fill the free cells row by row, adding rows as needed.
+type Grid struct {
+	BaseWidget
+	columns      []TrackSize
+	rows         []TrackSize
+	areas        []string
+	rowGap       int
+	columnGap    int
+	justifyItems GridAlignment
+	alignItems   GridAlignment
+	children     []Widget
+}
+
+// NewGrid creates a grid of children. Without columns the grid has a single
+// auto column.
+func NewGrid(children ...Widget) *Grid {
+	return &Grid{
+		BaseWidget: BaseWidget{
+			style: NewWidgetStyle(),
+		},
+		children: children,
+	}
+}
+
+func (g *Grid) AppendChild(child Widget) {
+	g.children = append(g.children, child)
+}
+
+// GetChildren returns the child widgets of the grid
+func (g *Grid) GetChildren() []Widget {
+	return g.children
+}
+
+// WithColumns sets the column tracks
+func (g *Grid) WithColumns(columns ...TrackSize) *Grid {
+	g.columns = columns
+	return g
+}
+
+// WithRows sets the row tracks. Rows added to fit extra children are auto.
+func (g *Grid) WithRows(rows ...TrackSize) *Grid {
+	g.rows = rows
+	return g
+}
+
+// WithAreas names regions of the grid, one string per row with one name per
+// column separated by spaces. A "." leaves the cell unnamed. Children are
+// placed in an area with GridItem.WithArea.
+func (g *Grid) WithAreas(rows ...string) *Grid {
+	g.areas = rows
+	return g
+}
+
+// WithGap sets the number of cells between both rows and columns
+func (g *Grid) WithGap(gap int) *Grid {
+	return g.WithRowGap(gap).WithColumnGap(gap)
+}
+
+// WithRowGap sets the number of cells between rows
+func (g *Grid) WithRowGap(gap int) *Grid {
+	g.rowGap = max(0, gap)
+	return g
+}
+
+// WithColumnGap sets the number of cells between columns
+func (g *Grid) WithColumnGap(gap int) *Grid {
+	g.columnGap = max(0, gap)
+	return g
+}
+
+// WithJustifyItems sets the default horizontal alignment of children in
+// their cells
+func (g *Grid) WithJustifyItems(alignment GridAlignment) *Grid {
+	g.justifyItems = alignment
+	return g
+}
+
+// WithAlignItems sets the default vertical alignment of children in their
+// cells
+func (g *Grid) WithAlignItems(alignment GridAlignment) *Grid {
+	g.alignItems = alignment
+	return g
+}
+
+// WithStyle sets the style of the grid
+func (g *Grid) WithStyle(style WidgetStyle) *Grid {
+	g.style = style
+	return g
+}
+
+func (g *Grid) Build(context BuildContext) Widget {
+	return g
+}
+
+// CreateRenderObject creates the grid's render object. The render objects of
+// the children are attached by the grid's element.
+func (g *Grid) CreateRenderObject() RenderObject {
+	r := NewRenderGrid()
+	g.UpdateRenderObject(r)
+	return r
+}
+
+func (g *Grid) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderGrid); ok {
+		r.WithStyle(g.style)
+		r.columns = g.columns
+		r.rows = g.rows
+		r.areas = parseGridAreas(g.areas)
+		r.rowGap = g.rowGap
+		r.columnGap = g.columnGap
+		r.justifyItems = g.justifyItems
+		r.alignItems = g.alignItems
+	}
+}
+
+// GridItem places a child of a Grid in a particular cell or named area and
+// lets it span several tracks or override the grid's alignment
+type GridItem struct {
+	BaseWidget
+	child     Widget
+	placement gridPlacement
+	justify   *GridAlignment
+	align     *GridAlignment
+}
+
+// NewGridItem creates a grid item spanning a single cell. Unless a cell or
+// area is set, it is placed in the next free cell.
+func NewGridItem(child Widget) *GridItem {
+	return &GridItem{
+		child:     child,
+		placement: gridPlacement{row: -1, column: -1, rowSpan: 1, columnSpan: 1},
+	}
+}
+
+// WithCell places the item with its top left corner at row and column,
+// counting from zero
+func (i *GridItem) WithCell(row, column int) *GridItem {
+	i.placement.row = max(0, row)
+	i.placement.column = max(0, column)
+	return i
+}
+
+// WithSpan sets how many rows and columns the item covers
+func (i *GridItem) WithSpan(rows, columns int) *GridItem {
+	i.placement.rowSpan = max(1, rows)
+	i.placement.columnSpan = max(1, columns)
+	return i
+}
+
+// WithArea places the item in a named area of the grid, covering all of it
+func (i *GridItem) WithArea(name string) *GridItem {
+	i.placement.area = name
+	return i
+}
+
+// WithJustifySelf sets the horizontal alignment of the item in its cell
+func (i *GridItem) WithJustifySelf(alignment GridAlignment) *GridItem {
+	i.justify = &alignment
+	return i
+}
+
+// WithAlignSelf sets the vertical alignment of the item in its cell
+func (i *GridItem) WithAlignSelf(alignment GridAlignment) *GridItem {
+	i.align = &alignment
+	return i
+}
+
+// GetChildren returns the wrapped child, if any
+func (i *GridItem) GetChildren() []Widget {
+	if i.child == nil {
+		return nil
+	}
+	return []Widget{i.child}
+}
+
+func (i *GridItem) Build(context BuildContext) Widget {
+	return i
+}
+
+func (i *GridItem) CreateRenderObject() RenderObject {
+	r := &renderProxy{}
+	i.UpdateRenderObject(r)
+	return r
+}
+
+func (i *GridItem) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderProxy); ok {
+		r.style = i.style
+		data, ok := r.parentData.(*GridParentData)
+		if !ok {
+			data = &GridParentData{}
+			r.parentData = data
+		}
+		data.Row, data.Column = i.placement.row, i.placement.column
+		data.RowSpan, data.ColumnSpan = i.placement.rowSpan, i.placement.columnSpan
+		data.Area = i.placement.area
+		data.JustifySelf = i.justify
+		data.AlignSelf = i.align
+	}
+}
+
+// gridPlacement is where a child sits in the grid. A negative row or column
+// means the child is placed automatically.
+type gridPlacement struct {
+	row        int
+	column     int
+	rowSpan    int
+	columnSpan int
+	area       string
+}
+
+// GridParentData is the parent data of a child of a RenderGrid
+type GridParentData struct {
+	BoxParentData
+	// Row and Column are the child's top left cell, counting from zero. A
+	// negative row or column places the child automatically.
+	Row    int
+	Column int
+	// RowSpan and ColumnSpan are how many rows and columns the child covers
+	RowSpan    int
+	ColumnSpan int
+	// Area names the area of the grid the child covers, overriding its cell
+	Area string
+	// JustifySelf and AlignSelf override the grid's alignment of the child
+	// in its cell when set
+	JustifySelf *GridAlignment
+	AlignSelf   *GridAlignment
+}
+
+// placement returns the cell the child asks for
+func (d *GridParentData) placement() gridPlacement {
+	return gridPlacement{
+		row:        d.Row,
+		column:     d.Column,
+		rowSpan:    max(1, d.RowSpan),
+		columnSpan: max(1, d.ColumnSpan),
+		area:       d.Area,
+	}
+}
+
+// parseGridAreas turns area template rows into the cells each name covers
+func parseGridAreas(rows []string) map[string]gridPlacement {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	areas := make(map[string]gridPlacement)
+	for row, line := range rows {
+		for column, name := range strings.Fields(line) {
+			if name == "." {
+				continue
+			}
+			area, ok := areas[name]
+			if !ok {
+				areas[name] = gridPlacement{row: row, column: column, rowSpan: 1, columnSpan: 1}
+				continue
+			}
+			// Areas cover the bounding box of every cell with their name
+			area.rowSpan = max(area.rowSpan, row-area.row+1)
+			area.columnSpan = max(area.column+area.columnSpan, column+1) - min(area.column, column)
+			area.column = min(area.column, column)
+			areas[name] = area
+		}
+	}
+	return areas
+}
+
+// gridCell is a child of the grid with its resolved placement
+type gridCell struct {
+	child     RenderObject
+	placement gridPlacement
+	justify   GridAlignment
+	align     GridAlignment
+}
+
+// RenderGrid lays out its children in rows and columns inside the box model
+// of its style
+type RenderGrid struct {
+	BaseRenderBox
+	columns      []TrackSize
+	rows         []TrackSize
+	areas        map[string]gridPlacement
+	rowGap       int
+	columnGap    int
+	justifyItems GridAlignment
+	alignItems   GridAlignment
+
+	// Results of the last layout
+	columnSizes []int
+	rowSizes    []int
+}
+
+// NewRenderGrid creates an empty render grid
+func NewRenderGrid() *RenderGrid {
+	return &RenderGrid{
+		BaseRenderBox: *NewBaseRenderBox(),
+	}
+}
+
+// ColumnSizes returns the width of every column after the last layout
+func (r *RenderGrid) ColumnSizes() []int {
+	return r.columnSizes
+}
+
+// RowSizes returns the height of every row after the last layout
+func (r *RenderGrid) RowSizes() []int {
+	return r.rowSizes
+}
+
+func (r *RenderGrid) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	content := r.layoutGrid(r.contentConstraints(constraints))
+	r.size = constraints.Constrain(r.outerSize(content))
+	return r.size
+}
+
+// layoutGrid places the children, sizes the columns and then the rows, and
+// lays out every child in its cell. It returns the content size.
+func (r *RenderGrid) layoutGrid(constraints Constraints) geometry.Size {
+	columns := r.columnTracks()
+	cells, rowCount := r.placeCells(len(columns))
+	rows := r.rowTracks(rowCount)
+
+	r.columnSizes = sizeTracks(columns, r.columnItems(cells), constraints.MaxSize.Width, r.columnGap)
+	r.rowSizes = sizeTracks(rows, r.rowItems(cells), constraints.MaxSize.Height, r.rowGap)
+
+	r.layoutCells(cells)
+
+	return constraints.Constrain(geometry.Size{
+		Width:  trackExtent(r.columnSizes, 0, len(r.columnSizes), r.columnGap),
+		Height: trackExtent(r.rowSizes, 0, len(r.rowSizes), r.rowGap),
+	})
+}
+
+// columnTracks returns the column tracks, falling back to one auto column
+// per named area column or a single auto column
+func (r *RenderGrid) columnTracks() []TrackSize {
+	if len(r.columns) > 0 {
+		return r.columns
+	}
+	count := 1
+	for _, area := range r.areas {
+		count = max(count, area.column+area.columnSpan)
+	}
+	tracks := make([]TrackSize, count)
+	for i := range tracks {
+		tracks[i] = TrackAuto()
+	}
+	return tracks
+}
+
+// rowTracks returns the row tracks, adding auto rows up to count
+func (r *RenderGrid) rowTracks(count int) []TrackSize {
+	tracks := make([]TrackSize, max(count, len(r.rows)))
+	copy(tracks, r.rows)
+	for i := len(r.rows); i < len(tracks); i++ {
+		tracks[i] = TrackAuto()
+	}
+	return tracks
+}
+
+// placeCells resolves where every child goes and returns the cells along
+// with the number of rows they need. Children with a cell or area are
+// placed first; the rest fill the free cells in order.
+func (r *RenderGrid) placeCells(columnCount int) ([]gridCell, int) {
+	cells := make([]gridCell, len(r.children))
+	grid := newGridOccupancy(columnCount)
+
+	var auto []int
+	for i, child := range r.children {
+		cells[i] = r.cellFor(child, columnCount)
+		if cells[i].placement.row < 0 {
+			auto = append(auto, i)
+			continue
+		}
+		grid.occupy(cells[i].placement)
+	}
+
+	for _, i := range auto {
+		cells[i].placement = grid.next(cells[i].placement)
+		grid.occupy(cells[i].placement)
+	}
+
+	return cells, max(grid.rows, r.areaRowCount())
+}
+
+// cellFor returns the requested placement and alignment of a child
+func (r *RenderGrid) cellFor(child RenderObject, columnCount int) gridCell {
+	cell := gridCell{
+		child:     child,
+		placement: gridPlacement{row: -1, column: -1, rowSpan: 1, columnSpan: 1},
+		justify:   r.justifyItems,
+		align:     r.alignItems,
+	}
+
+	data, ok := child.ParentData().(*GridParentData)
+	if !ok {
+		return cell
+	}
+	cell.placement = data.placement()
+	if data.Area != "" {
+		if area, ok := r.areas[data.Area]; ok {
+			cell.placement = area
+		}
+	}
+	if data.JustifySelf != nil {
+		cell.justify = *data.JustifySelf
+	}
+	if data.AlignSelf != nil {
+		cell.align = *data.AlignSelf
+	}
+	cell.placement = clampPlacement(cell.placement, columnCount)
+	return cell
+}
+
+// clampPlacement keeps an explicitly placed child inside the columns
+func clampPlacement(p gridPlacement, columnCount int) gridPlacement {
+	p.columnSpan = min(p.columnSpan, columnCount)
+	if p.row < 0 || p.column < 0 {
+		p.row, p.column = -1, -1
+		return p
+	}
+	p.column = min(p.column, columnCount-p.columnSpan)
+	return p
+}
+
+// areaRowCount returns the number of rows the named areas cover
+func (r *RenderGrid) areaRowCount() int {
+	count := 0
+	for _, area := range r.areas {
+		count = max(count, area.row+area.rowSpan)
+	}
+	return count
+}
+
+// columnItems returns the width every child asks for
+func (r *RenderGrid) columnItems(cells []gridCell) []trackItem {
+	items := make([]trackItem, len(cells))
+	for i, cell := range cells {
+		size := LayoutIfNeeded(cell.child, ConstraintsUnbounded)
+		items[i] = trackItem{start: cell.placement.column, span: cell.placement.columnSpan, size: size.Width}
+	}
+	return items
+}
+
+// rowItems returns the height every child asks for once the columns are
+// sized
+func (r *RenderGrid) rowItems(cells []gridCell) []trackItem {
+	items := make([]trackItem, len(cells))
+	for i, cell := range cells {
+		p := cell.placement
+		width := trackExtent(r.columnSizes, p.column, p.columnSpan, r.columnGap)
+		minWidth := 0
+		if cell.justify == GridStretch {
+			minWidth = width
+		}
+		size := LayoutIfNeeded(cell.child, NewConstraints(
+			geometry.Size{Width: minWidth},
+			geometry.Size{Width: width, Height: math.MaxInt32},
+		))
+		items[i] = trackItem{start: p.row, span: p.rowSpan, size: size.Height}
+	}
+	return items
+}
+
+// layoutCells lays out every child in its cell and aligns it there
+func (r *RenderGrid) layoutCells(cells []gridCell) {
+	columnOffsets := trackOffsets(r.columnSizes, r.columnGap)
+	rowOffsets := trackOffsets(r.rowSizes, r.rowGap)
+
+	origin := r.ContentRect().Min
+	for _, cell := range cells {
+		p := cell.placement
+		width := trackExtent(r.columnSizes, p.column, p.columnSpan, r.columnGap)
+		height := trackExtent(r.rowSizes, p.row, p.rowSpan, r.rowGap)
+
+		minSize := geometry.Size{}
+		if cell.justify == GridStretch {
+			minSize.Width = width
+		}
+		if cell.align == GridStretch {
+			minSize.Height = height
+		}
+		size := LayoutIfNeeded(cell.child, NewConstraints(minSize, geometry.Size{Width: width, Height: height}))
+
+		setChildOffset(cell.child, origin.Add(geometry.Point{
+			X: columnOffsets[p.column] + alignInCell(cell.justify, width-size.Width),
+			Y: rowOffsets[p.row] + alignInCell(cell.align, height-size.Height),
+		}))
+	}
+}
+
+// alignInCell returns the offset of a child within its cell given the space
+// it leaves free
+func alignInCell(alignment GridAlignment, free int) int {
+	switch alignment {
+	case GridEnd:
+		return free
+	case GridCenter:
+		return free / 2
+	default:
+		return 0
+	}
+}
+
+// gridIntrinsic measures the grid by sizing its tracks along axis from the
+// children's intrinsic sizes, without bounding that axis. Heights size the
+// columns for the given width first.
+func (r *RenderGrid) gridIntrinsic(axis Axis, extent int, minimum bool) int {
+	return r.intrinsicWith(axis, extent, func(extent int) int {
+		columns := r.columnTracks()
+		cells, rowCount := r.placeCells(len(columns))
+		if axis == AxisHorizontal {
+			sizes := sizeTracks(columns, r.intrinsicItems(cells, axis, minimum, nil), math.MaxInt32, r.columnGap)
+			return trackExtent(sizes, 0, len(sizes), r.columnGap)
+		}
+
+		columnSizes := sizeTracks(columns, r.intrinsicItems(cells, AxisHorizontal, false, nil), extent, r.columnGap)
+		items := r.intrinsicItems(cells, axis, minimum, columnSizes)
+		sizes := sizeTracks(r.rowTracks(rowCount), items, math.MaxInt32, r.rowGap)
+		return trackExtent(sizes, 0, len(sizes), r.rowGap)
+	})
+}
+
+// intrinsicItems returns the intrinsic extent of every cell along axis.
+// Heights are measured for the width of the columns each cell spans.
+func (r *RenderGrid) intrinsicItems(cells []gridCell, axis Axis, minimum bool, columnSizes []int) []trackItem {
+	measure := intrinsicMeasure(axis, minimum)
+	items := make([]trackItem, len(cells))
+	for i, cell := range cells {
+		p := cell.placement
+		if axis == AxisHorizontal {
+			items[i] = trackItem{start: p.column, span: p.columnSpan, size: measure(cell.child, math.MaxInt32)}
+			continue
+		}
+		width := trackExtent(columnSizes, p.column, p.columnSpan, r.columnGap)
+		items[i] = trackItem{start: p.row, span: p.rowSpan, size: measure(cell.child, width)}
+	}
+	return items
+}
+
+func (r *RenderGrid) MinIntrinsicWidth(height int) int {
+	return r.gridIntrinsic(AxisHorizontal, height, true)
+}
+
+func (r *RenderGrid) MaxIntrinsicWidth(height int) int {
+	return r.gridIntrinsic(AxisHorizontal, height, false)
+}
+
+func (r *RenderGrid) MinIntrinsicHeight(width int) int {
+	return r.gridIntrinsic(AxisVertical, width, true)
+}
+
+func (r *RenderGrid) MaxIntrinsicHeight(width int) int {
+	return r.gridIntrinsic(AxisVertical, width, false)
+}
+
+// gridOccupancy tracks which cells of the grid are taken
+type gridOccupancy struct {
+	columns int
+	rows    int
+	taken   map[geometry.Point]bool
+
+	// cursor is where automatic placement resumes
+	cursor geometry.Point
+}
+
+func newGridOccupancy(columns int) *gridOccupancy {
+	return &gridOccupancy{
+		columns: columns,
+		taken:   make(map[geometry.Point]bool),
+	}
+}
+
+// occupy marks the cells covered by a placement as taken
+func (g *gridOccupancy) occupy(p gridPlacement) {
+	for row := p.row; row < p.row+p.rowSpan; row++ {
+		for column := p.column; column < p.column+p.columnSpan; column++ {
+			g.taken[geometry.Point{X: column, Y: row}] = true
+		}
+	}
+	g.rows = max(g.rows, p.row+p.rowSpan)
+}
+
+// fits reports whether a placement covers only free cells
+func (g *gridOccupancy) fits(p gridPlacement) bool {
+	for row := p.row; row < p.row+p.rowSpan; row++ {
+		for column := p.column; column < p.column+p.columnSpan; column++ {
+			if g.taken[geometry.Point{X: column, Y: row}] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// next finds the first free position at or after the cursor where a
+// placement of the given span fits, moving row by row
+func (g *gridOccupancy) next(p gridPlacement) gridPlacement {
+	p.columnSpan = min(p.columnSpan, g.columns)
+	p.row, p.column = g.cursor.Y, g.cursor.X
+	for {
+		if p.column+p.columnSpan > g.columns {
+			p.row++
+			p.column = 0
+			continue
+		}
+		if g.fits(p) {
+			g.cursor = geometry.Point{X: p.column + p.columnSpan, Y: p.row}
+			return p
+		}
+		p.column++
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// layoutGrid mounts a grid widget and lays it out within maxSize
+func layoutGrid(g *Grid, maxSize geometry.Size) *RenderGrid {
+	elem := NewElement(g)
+	elem.Mount(nil)
+	r := elem.RenderObject().(*RenderGrid)
+	r.Layout(NewConstraints(geometry.Size{}, maxSize))
+	return r
+}
+
+func TestSizeTracks(t *testing.T) {
+	tests := []struct {
+		name      string
+		tracks    []TrackSize
+		items     []trackItem
+		available int
+		gap       int
+		want      []int
+	}{
+		{
+			name:      "fixed and fractional",
+			tracks:    []TrackSize{TrackFixed(3), TrackFr(1), TrackFr(2)},
+			available: 20,
+			gap:       1,
+			want:      []int{3, 5, 10},
+		},
+		{
+			name:      "auto fits its content",
+			tracks:    []TrackSize{TrackAuto(), TrackFr(1)},
+			items:     []trackItem{{start: 0, span: 1, size: 4}},
+			available: 10,
+			want:      []int{4, 6},
+		},
+		{
+			name:      "fractional never shrinks below its content",
+			tracks:    []TrackSize{TrackFixed(8), TrackFr(1)},
+			items:     []trackItem{{start: 1, span: 1, size: 5}},
+			available: 10,
+			want:      []int{8, 5},
+		},
+		{
+			name:      "minmax grows to its maximum",
+			tracks:    []TrackSize{TrackMinMax(TrackFixed(2), TrackFixed(6)), TrackFr(1)},
+			available: 10,
+			want:      []int{6, 4},
+		},
+		{
+			name:      "minmax shares what is left",
+			tracks:    []TrackSize{TrackMinMax(TrackFixed(2), TrackFixed(6)), TrackFixed(5)},
+			available: 8,
+			want:      []int{3, 5},
+		},
+		{
+			name:      "spanning child is shared between auto tracks",
+			tracks:    []TrackSize{TrackAuto(), TrackAuto()},
+			items:     []trackItem{{start: 0, span: 2, size: 7}},
+			available: 20,
+			gap:       1,
+			want:      []int{3, 3},
+		},
+		{
+			name:   "unbounded tracks fit their content",
+			tracks: []TrackSize{TrackAuto(), TrackFr(1)},
+			items: []trackItem{
+				{start: 0, span: 1, size: 3},
+				{start: 1, span: 1, size: 2},
+			},
+			available: math.MaxInt32,
+			want:      []int{3, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sizeTracks(tt.tracks, tt.items, tt.available, tt.gap))
+		})
+	}
+}
+
+func TestGrid_AutoPlacement(t *testing.T) {
+	g := NewGrid(NewText("a"), NewText("b"), NewText("c")).
+		WithColumns(TrackFixed(4), TrackFr(1)).
+		WithGap(1)
+	r := layoutGrid(g, geometry.Size{Width: 12, Height: 10})
+
+	assert.Equal(t, []int{4, 7}, r.ColumnSizes())
+	assert.Equal(t, []int{1, 1}, r.RowSizes())
+	assert.Equal(t, []geometry.Point{{X: 0, Y: 0}, {X: 5, Y: 0}, {X: 0, Y: 2}}, childOffsets(r))
+	assert.Equal(t, geometry.Size{Width: 12, Height: 3}, r.Size())
+}
+
+func TestGrid_Placement(t *testing.T) {
+	t.Run("explicit cells and spans", func(t *testing.T) {
+		g := NewGrid(
+			NewGridItem(NewText("tall")).WithCell(0, 1).WithSpan(2, 1),
+			NewText("a"),
+			NewText("b"),
+			NewGridItem(NewText("wide")).WithSpan(1, 2),
+		).WithColumns(TrackFixed(5), TrackFixed(5))
+		r := layoutGrid(g, geometry.Size{Width: 10, Height: 10})
+
+		assert.Equal(t, geometry.Point{X: 5, Y: 0}, childOffsets(r)[0])
+		assert.Equal(t, 2, r.children[0].Size().Height)
+		// Auto placed children flow around the explicit one
+		assert.Equal(t, geometry.Point{X: 0, Y: 0}, childOffsets(r)[1])
+		assert.Equal(t, geometry.Point{X: 0, Y: 1}, childOffsets(r)[2])
+		assert.Equal(t, geometry.Point{X: 0, Y: 2}, childOffsets(r)[3])
+		assert.Equal(t, 10, r.children[3].Size().Width)
+	})
+
+	t.Run("named areas", func(t *testing.T) {
+		g := NewGrid(
+			NewGridItem(NewText("main")).WithArea("main"),
+			NewGridItem(NewText("header")).WithArea("header"),
+			NewGridItem(NewText("side")).WithArea("side"),
+		).
+			WithColumns(TrackFixed(6), TrackFr(1)).
+			WithRows(TrackAuto(), TrackFixed(2), TrackFixed(2)).
+			WithAreas(
+				"header header",
+				"side   main",
+				"side   .",
+			)
+		r := layoutGrid(g, geometry.Size{Width: 20, Height: 10})
+
+		assert.Equal(t, geometry.Point{X: 6, Y: 1}, childOffsets(r)[0])
+		assert.Equal(t, geometry.Point{X: 0, Y: 0}, childOffsets(r)[1])
+		assert.Equal(t, 20, r.children[1].Size().Width)
+		assert.Equal(t, geometry.Point{X: 0, Y: 1}, childOffsets(r)[2])
+		assert.Equal(t, 4, r.children[2].Size().Height)
+		assert.Equal(t, []int{1, 2, 2}, r.RowSizes())
+	})
+}
+
+func TestGrid_CellAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		child     Widget
+		justify   GridAlignment
+		wantX     int
+		wantWidth int
+	}{
+		{"stretch", NewText("ab"), GridStretch, 0, 6},
+		{"start", NewText("ab"), GridStart, 0, 2},
+		{"center", NewText("ab"), GridCenter, 2, 2},
+		{"end", NewText("ab"), GridEnd, 4, 2},
+		{"item overrides grid", NewGridItem(NewText("ab")).WithJustifySelf(GridEnd), GridStart, 4, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGrid(tt.child).
+				WithColumns(TrackFixed(6)).
+				WithRows(TrackFixed(3)).
+				WithJustifyItems(tt.justify).
+				WithAlignItems(GridCenter)
+			r := layoutGrid(g, geometry.Size{Width: 10, Height: 10})
+
+			assert.Equal(t, geometry.Point{X: tt.wantX, Y: 1}, childOffsets(r)[0])
+			assert.Equal(t, tt.wantWidth, r.children[0].Size().Width)
+		})
+	}
+}
+
+func TestGrid_Reflow(t *testing.T) {
+	g := NewGrid(NewText("a"), NewText("b")).
+		WithColumns(TrackFixed(2), TrackFr(1))
+	r := layoutGrid(g, geometry.Size{Width: 20, Height: 5})
+	assert.Equal(t, []int{2, 18}, r.ColumnSizes())
+
+	// A smaller terminal shrinks the flexible column
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 5}))
+	assert.Equal(t, []int{2, 6}, r.ColumnSizes())
+	assert.Equal(t, 8, r.Size().Width)
+}
+
+func TestGrid_Paint(t *testing.T) {
+	g := NewGrid(NewText("a"), NewText("b"), NewText("c"), NewText("d")).
+		WithColumns(TrackFixed(2), TrackFixed(2)).
+		WithRowGap(1)
+	r := layoutGrid(g, geometry.Size{Width: 10, Height: 10})
+
+	ctx := NewMockRenderContext()
+	r.Paint(ctx)
+
+	assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 0, Y: 0}].Rune)
+	assert.Equal(t, 'b', ctx.cells[geometry.Point{X: 2, Y: 0}].Rune)
+	assert.Equal(t, 'c', ctx.cells[geometry.Point{X: 0, Y: 2}].Rune)
+	assert.Equal(t, 'd', ctx.cells[geometry.Point{X: 2, Y: 2}].Rune)
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"sort"
+)
+
+// breadthKind is the way a track breadth is measured
+type breadthKind int
+
+const (
+	breadthAuto breadthKind = iota
+	breadthFixed
+	breadthFr
+)
+
+// trackBreadth is one bound of a track size
+type trackBreadth struct {
+	kind  breadthKind
+	value int
+}
+
+// TrackSize sizes a row or column of a Grid. A track is never smaller than
+// its minimum breadth and grows towards its maximum breadth when there is
+// space.
+type TrackSize struct {
+	min trackBreadth
+	max trackBreadth
+}
+
+// TrackFixed creates a track that is exactly cells wide
+func TrackFixed(cells int) TrackSize {
+	breadth := trackBreadth{kind: breadthFixed, value: max(0, cells)}
+	return TrackSize{min: breadth, max: breadth}
+}
+
+// TrackFr creates a flexible track that takes a share of the space left
+// over once the other tracks are sized. Its share is factor divided by the
+// total factor of all flexible tracks. It never shrinks below its content.
+func TrackFr(factor int) TrackSize {
+	return TrackSize{
+		min: trackBreadth{kind: breadthAuto},
+		max: trackBreadth{kind: breadthFr, value: max(0, factor)},
+	}
+}
+
+// TrackAuto creates a track sized to the largest child placed in it
+func TrackAuto() TrackSize {
+	return TrackSize{}
+}
+
+// TrackMinMax creates a track at least as large as the minimum of minSize
+// and at most as large as the maximum of maxSize. A flexible minimum is
+// treated as auto.
+func TrackMinMax(minSize, maxSize TrackSize) TrackSize {
+	track := TrackSize{min: minSize.min, max: maxSize.max}
+	if track.min.kind == breadthFr {
+		track.min = trackBreadth{kind: breadthAuto}
+	}
+	return track
+}
+
+// isFlexible reports whether the track takes a share of the free space
+func (t TrackSize) isFlexible() bool {
+	return t.max.kind == breadthFr && t.max.value > 0
+}
+
+// trackItem is a child's size contribution to the tracks it spans
+type trackItem struct {
+	start int
+	span  int
+	size  int
+}
+
+// sizeTracks resolves the size of every track along one axis given the
+// space available, the gap between tracks and the sizes of the children
+// placed in them
+func sizeTracks(tracks []TrackSize, items []trackItem, available, gap int) []int {
+	base := make([]int, len(tracks))
+	limit := make([]int, len(tracks))
+	for i, track := range tracks {
+		if track.min.kind == breadthFixed {
+			base[i] = track.min.value
+		}
+		if track.max.kind == breadthFixed {
+			limit[i] = track.max.value
+		}
+	}
+
+	fitContent(tracks, items, base, limit, gap)
+
+	if available >= math.MaxInt32 {
+		// Without a bound every track takes the space its content asks for
+		for i := range base {
+			base[i] = max(base[i], limit[i])
+		}
+		return base
+	}
+
+	gaps := gap * max(0, len(tracks)-1)
+	growTracks(tracks, base, limit, available-gaps-sumInts(base))
+	flexTracks(tracks, base, available-gaps)
+	return base
+}
+
+// fitContent grows tracks with an auto minimum so their children fit, and
+// raises the limit of tracks with a content-based maximum. Children that
+// span one track are handled first; the space children spanning several
+// tracks still need is shared between the auto tracks they span.
+func fitContent(tracks []TrackSize, items []trackItem, base, limit []int, gap int) {
+	sorted := make([]trackItem, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].span < sorted[j].span
+	})
+
+	for _, item := range sorted {
+		if item.span == 1 {
+			i := item.start
+			if tracks[i].min.kind == breadthAuto {
+				base[i] = max(base[i], item.size)
+			}
+			if tracks[i].max.kind != breadthFixed {
+				limit[i] = max(limit[i], item.size)
+			}
+			continue
+		}
+		spanned := tracks[item.start : item.start+item.span]
+		need := item.size - sumInts(base[item.start:item.start+item.span]) - gap*(item.span-1)
+		shareSpace(spanned, base[item.start:item.start+item.span], need)
+	}
+
+	for i := range limit {
+		limit[i] = max(limit[i], base[i])
+	}
+}
+
+// shareSpace spreads need cells evenly over the tracks with an auto minimum
+func shareSpace(tracks []TrackSize, base []int, need int) {
+	var auto []int
+	for i, track := range tracks {
+		if track.min.kind == breadthAuto {
+			auto = append(auto, i)
+		}
+	}
+	if need <= 0 || len(auto) == 0 {
+		return
+	}
+
+	for n, i := range auto {
+		share := need / len(auto)
+		if n < need%len(auto) {
+			share++
+		}
+		base[i] += share
+	}
+}
+
+// growTracks hands free space to the inflexible tracks that have not reached
+// their limit, a cell at a time so they grow evenly
+func growTracks(tracks []TrackSize, base, limit []int, free int) {
+	for free > 0 {
+		grown := false
+		for i, track := range tracks {
+			if free == 0 {
+				break
+			}
+			if track.isFlexible() || base[i] >= limit[i] {
+				continue
+			}
+			base[i]++
+			free--
+			grown = true
+		}
+		if !grown {
+			return
+		}
+	}
+}
+
+// flexTracks shares the space left after the inflexible tracks between the
+// flexible tracks by factor. Cells that don't divide evenly go to the first
+// flexible tracks.
+func flexTracks(tracks []TrackSize, base []int, available int) {
+	totalFr := 0
+	free := available
+	for i, track := range tracks {
+		if track.isFlexible() {
+			totalFr += track.max.value
+		} else {
+			free -= base[i]
+		}
+	}
+	if totalFr == 0 || free <= 0 {
+		return
+	}
+
+	remainder := free
+	for _, track := range tracks {
+		if track.isFlexible() {
+			remainder -= free * track.max.value / totalFr
+		}
+	}
+	for i, track := range tracks {
+		if !track.isFlexible() {
+			continue
+		}
+		share := free * track.max.value / totalFr
+		if remainder > 0 {
+			share++
+			remainder--
+		}
+		base[i] = max(base[i], share)
+	}
+}
+
+// trackOffsets returns the start of every track given their sizes and the
+// gap between them
+func trackOffsets(sizes []int, gap int) []int {
+	offsets := make([]int, len(sizes))
+	position := 0
+	for i, size := range sizes {
+		offsets[i] = position
+		position += size + gap
+	}
+	return offsets
+}
+
+// trackExtent returns the space taken by span tracks from start, including
+// the gaps between them
+func trackExtent(sizes []int, start, span, gap int) int {
+	return sumInts(sizes[start:start+span]) + gap*max(0, span-1)
+}
+
+func sumInts(values []int) int {
+	total := 0
+	for _, value := range values {
+		total += value
+	}
+	return total
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+// InheritedWidget makes a value available to every widget below it, such as
+// a theme, a locale or an app service. Widgets that look it up with DependOn
+// are rebuilt when it is replaced by a widget whose UpdateShouldNotify
+// returns true.
+type InheritedWidget interface {
+	Widget
+	GetChild() Widget
+	UpdateShouldNotify(oldWidget InheritedWidget) bool
+}
+
+// BaseInheritedWidget provides the child handling of an InheritedWidget.
+// Embedding widgets add their value and usually override UpdateShouldNotify.
+type BaseInheritedWidget struct {
+	BaseWidget
+	child Widget
+}
+
+// GetChild returns the widget below the inherited widget
+func (w *BaseInheritedWidget) GetChild() Widget {
+	return w.child
+}
+
+// WithChild sets the widget below the inherited widget
+func (w *BaseInheritedWidget) WithChild(child Widget) *BaseInheritedWidget {
+	w.child = child
+	return w
+}
+
+// Build returns the child, so inherited widgets take no space of their own
+func (w *BaseInheritedWidget) Build(context BuildContext) Widget {
+	return w.child
+}
+
+// UpdateShouldNotify reports whether dependents need rebuilding. The default
+// always notifies.
+func (w *BaseInheritedWidget) UpdateShouldNotify(oldWidget InheritedWidget) bool {
+	return true
+}
+
+// inheritedElement hosts an InheritedWidget and tracks the elements that
+// depend on it
+type inheritedElement struct {
+	BaseElement
+	dependents map[Element]bool
+}
+
+func newInheritedElement(widget InheritedWidget) *inheritedElement {
+	elem := &inheritedElement{
+		dependents: make(map[Element]bool),
+	}
+	elem.widget = widget
+	elem.self = elem
+	return elem
+}
+
+// Update notifies dependents when the new widget asks for it
+func (e *inheritedElement) Update(newWidget Widget) {
+	oldWidget := e.widget.(InheritedWidget)
+	if !canUpdate(oldWidget, newWidget) {
+		// The element is replaced, taking its dependents with it
+		e.BaseElement.Update(newWidget)
+		return
+	}
+
+	e.BaseElement.Update(newWidget)
+	if newWidget.(InheritedWidget).UpdateShouldNotify(oldWidget) {
+		for dependent := range e.dependents {
+			dependent.MarkNeedsBuild()
+		}
+	}
+}
+
+// Unmount lets go of the dependents, and has them let go of the element
+func (e *inheritedElement) Unmount() {
+	for dependent := range e.dependents {
+		if tracker, ok := dependent.(dependencyTracker); ok {
+			tracker.removeDependency(e)
+		}
+	}
+	e.dependents = make(map[Element]bool)
+	e.BaseElement.Unmount()
+}
+
+// addDependent registers an element to rebuild when the widget changes
+func (e *inheritedElement) addDependent(dependent Element) {
+	e.dependents[dependent] = true
+}
+
+func (e *inheritedElement) removeDependent(dependent Element) {
+	delete(e.dependents, dependent)
+}
+
+// dependencyTracker is implemented by elements that remember which
+// inherited elements they depend on
+type dependencyTracker interface {
+	addDependency(*inheritedElement)
+	removeDependency(*inheritedElement)
+	dropDependencies()
+	renewDependencies() bool
+}
+
+func (e *BaseElement) addDependency(ancestor *inheritedElement) {
+	for _, existing := range e.dependencies {
+		if existing == ancestor {
+			return
+		}
+	}
+	e.dependencies = append(e.dependencies, ancestor)
+	ancestor.addDependent(e.element())
+}
+
+// removeDependency forgets an ancestor that is going away
+func (e *BaseElement) removeDependency(ancestor *inheritedElement) {
+	for i, existing := range e.dependencies {
+		if existing == ancestor {
+			e.dependencies = append(e.dependencies[:i], e.dependencies[i+1:]...)
+			return
+		}
+	}
+}
+
+// clearDependencies unregisters the element from everything it depends on
+// and reports whether there was anything
+func (e *BaseElement) clearDependencies() bool {
+	for _, ancestor := range e.dependencies {
+		ancestor.removeDependent(e.element())
+	}
+	had := len(e.dependencies) > 0
+	e.dependencies = nil
+	return had
+}
+
+// dropDependencies clears the dependencies of an element leaving the tree,
+// remembering that it had some
+func (e *BaseElement) dropDependencies() {
+	if e.clearDependencies() {
+		e.lostDependencies = true
+	}
+}
+
+// renewDependencies clears the dependencies of an element that moved and
+// reports whether it has to look them up again
+func (e *BaseElement) renewDependencies() bool {
+	renew := e.clearDependencies() || e.lostDependencies
+	e.lostDependencies = false
+	return renew
+}
+
+// deactivateDependencies drops the dependencies of element and its
+// descendants as they leave the tree, so inherited elements neither keep
+// them alive nor rebuild them
+func deactivateDependencies(element Element) {
+	if tracker, ok := element.(dependencyTracker); ok {
+		tracker.dropDependencies()
+	}
+	for _, child := range element.Children() {
+		deactivateDependencies(child)
+	}
+}
+
+// reactivateDependencies rebuilds element and those of its descendants
+// that depended on inherited elements, so they find the ones above where
+// they are now
+func reactivateDependencies(element Element) {
+	if tracker, ok := element.(dependencyTracker); ok && tracker.renewDependencies() {
+		element.MarkNeedsBuild()
+	}
+	for _, child := range element.Children() {
+		reactivateDependencies(child)
+	}
+}
+
+// findInherited returns the nearest ancestor element hosting an inherited
+// widget of type T
+func findInherited[T InheritedWidget](context BuildContext) (*inheritedElement, T) {
+	var zero T
+	if context == nil || context.Element() == nil {
+		return nil, zero
+	}
+
+	for ancestor := context.Element().Parent(); ancestor != nil; ancestor = ancestor.Parent() {
+		if element, ok := ancestor.(*inheritedElement); ok {
+			if widget, ok := element.widget.(T); ok {
+				return element, widget
+			}
+		}
+	}
+	return nil, zero
+}
+
+// DependOn returns the nearest ancestor inherited widget of type T and
+// registers the context's element to be rebuilt when that widget changes.
+// It reports false when there is no such ancestor.
+func DependOn[T InheritedWidget](context BuildContext) (T, bool) {
+	element, widget := findInherited[T](context)
+	if element == nil {
+		return widget, false
+	}
+
+	if tracker, ok := context.Element().(dependencyTracker); ok {
+		tracker.addDependency(element)
+	}
+	return widget, true
+}
+
+// Find returns the nearest ancestor inherited widget of type T without
+// registering a dependency, for reads that should not trigger rebuilds.
+// It reports false when there is no such ancestor.
+func Find[T InheritedWidget](context BuildContext) (T, bool) {
+	element, widget := findInherited[T](context)
+	return widget, element != nil
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTheme is an inherited widget carrying a single value
+type testTheme struct {
+	BaseInheritedWidget
+	value string
+}
+
+func newTestTheme(value string, child Widget) *testTheme {
+	theme := &testTheme{value: value}
+	theme.WithChild(child)
+	return theme
+}
+
+func (t *testTheme) UpdateShouldNotify(oldWidget InheritedWidget) bool {
+	return oldWidget.(*testTheme).value != t.value
+}
+
+// themedWidget records the theme value it sees on every build
+type themedWidget struct {
+	BaseWidget
+	depend bool
+	seen   []string
+	builds int
+}
+
+func (w *themedWidget) CreateState() State {
+	return &themedState{}
+}
+
+type themedState struct {
+	BaseState
+}
+
+func (s *themedState) Build(context BuildContext) Widget {
+	w := s.Widget().(*themedWidget)
+	w.builds++
+
+	var theme *testTheme
+	var ok bool
+	if w.depend {
+		theme, ok = DependOn[*testTheme](context)
+	} else {
+		theme, ok = Find[*testTheme](context)
+	}
+	if ok {
+		w.seen = append(w.seen, theme.value)
+	}
+	return NewText("themed")
+}
+
+// themeHost rebuilds a theme around the same child widgets
+type themeHost struct {
+	BaseWidget
+	child Widget
+	state *themeHostState
+}
+
+func (w *themeHost) CreateState() State {
+	w.state = &themeHostState{value: "light"}
+	return w.state
+}
+
+type themeHostState struct {
+	BaseState
+	value string
+}
+
+func (s *themeHostState) Build(context BuildContext) Widget {
+	return newTestTheme(s.value, s.Widget().(*themeHost).child)
+}
+
+func mountThemed() (*BuildOwner, *themeHost, *themedWidget, *themedWidget) {
+	dependent := &themedWidget{depend: true}
+	reader := &themedWidget{}
+	box := NewBox()
+	box.AppendChild(dependent)
+	box.AppendChild(reader)
+
+	host := &themeHost{child: box}
+	owner := NewBuildOwner()
+	owner.MountRoot(NewElement(host))
+	return owner, host, dependent, reader
+}
+
+func TestInheritedWidget_DependOn(t *testing.T) {
+	owner, host, dependent, reader := mountThemed()
+	assert.Equal(t, []string{"light"}, dependent.seen)
+	assert.Equal(t, []string{"light"}, reader.seen)
+
+	host.state.SetState(func() { host.state.value = "dark" })
+	owner.BuildScope()
+
+	// Only the dependent is rebuilt
+	assert.Equal(t, []string{"light", "dark"}, dependent.seen)
+	assert.Equal(t, 1, reader.builds)
+}
+
+func TestInheritedWidget_UpdateShouldNotify(t *testing.T) {
+	owner, host, dependent, _ := mountThemed()
+
+	// Same value, so dependents are left alone
+	host.state.SetState(nil)
+	owner.BuildScope()
+	assert.Equal(t, 1, dependent.builds)
+}
+
+func TestInheritedWidget_MissingAncestor(t *testing.T) {
+	w := &themedWidget{depend: true}
+	owner := NewBuildOwner()
+	owner.MountRoot(NewElement(w))
+
+	assert.Empty(t, w.seen)
+
+	theme, ok := Find[*testTheme](nil)
+	assert.False(t, ok)
+	assert.Nil(t, theme)
+}
+
+func TestInheritedWidget_UnmountRemovesDependent(t *testing.T) {
+	dependent := &themedWidget{depend: true}
+	owner := NewBuildOwner()
+	root := NewElement(newTestTheme("light", dependent))
+	owner.MountRoot(root)
+
+	inherited := root.(*inheritedElement)
+	assert.Len(t, inherited.dependents, 1)
+
+	root.Children()[0].Unmount()
+	assert.Empty(t, inherited.dependents)
+}
+
+// valueTheme is an inherited widget passed by value rather than by pointer
+type valueTheme struct {
+	*BaseInheritedWidget
+	value string
+}
+
+func (t valueTheme) UpdateShouldNotify(oldWidget InheritedWidget) bool {
+	return oldWidget.(valueTheme).value != t.value
+}
+
+// valueThemed records the valueTheme it depends on on every build
+type valueThemed struct {
+	BaseWidget
+	seen []string
+}
+
+func (w *valueThemed) CreateState() State {
+	return &valueThemedState{}
+}
+
+type valueThemedState struct {
+	BaseState
+}
+
+func (s *valueThemedState) Build(context BuildContext) Widget {
+	w := s.Widget().(*valueThemed)
+	if theme, ok := DependOn[valueTheme](context); ok {
+		w.seen = append(w.seen, theme.value)
+	}
+	return NewText("themed")
+}
+
+// valueThemeHost rebuilds a valueTheme around the same child
+type valueThemeHost struct {
+	BaseWidget
+	child Widget
+	state *valueThemeHostState
+}
+
+func (w *valueThemeHost) CreateState() State {
+	w.state = &valueThemeHostState{value: "light"}
+	return w.state
+}
+
+type valueThemeHostState struct {
+	BaseState
+	value string
+}
+
+func (s *valueThemeHostState) Build(context BuildContext) Widget {
+	return valueTheme{
+		BaseInheritedWidget: (&BaseInheritedWidget{}).WithChild(s.Widget().(*valueThemeHost).child),
+		value:               s.value,
+	}
+}
+
+func TestInheritedWidget_NotifiesForValueWidgets(t *testing.T) {
+	dependent := &valueThemed{}
+	host := &valueThemeHost{child: dependent}
+	owner := NewBuildOwner()
+	owner.MountRoot(NewElement(host))
+
+	host.state.SetState(nil)
+	owner.BuildScope()
+	assert.Equal(t, []string{"light"}, dependent.seen, "an equal value doesn't notify")
+
+	host.state.SetState(func() { host.state.value = "dark" })
+	owner.BuildScope()
+	assert.Equal(t, []string{"light", "dark"}, dependent.seen)
+}
+
+// themedSplit shows a keyed panel under either a left or a right theme
+type themedSplit struct {
+	BaseWidget
+	panel Widget
+	state *themedSplitState
+}
+
+func (w *themedSplit) CreateState() State {
+	w.state = &themedSplitState{left: true}
+	return w.state
+}
+
+type themedSplitState struct {
+	BaseState
+	left bool
+}
+
+func (s *themedSplitState) Build(context BuildContext) Widget {
+	left, right := NewBox(), NewBox()
+	if s.left {
+		left.AppendChild(s.Widget().(*themedSplit).panel)
+	} else {
+		right.AppendChild(s.Widget().(*themedSplit).panel)
+	}
+
+	split := NewBox()
+	split.AppendChild(newTestTheme("left", left))
+	split.AppendChild(newTestTheme("right", right))
+	return split
+}
+
+func TestInheritedWidget_MovedDependent(t *testing.T) {
+	dependent := &themedWidget{depend: true}
+	panel := NewBox()
+	panel.AppendChild(dependent)
+	panel.WithKey(NewGlobalKey("panel"))
+	split := &themedSplit{panel: panel}
+
+	owner := NewBuildOwner()
+	root := NewElement(split)
+	owner.MountRoot(root)
+	theme := func(index int) *inheritedElement {
+		return root.Children()[0].Children()[index].(*inheritedElement)
+	}
+	assert.Equal(t, []string{"left"}, dependent.seen)
+
+	split.state.SetState(func() { split.state.left = false })
+	owner.BuildScope()
+
+	assert.Equal(t, []string{"left", "right"}, dependent.seen, "the moved dependent finds its new theme")
+	assert.Empty(t, theme(0).dependents, "the old theme lets go of it")
+	assert.Len(t, theme(1).dependents, 1)
+}
+
+func TestInheritedWidget_DeactivateRemovesDependents(t *testing.T) {
+	dependent := &themedWidget{depend: true}
+	owner := NewBuildOwner()
+	root := NewElement(newTestTheme("light", dependent))
+	owner.MountRoot(root)
+	inherited := root.(*inheritedElement)
+
+	// Removed from the tree but not yet unmounted
+	owner.deactivate(root.Children()[0])
+	assert.Empty(t, inherited.dependents)
+
+	inherited.Update(newTestTheme("dark", dependent))
+	owner.BuildScope()
+	assert.Equal(t, 1, dependent.builds, "an inactive dependent isn't rebuilt")
+}
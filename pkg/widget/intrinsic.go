@@ -0,0 +1,215 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// intrinsicMeasure returns the render object method measuring the minimum
+// or maximum intrinsic extent along axis
+func intrinsicMeasure(axis Axis, minimum bool) func(RenderObject, int) int {
+	switch {
+	case axis == AxisHorizontal && minimum:
+		return RenderObject.MinIntrinsicWidth
+	case axis == AxisHorizontal:
+		return RenderObject.MaxIntrinsicWidth
+	case minimum:
+		return RenderObject.MinIntrinsicHeight
+	default:
+		return RenderObject.MaxIntrinsicHeight
+	}
+}
+
+// maxIntrinsic returns the largest intrinsic extent of children
+func maxIntrinsic(children []RenderObject, extent int, measure func(RenderObject, int) int) int {
+	result := 0
+	for _, child := range children {
+		result = max(result, measure(child, extent))
+	}
+	return result
+}
+
+// deflateExtent removes inset from an extent, leaving unbounded extents alone
+func deflateExtent(extent, inset int) int {
+	if extent >= math.MaxInt32 {
+		return extent
+	}
+	return max(0, extent-inset)
+}
+
+// The default intrinsic size of a render object is the largest intrinsic
+// size of its children, which it paints on top of each other
+
+func (r *BaseRenderObject) MinIntrinsicWidth(height int) int {
+	return maxIntrinsic(r.children, height, RenderObject.MinIntrinsicWidth)
+}
+
+func (r *BaseRenderObject) MaxIntrinsicWidth(height int) int {
+	return maxIntrinsic(r.children, height, RenderObject.MaxIntrinsicWidth)
+}
+
+func (r *BaseRenderObject) MinIntrinsicHeight(width int) int {
+	return maxIntrinsic(r.children, width, RenderObject.MinIntrinsicHeight)
+}
+
+func (r *BaseRenderObject) MaxIntrinsicHeight(width int) int {
+	return maxIntrinsic(r.children, width, RenderObject.MaxIntrinsicHeight)
+}
+
+// intrinsicWith adds the box's padding and border to an intrinsic extent of
+// its content along axis. content is given the extent across the axis that
+// is left inside the box.
+func (r *BaseRenderBox) intrinsicWith(axis Axis, extent int, content func(extent int) int) int {
+	along, across := r.insets()
+	if axis == AxisVertical {
+		along, across = across, along
+	}
+	return content(deflateExtent(extent, across)) + along
+}
+
+// boxIntrinsic measures the box like layout does, as its largest child
+func (r *BaseRenderBox) boxIntrinsic(axis Axis, extent int, minimum bool) int {
+	return r.intrinsicWith(axis, extent, func(extent int) int {
+		return maxIntrinsic(r.children, extent, intrinsicMeasure(axis, minimum))
+	})
+}
+
+func (r *BaseRenderBox) MinIntrinsicWidth(height int) int {
+	return r.boxIntrinsic(AxisHorizontal, height, true)
+}
+
+func (r *BaseRenderBox) MaxIntrinsicWidth(height int) int {
+	return r.boxIntrinsic(AxisHorizontal, height, false)
+}
+
+func (r *BaseRenderBox) MinIntrinsicHeight(width int) int {
+	return r.boxIntrinsic(AxisVertical, width, true)
+}
+
+func (r *BaseRenderBox) MaxIntrinsicHeight(width int) int {
+	return r.boxIntrinsic(AxisVertical, width, false)
+}
+
+// IntrinsicWidth sizes its child to the child's maximum intrinsic width.
+// Wrapping a column with stretched children makes every child as wide as
+// the widest one.
+type IntrinsicWidth struct {
+	BaseWidget
+	child Widget
+}
+
+// NewIntrinsicWidth creates an IntrinsicWidth around child
+func NewIntrinsicWidth(child Widget) *IntrinsicWidth {
+	return &IntrinsicWidth{child: child}
+}
+
+// GetChildren returns the wrapped child, if any
+func (w *IntrinsicWidth) GetChildren() []Widget {
+	if w.child == nil {
+		return nil
+	}
+	return []Widget{w.child}
+}
+
+func (w *IntrinsicWidth) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *IntrinsicWidth) CreateRenderObject() RenderObject {
+	return &renderIntrinsic{
+		BaseRenderObject: BaseRenderObject{style: w.style},
+		axis:             AxisHorizontal,
+	}
+}
+
+func (w *IntrinsicWidth) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderIntrinsic); ok {
+		r.style = w.style
+	}
+}
+
+// IntrinsicHeight sizes its child to the child's maximum intrinsic height.
+// Wrapping a row with stretched children makes every child as tall as the
+// tallest one.
+type IntrinsicHeight struct {
+	BaseWidget
+	child Widget
+}
+
+// NewIntrinsicHeight creates an IntrinsicHeight around child
+func NewIntrinsicHeight(child Widget) *IntrinsicHeight {
+	return &IntrinsicHeight{child: child}
+}
+
+// GetChildren returns the wrapped child, if any
+func (w *IntrinsicHeight) GetChildren() []Widget {
+	if w.child == nil {
+		return nil
+	}
+	return []Widget{w.child}
+}
+
+func (w *IntrinsicHeight) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *IntrinsicHeight) CreateRenderObject() RenderObject {
+	return &renderIntrinsic{
+		BaseRenderObject: BaseRenderObject{style: w.style},
+		axis:             AxisVertical,
+	}
+}
+
+func (w *IntrinsicHeight) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderIntrinsic); ok {
+		r.style = w.style
+	}
+}
+
+// renderIntrinsic tightens its child's constraints along axis to the
+// child's maximum intrinsic extent
+type renderIntrinsic struct {
+	BaseRenderObject
+	axis Axis
+}
+
+func (r *renderIntrinsic) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	if len(r.children) == 0 {
+		r.size = constraints.Constrain(constraints.MinSize)
+		return r.size
+	}
+
+	child := r.children[0]
+	r.size = constraints.Constrain(LayoutIfNeeded(child, r.childConstraints(child, constraints)))
+	setChildOffset(child, geometry.Point{})
+	return r.size
+}
+
+// childConstraints returns constraints that are tight along the axis at the
+// child's maximum intrinsic extent, within the incoming constraints
+func (r *renderIntrinsic) childConstraints(child RenderObject, constraints Constraints) Constraints {
+	if r.axis == AxisHorizontal && constraints.HasTightWidth() ||
+		r.axis == AxisVertical && constraints.HasTightHeight() {
+		return constraints
+	}
+
+	across := r.axis.cross(constraints.MaxSize)
+	if r.axis == AxisHorizontal && !constraints.HasTightHeight() {
+		across = math.MaxInt32
+	}
+	extent := intrinsicMeasure(r.axis, false)(child, across)
+
+	minSize, maxSize := constraints.MinSize, constraints.MaxSize
+	extent = min(max(extent, r.axis.main(minSize)), r.axis.main(maxSize))
+	return Constraints{
+		MinSize: r.axis.size(extent, r.axis.cross(minSize)),
+		MaxSize: r.axis.size(extent, r.axis.cross(maxSize)),
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// intrinsics holds every intrinsic size of a render object
+type intrinsics struct {
+	minWidth, maxWidth, minHeight, maxHeight int
+}
+
+func measureIntrinsics(r RenderObject, width, height int) intrinsics {
+	return intrinsics{
+		minWidth:  r.MinIntrinsicWidth(height),
+		maxWidth:  r.MaxIntrinsicWidth(height),
+		minHeight: r.MinIntrinsicHeight(width),
+		maxHeight: r.MaxIntrinsicHeight(width),
+	}
+}
+
+// mountRenderObject mounts a widget and returns its render object
+func mountRenderObject(w Widget) RenderObject {
+	elem := NewElement(w)
+	elem.Mount(nil)
+	return elem.RenderObject()
+}
+
+func TestRenderObject_Intrinsics(t *testing.T) {
+	padded := NewWidgetStyle()
+	padded.Padding = NewEdgeInsets(1, 2, 1, 2)
+
+	tests := []struct {
+		name   string
+		widget Widget
+		width  int
+		want   intrinsics
+	}{
+		{
+			name:   "text",
+			widget: NewText("ab\nabcd"),
+			width:  math.MaxInt32,
+			want:   intrinsics{4, 4, 2, 2},
+		},
+		{
+			name:   "row adds widths",
+			widget: NewRow(NewText("ab"), NewText("cde")).WithSpacing(1),
+			width:  math.MaxInt32,
+			want:   intrinsics{6, 6, 1, 1},
+		},
+		{
+			name:   "column adds heights",
+			widget: NewColumn(NewText("ab"), NewText("cde\nf")).WithSpacing(1),
+			width:  math.MaxInt32,
+			want:   intrinsics{3, 3, 4, 4},
+		},
+		{
+			name:   "padding is added",
+			widget: NewColumn(NewText("ab")).WithStyle(padded),
+			width:  math.MaxInt32,
+			want:   intrinsics{6, 6, 3, 3},
+		},
+		{
+			name: "stack ignores positioned children",
+			widget: NewStack(
+				NewText("abc"),
+				NewPositioned(NewText("a much longer label")).WithLeft(0),
+			),
+			width: math.MaxInt32,
+			want:  intrinsics{3, 3, 1, 1},
+		},
+		{
+			name:   "wrap forms runs for the width",
+			widget: NewWrap(NewText("aaa"), NewText("bb"), NewText("cccc")).WithSpacing(1),
+			width:  5,
+			want:   intrinsics{4, 11, 3, 3},
+		},
+		{
+			name: "grid sizes its tracks",
+			widget: NewGrid(NewText("a"), NewText("bcd"), NewText("ef")).
+				WithColumns(TrackFixed(2), TrackAuto()).
+				WithColumnGap(1),
+			width: math.MaxInt32,
+			want:  intrinsics{6, 6, 2, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mountRenderObject(tt.widget)
+			assert.Equal(t, tt.want, measureIntrinsics(r, tt.width, math.MaxInt32))
+		})
+	}
+}
+
+func TestIntrinsicWidth(t *testing.T) {
+	column := NewColumn(NewText("a"), NewText("abcd")).
+		WithCrossAxisAlignment(CrossAxisStretch).
+		WithMainAxisSize(MainAxisSizeMin)
+	r := mountRenderObject(NewIntrinsicWidth(column))
+
+	size := r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 10}))
+
+	// Without IntrinsicWidth the column would stretch its children to 20
+	assert.Equal(t, geometry.Size{Width: 4, Height: 2}, size)
+	for _, child := range r.Children()[0].Children() {
+		assert.Equal(t, 4, child.Size().Width)
+	}
+}
+
+func TestIntrinsicHeight(t *testing.T) {
+	row := NewRow(NewText("a"), NewText("b\nb\nb")).
+		WithCrossAxisAlignment(CrossAxisStretch).
+		WithMainAxisSize(MainAxisSizeMin)
+	r := mountRenderObject(NewIntrinsicHeight(row))
+
+	size := r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 10}))
+
+	assert.Equal(t, geometry.Size{Width: 2, Height: 3}, size)
+	for _, child := range r.Children()[0].Children() {
+		assert.Equal(t, 3, child.Size().Height)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "reflect"
+
+// ValueKey is a key identified by its string value. Siblings with equal
+// value keys are treated as the same child across rebuilds.
+type ValueKey string
+
+func (k ValueKey) String() string {
+	return string(k)
+}
+
+// keyID is a comparable identity for a key. Pointer keys are identified by
+// the pointer itself, other keys by their type and string value.
+type keyID struct {
+	ref   Key
+	typ   reflect.Type
+	value string
+}
+
+func keyIdentity(key Key) keyID {
+	typ := reflect.TypeOf(key)
+	if typ.Kind() == reflect.Pointer {
+		return keyID{ref: key}
+	}
+	return keyID{typ: typ, value: key.String()}
+}
+
+// keysEqual reports whether two keys identify the same widget
+func keysEqual(a, b Key) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return keyIdentity(a) == keyIdentity(b)
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "github.com/watzon/tide/pkg/core/geometry"
+
+// LayoutWidgetBuilder builds a widget for the constraints it will be laid out with
+type LayoutWidgetBuilder func(context BuildContext, constraints Constraints) Widget
+
+// LayoutBuilder builds its child during layout from the constraints its
+// parent passes down, so a widget can change structure rather than just
+// size when space runs out. The builder runs again whenever the
+// constraints change or the LayoutBuilder is rebuilt. It must not mark
+// widgets outside its own subtree as needing to build.
+type LayoutBuilder struct {
+	BaseWidget
+	builder LayoutWidgetBuilder
+}
+
+// NewLayoutBuilder creates a LayoutBuilder with the given builder
+func NewLayoutBuilder(builder LayoutWidgetBuilder) *LayoutBuilder {
+	return &LayoutBuilder{builder: builder}
+}
+
+func (w *LayoutBuilder) CreateRenderObject() RenderObject {
+	return &renderLayoutBuilder{
+		BaseRenderObject: BaseRenderObject{style: w.style},
+	}
+}
+
+func (w *LayoutBuilder) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderLayoutBuilder); ok {
+		r.style = w.style
+	}
+}
+
+// renderLayoutBuilder asks its element to build whenever it is laid out
+// with new constraints, then sizes itself to the built child
+type renderLayoutBuilder struct {
+	BaseRenderObject
+	onLayout        func(Constraints)
+	needsBuild      bool
+	built           bool
+	lastConstraints Constraints
+}
+
+func (r *renderLayoutBuilder) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+
+	if r.onLayout != nil && (r.needsBuild || !r.built || constraints != r.lastConstraints) {
+		r.needsBuild = false
+		r.built = true
+		r.lastConstraints = constraints
+		r.onLayout(constraints)
+	}
+
+	if len(r.children) == 0 {
+		r.size = constraints.Constrain(constraints.MinSize)
+		return r.size
+	}
+	r.size = constraints.Constrain(LayoutIfNeeded(r.children[0], constraints))
+	setChildOffset(r.children[0], geometry.Point{})
+	return r.size
+}
+
+func (w *LayoutBuilder) createElement() Element {
+	return newLayoutBuilderElement(w)
+}
+
+// layoutBuilderElement builds its child from inside layout instead of
+// during the build phase
+type layoutBuilderElement struct {
+	BaseElement
+}
+
+func newLayoutBuilderElement(widget *LayoutBuilder) *layoutBuilderElement {
+	elem := &layoutBuilderElement{}
+	elem.widget = widget
+	elem.self = elem
+	return elem
+}
+
+func (e *layoutBuilderElement) Mount(parent Element) {
+	if e.mounted {
+		return
+	}
+
+	e.attach(parent)
+
+	renderObject := e.widget.CreateRenderObject().(*renderLayoutBuilder)
+	renderObject.onLayout = e.layout
+	e.renderObject = renderObject
+}
+
+// Build defers building to the next layout
+func (e *layoutBuilderElement) Build() {
+	if !e.mounted {
+		return
+	}
+
+	if renderObject, ok := e.renderObject.(*renderLayoutBuilder); ok {
+		renderObject.needsBuild = true
+		renderObject.MarkNeedsLayout()
+	}
+	e.dirty = false
+}
+
+// layout runs the builder for the given constraints and builds the
+// resulting subtree before the render object lays it out
+func (e *layoutBuilderElement) layout(constraints Constraints) {
+	if !e.mounted {
+		return
+	}
+
+	var newWidget Widget
+	if builder := e.widget.(*LayoutBuilder).builder; builder != nil {
+		newWidget = builder(e.BuildContext(), constraints)
+	}
+
+	var old Element
+	if len(e.children) > 0 {
+		old = e.children[0]
+	}
+	if child := e.updateChild(old, newWidget); child != nil {
+		e.children = []Element{child}
+	} else {
+		e.children = nil
+	}
+
+	// Children updated above were only scheduled, so build them now
+	if e.owner != nil {
+		e.owner.buildScopeFor(e)
+	}
+
+	e.syncRenderChildren()
+	e.built = true
+}
+
+// syncRenderChildren hands the child's render object to the render object
+func (e *layoutBuilderElement) syncRenderChildren() {
+	if container, ok := e.renderObject.(ContainerRenderObject); ok {
+		container.SetChildren(e.childRenderObjects())
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+func widthConstraints(width int) Constraints {
+	return NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: 10})
+}
+
+// responsiveHost rebuilds a LayoutBuilder whose builder reads its state
+type responsiveHost struct {
+	BaseWidget
+	builds *int
+	state  *responsiveState
+}
+
+func (w *responsiveHost) CreateState() State {
+	w.state = &responsiveState{label: "sidebar"}
+	return w.state
+}
+
+type responsiveState struct {
+	BaseState
+	label string
+}
+
+func (s *responsiveState) Build(context BuildContext) Widget {
+	builds := s.Widget().(*responsiveHost).builds
+	label := s.label
+	return NewLayoutBuilder(func(context BuildContext, constraints Constraints) Widget {
+		*builds++
+		if constraints.MaxSize.Width < 40 {
+			return NewText("tabs")
+		}
+
+		row := NewBox()
+		row.AppendChild(NewText(label))
+		row.AppendChild(NewText("content"))
+		return row
+	})
+}
+
+func TestLayoutBuilder_BuildsFromConstraints(t *testing.T) {
+	var builds int
+	owner := NewBuildOwner()
+	root := NewElement(&responsiveHost{builds: &builds})
+	owner.MountRoot(root)
+
+	// Nothing is built until the first layout
+	builder := root.Children()[0]
+	assert.Empty(t, builder.Children())
+	assert.Equal(t, 0, builds)
+
+	size := root.RenderObject().Layout(widthConstraints(80))
+	assert.Equal(t, 1, builds)
+	assert.IsType(t, &Box{}, builder.Children()[0].Widget())
+	assert.Len(t, builder.Children()[0].Children(), 2)
+	assert.Equal(t, 7, size.Width)
+
+	// Narrowing the terminal changes the structure
+	size = root.RenderObject().Layout(widthConstraints(30))
+	assert.Equal(t, 2, builds)
+	assert.IsType(t, &Text{}, builder.Children()[0].Widget())
+	assert.Equal(t, geometry.Size{Width: 4, Height: 1}, size)
+	assert.Equal(t, []RenderObject{builder.Children()[0].RenderObject()}, builder.RenderObject().Children())
+}
+
+func TestLayoutBuilder_SkipsUnchangedConstraints(t *testing.T) {
+	var builds int
+	owner := NewBuildOwner()
+	root := NewElement(&responsiveHost{builds: &builds})
+	owner.MountRoot(root)
+
+	root.RenderObject().Layout(widthConstraints(80))
+	root.RenderObject().Layout(widthConstraints(80))
+	assert.Equal(t, 1, builds)
+}
+
+func TestLayoutBuilder_RebuildsOnUpdate(t *testing.T) {
+	var builds int
+	host := &responsiveHost{builds: &builds}
+	owner := NewBuildOwner()
+	root := NewElement(host)
+	owner.MountRoot(root)
+	root.RenderObject().Layout(widthConstraints(80))
+
+	host.state.SetState(func() { host.state.label = "menu" })
+	// The host and its LayoutBuilder rebuild; the builder itself waits for layout
+	assert.Equal(t, 2, owner.BuildScope())
+	assert.Equal(t, 1, builds)
+
+	// The builder runs again on the next layout even though the
+	// constraints did not change, and its subtree is built right away
+	root.RenderObject().Layout(widthConstraints(80))
+	assert.Equal(t, 2, builds)
+
+	row := root.Children()[0].Children()[0]
+	label := row.Children()[0].RenderObject().(*TextRenderObject)
+	assert.Equal(t, "menu", label.content)
+	assert.False(t, owner.HasDirtyElements())
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "sort"
+
+// listExtents locates the items of a list along its scroll axis. Items are
+// either all the same fixed extent, or each measured by extentOf. Variable
+// extents are summed once, on first use, so positions can be looked up
+// without building any items.
+type listExtents struct {
+	count    int
+	fixed    int
+	extentOf func(index int) int
+	// version is the version of extentOf the extents were measured with
+	version int
+	// ends[i] is the offset where item i ends, for variable extents
+	ends []int
+}
+
+func newListExtents(count, fixed int, extentOf func(index int) int, version int) *listExtents {
+	return &listExtents{count: max(0, count), fixed: max(0, fixed), extentOf: extentOf, version: version}
+}
+
+// matches reports whether extents made from count, fixed and an extent
+// function of version would be the same as these, so a rebuilt list can
+// keep what was measured
+func (e *listExtents) matches(count, fixed int, extentOf func(index int) int, version int) bool {
+	return e.count == max(0, count) && e.fixed == max(0, fixed) &&
+		(e.extentOf == nil) == (extentOf == nil) && e.version == version
+}
+
+// measure sums the variable extents if that hasn't happened yet
+func (e *listExtents) measure() {
+	if e.extentOf == nil || e.ends != nil {
+		return
+	}
+	e.ends = make([]int, e.count)
+	end := 0
+	for i := range e.ends {
+		end += max(0, e.extentOf(i))
+		e.ends[i] = end
+	}
+}
+
+// total returns the extent of every item together
+func (e *listExtents) total() int {
+	if e.extentOf == nil {
+		return e.count * e.fixed
+	}
+	e.measure()
+	if e.count == 0 {
+		return 0
+	}
+	return e.ends[e.count-1]
+}
+
+// start returns the offset where the item at index begins
+func (e *listExtents) start(index int) int {
+	if e.extentOf == nil {
+		return index * e.fixed
+	}
+	e.measure()
+	if index <= 0 {
+		return 0
+	}
+	return e.ends[index-1]
+}
+
+// extent returns the length of the item at index
+func (e *listExtents) extent(index int) int {
+	if e.extentOf == nil {
+		return e.fixed
+	}
+	e.measure()
+	return e.ends[index] - e.start(index)
+}
+
+// indexAt returns the item covering offset, clamped to the items there are
+func (e *listExtents) indexAt(offset int) int {
+	if e.count == 0 {
+		return 0
+	}
+	if e.extentOf == nil {
+		if e.fixed == 0 {
+			return 0
+		}
+		return max(0, min(offset/e.fixed, e.count-1))
+	}
+	e.measure()
+	index := sort.Search(e.count, func(i int) bool { return e.ends[i] > offset })
+	return min(index, e.count-1)
+}
@@ -0,0 +1,558 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/style"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// defaultCacheExtent is how many lines beyond each end of the viewport a
+// list builds items for, so short scrolls don't have to build first
+const defaultCacheExtent = 8
+
+// IndexedWidgetBuilder builds the widget for the item at index
+type IndexedWidgetBuilder func(context BuildContext, index int) Widget
+
+// ListView is a vertical, scrollable list that builds its items on demand.
+// Only the items in the viewport, plus a cache extent above and below it,
+// have elements, so a list can hold millions of items. Items are one line
+// tall unless given another fixed extent or an extent builder; positions
+// come from the extents alone, without building the items. Items keep
+// their elements while they stay in range, and keyed items keep them when
+// their index changes.
+//
+// A selectable list moves a highlighted selection with the arrow keys,
+// PgUp/PgDn, Home/End and mouse clicks, scrolling to keep it in view.
+// Otherwise those keys scroll the list like a SingleChildScrollView. Keys
+// only reach the list while it has focus, which a click gives it.
+type ListView struct {
+	BaseWidget
+	itemCount      int
+	builder        IndexedWidgetBuilder
+	itemExtent     int
+	extentBuilder  func(index int) int
+	extentVersion  int
+	cacheExtent    int
+	controller     *ScrollController
+	focusNode      *FocusNode
+	scrollbar      bool
+	selectable     bool
+	onSelect       func(index int)
+	selectionStyle WidgetStyle
+}
+
+// NewListViewBuilder creates a list of itemCount one line items, each
+// built by builder when it scrolls into range
+func NewListViewBuilder(itemCount int, builder IndexedWidgetBuilder) *ListView {
+	return &ListView{
+		itemCount:      itemCount,
+		builder:        builder,
+		itemExtent:     1,
+		cacheExtent:    defaultCacheExtent,
+		scrollbar:      true,
+		selectionStyle: NewWidgetStyle().WithBackground(color.Blue),
+	}
+}
+
+// WithItemExtent makes every item extent lines tall
+func (w *ListView) WithItemExtent(extent int) *ListView {
+	w.itemExtent = extent
+	w.extentBuilder = nil
+	return w
+}
+
+// WithItemExtentBuilder gives each item the number of lines returned by
+// extent. Every extent is asked for once when the list is first laid out,
+// and again only when the item count or version changes, so a rebuilt list
+// must be given a new version whenever the extents it returns change.
+func (w *ListView) WithItemExtentBuilder(extent func(index int) int, version int) *ListView {
+	w.extentBuilder = extent
+	w.extentVersion = version
+	return w
+}
+
+// WithCacheExtent sets how many lines beyond the viewport are built
+func (w *ListView) WithCacheExtent(extent int) *ListView {
+	w.cacheExtent = extent
+	return w
+}
+
+// WithController sets the controller that reads and changes the offset.
+// Without one the list creates its own.
+func (w *ListView) WithController(controller *ScrollController) *ListView {
+	w.controller = controller
+	return w
+}
+
+// WithFocusNode sets the node that gives the list focus. Without one the
+// list creates its own.
+func (w *ListView) WithFocusNode(node *FocusNode) *ListView {
+	w.focusNode = node
+	return w
+}
+
+// WithScrollbar sets whether a scrollbar is drawn when the items overflow
+func (w *ListView) WithScrollbar(scrollbar bool) *ListView {
+	w.scrollbar = scrollbar
+	return w
+}
+
+// WithSelectable sets whether the list has a selected item
+func (w *ListView) WithSelectable(selectable bool) *ListView {
+	w.selectable = selectable
+	return w
+}
+
+// WithOnSelect sets the function called with the index of each newly
+// selected item
+func (w *ListView) WithOnSelect(onSelect func(index int)) *ListView {
+	w.onSelect = onSelect
+	return w
+}
+
+// WithSelectionStyle sets the colors the selected item is painted with
+func (w *ListView) WithSelectionStyle(style WidgetStyle) *ListView {
+	w.selectionStyle = style
+	return w
+}
+
+func (w *ListView) WithStyle(style WidgetStyle) *ListView {
+	w.style = style
+	return w
+}
+
+func (w *ListView) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *ListView) CreateRenderObject() RenderObject {
+	r := &RenderListView{}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *ListView) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderListView); ok {
+		r.style = w.style
+		if r.extents == nil || !r.extents.matches(w.itemCount, w.itemExtent, w.extentBuilder, w.extentVersion) {
+			r.extents = newListExtents(w.itemCount, w.itemExtent, w.extentBuilder, w.extentVersion)
+		}
+		r.cacheExtent = max(0, w.cacheExtent)
+		r.scrollbar = w.scrollbar
+		r.selectable = w.selectable
+		r.onSelect = w.onSelect
+		r.selectionStyle = w.selectionStyle
+		r.selected = max(0, min(r.selected, w.itemCount-1))
+	}
+}
+
+func (w *ListView) createElement() Element {
+	return newListViewElement(w)
+}
+
+// listViewElement builds the items of a list from inside layout, once the
+// render object knows which of them are in range
+type listViewElement struct {
+	BaseElement
+	// indices holds the item index of each child element
+	indices []int
+	// builtWidget is the widget the items were last built from
+	builtWidget Widget
+	binding     scrollBinding
+	focus       focusBinding
+}
+
+func newListViewElement(widget *ListView) *listViewElement {
+	elem := &listViewElement{}
+	elem.widget = widget
+	elem.self = elem
+	return elem
+}
+
+func (e *listViewElement) Mount(parent Element) {
+	if e.mounted {
+		return
+	}
+
+	e.attach(parent)
+
+	renderObject := e.widget.CreateRenderObject().(*RenderListView)
+	renderObject.onLayout = e.layout
+	renderObject.onChange = e.MarkNeedsBuild
+	e.renderObject = renderObject
+	e.Build()
+}
+
+func (e *listViewElement) Unmount() {
+	e.binding.unbind()
+	e.focus.unbind()
+	e.indices = nil
+	e.BaseElement.Unmount()
+}
+
+// Build connects the controller and focus node, marks the list as needing
+// layout and, when the widget changed, has that layout rebuild every item
+// in range. Scrolling also lands here, so only the layout is redone then.
+func (e *listViewElement) Build() {
+	if !e.mounted {
+		return
+	}
+
+	w := e.widget.(*ListView)
+	renderObject := e.renderObject.(*RenderListView)
+	renderObject.controller = e.binding.bind(w.controller, e.MarkNeedsBuild, e.BuildContext().Post)
+	renderObject.focus = e.focus.bind(w.focusNode, e, e.MarkNeedsBuild)
+	if e.widget != e.builtWidget {
+		e.builtWidget = e.widget
+		renderObject.needsBuild = true
+	}
+	renderObject.MarkNeedsLayout()
+	e.dirty = false
+}
+
+// layout builds the items from first to last. Items already built are
+// kept as they are unless rebuild is set, in which case every item is
+// built again, matching keyed items to their old elements by key.
+func (e *listViewElement) layout(first, last int, rebuild bool) {
+	if !e.mounted {
+		return
+	}
+
+	old := make(map[int]Element, len(e.children))
+	keyed := make(map[keyID]Element)
+	for i, child := range e.children {
+		old[e.indices[i]] = child
+		if key := child.Widget().GetKey(); key != nil && rebuild {
+			keyed[keyIdentity(key)] = child
+		}
+	}
+
+	var children []Element
+	var indices []int
+	for index := first; index <= last; index++ {
+		child := e.updateItem(old, keyed, index, rebuild)
+		if child != nil {
+			children = append(children, child)
+			indices = append(indices, index)
+		}
+	}
+
+	// Whatever was not reused has scrolled out of range
+	for _, child := range old {
+		e.deactivateChild(child)
+	}
+	e.children, e.indices = children, indices
+
+	// Items updated above were only scheduled, so build them now
+	if e.owner != nil {
+		e.owner.buildScopeFor(e)
+	}
+
+	e.syncRenderChildren()
+	e.built = true
+}
+
+// updateItem returns the element for the item at index, taking it out of
+// old once it is reused
+func (e *listViewElement) updateItem(old map[int]Element, keyed map[keyID]Element, index int, rebuild bool) Element {
+	if child, ok := old[index]; ok && !rebuild {
+		delete(old, index)
+		return child
+	}
+
+	builder := e.widget.(*ListView).builder
+	if builder == nil {
+		return nil
+	}
+	item := builder(e.BuildContext(), index)
+	if item == nil {
+		return nil
+	}
+
+	var child Element
+	if item.GetKey() != nil {
+		child = takeKeyed(keyed, item)
+	} else if candidate, ok := old[index]; ok && candidate.Widget().GetKey() == nil {
+		child = candidate
+	}
+	for i, candidate := range old {
+		if candidate == child {
+			delete(old, i)
+		}
+	}
+	return e.updateChild(child, item)
+}
+
+// syncRenderChildren hands the render objects of the items, with their
+// indices, to the render object
+func (e *listViewElement) syncRenderChildren() {
+	renderObject, ok := e.renderObject.(*RenderListView)
+	if !ok {
+		return
+	}
+
+	children := make([]RenderObject, 0, len(e.children))
+	indices := make([]int, 0, len(e.children))
+	for i, child := range e.children {
+		if childRenderObject := child.RenderObject(); childRenderObject != nil {
+			children = append(children, childRenderObject)
+			indices = append(indices, e.indices[i])
+		}
+	}
+	renderObject.SetChildren(children)
+	if !slices.Equal(renderObject.indices, indices) {
+		renderObject.indices = indices
+		renderObject.MarkNeedsLayout()
+	}
+}
+
+// RenderListView lays out the built items of a list at their positions
+// relative to the scroll offset
+type RenderListView struct {
+	BaseRenderObject
+	extents        *listExtents
+	cacheExtent    int
+	controller     *ScrollController
+	focus          *FocusNode
+	scrollbar      bool
+	showScrollbar  bool
+	selectable     bool
+	selected       int
+	onSelect       func(index int)
+	selectionStyle WidgetStyle
+
+	// indices holds the item index of each child
+	indices []int
+	// first and last are the range of items last built
+	first, last int
+	needsBuild  bool
+	// onLayout asks the element to build the items from first to last
+	onLayout func(first, last int, rebuild bool)
+	// onChange asks for the list to be laid out and painted again
+	onChange func()
+}
+
+func (r *RenderListView) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	total := r.extents.total()
+	viewport := constraints.Constrain(geometry.Size{Height: total}).Height
+	r.showScrollbar = r.scrollbar && total > viewport && constraints.MaxSize.Width > 1
+
+	if r.controller != nil {
+		r.controller.updateExtents(viewport, total)
+	}
+	r.buildRange(viewport)
+
+	width := r.layoutItems(constraints)
+	r.size = constraints.Constrain(geometry.Size{Width: width, Height: viewport})
+	return r.size
+}
+
+// offset returns how far the list is scrolled
+func (r *RenderListView) offset() int {
+	if r.controller == nil {
+		return 0
+	}
+	return r.controller.Offset()
+}
+
+// buildRange has the element build the items within the cache extent of
+// the viewport if they changed
+func (r *RenderListView) buildRange(viewport int) {
+	first, last := 0, -1
+	if r.extents.count > 0 {
+		start := max(0, r.offset()-r.cacheExtent)
+		end := min(r.extents.total(), r.offset()+viewport+r.cacheExtent)
+		first = r.extents.indexAt(start)
+		last = r.extents.indexAt(max(start, end-1))
+	}
+
+	if r.onLayout != nil && (r.needsBuild || first != r.first || last != r.last) {
+		rebuild := r.needsBuild
+		r.needsBuild = false
+		r.first, r.last = first, last
+		r.onLayout(first, last, rebuild)
+	}
+}
+
+// layoutItems sizes every item to its extent and places it relative to
+// the scroll offset. Items fill a bounded width, less the scrollbar, and
+// otherwise the list is as wide as its widest item. It returns the width
+// of the list.
+func (r *RenderListView) layoutItems(constraints Constraints) int {
+	reserved := 0
+	if r.showScrollbar {
+		reserved = 1
+	}
+	maxWidth := deflateExtent(constraints.MaxSize.Width, reserved)
+	minWidth := maxWidth
+	if maxWidth >= math.MaxInt32 {
+		minWidth = 0
+	}
+
+	width := 0
+	for i, child := range r.children {
+		index := r.indices[i]
+		extent := r.extents.extent(index)
+		size := LayoutIfNeeded(child, NewConstraints(
+			geometry.Size{Width: minWidth, Height: extent},
+			geometry.Size{Width: maxWidth, Height: extent},
+		))
+		width = max(width, size.Width)
+		setChildOffset(child, geometry.Point{Y: r.extents.start(index) - r.offset()})
+	}
+	if maxWidth < math.MaxInt32 {
+		width = maxWidth
+	}
+	return width + reserved
+}
+
+func (r *RenderListView) Paint(context engine.RenderContext) {
+	context.PushClipRect(geometry.NewRect(0, 0, r.size.Width, r.size.Height))
+	for i, child := range r.children {
+		if r.selectable && r.indices[i] == r.selected {
+			r.paintSelected(context, child)
+		} else {
+			paintChild(context, child)
+		}
+	}
+	context.PopClipRect()
+
+	if r.showScrollbar && r.controller != nil {
+		paintScrollbar(context, AxisVertical, r.size, r.controller, r.style)
+	}
+}
+
+// paintSelected paints the selected item in the selection colors, filling
+// the cells the item leaves empty
+func (r *RenderListView) paintSelected(context engine.RenderContext, child RenderObject) {
+	fg, bg := r.selectionStyle.ForegroundColor, r.selectionStyle.BackgroundColor
+	size := child.Size()
+
+	context.PushOffset(childOffset(child))
+	for y := 0; y < size.Height; y++ {
+		for x := 0; x < size.Width; x++ {
+			context.DrawCell(x, y, ' ', fg, bg)
+		}
+	}
+	child.Paint(&highlightContext{RenderContext: context, fg: fg, bg: bg})
+	context.PopOffset()
+}
+
+// HandleEvent moves the selection of a selectable list, and otherwise
+// scrolls it like a scroll view. Keys are only handled while the list has
+// focus, and a click focuses it. The mouse wheel always scrolls.
+func (r *RenderListView) HandleEvent(event terminal.Event) bool {
+	if r.controller == nil || r.extents.count == 0 {
+		return false
+	}
+
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+		if target, ok := r.selectionTarget(ev.Key); ok && r.selectable {
+			return r.selectIndex(target)
+		}
+	case terminal.MouseEvent:
+		if ev.Buttons&tcell.ButtonPrimary != 0 && (!r.showScrollbar || ev.Position.X < r.size.Width-1) {
+			if r.focus != nil {
+				r.focus.RequestFocus()
+			}
+			if r.selectable {
+				return r.selectIndex(r.extents.indexAt(r.offset() + ev.Position.Y))
+			}
+		}
+	}
+	return scrollForEvent(r.controller, AxisVertical, event)
+}
+
+// selectionTarget returns the item a key moves the selection to
+func (r *RenderListView) selectionTarget(key tcell.Key) (int, bool) {
+	page := r.controller.ViewportExtent()
+	switch key {
+	case tcell.KeyUp:
+		return r.selected - 1, true
+	case tcell.KeyDown:
+		return r.selected + 1, true
+	case tcell.KeyPgUp:
+		return r.extents.indexAt(r.extents.start(r.selected) - page), true
+	case tcell.KeyPgDn:
+		return r.extents.indexAt(r.extents.start(r.selected) + page), true
+	case tcell.KeyHome:
+		return 0, true
+	case tcell.KeyEnd:
+		return r.extents.count - 1, true
+	}
+	return 0, false
+}
+
+// selectIndex selects the item at index, clamped to the items there are,
+// and scrolls it into view. It reports whether the selection moved.
+func (r *RenderListView) selectIndex(index int) bool {
+	index = max(0, min(index, r.extents.count-1))
+	if index == r.selected {
+		return false
+	}
+
+	r.selected = index
+	r.controller.ShowRange(r.extents.start(index), r.extents.extent(index))
+	if r.onSelect != nil {
+		r.onSelect(index)
+	}
+	if r.onChange != nil {
+		r.onChange()
+	}
+	return true
+}
+
+// Selected returns the index of the selected item
+func (r *RenderListView) Selected() int {
+	return r.selected
+}
+
+// highlightContext paints through another render context in the selection
+// colors. A transparent foreground keeps the item's own text color.
+type highlightContext struct {
+	engine.RenderContext
+	fg, bg color.Color
+}
+
+func (h *highlightContext) foreground(fg color.Color) color.Color {
+	if h.fg.IsTransparent() {
+		return fg
+	}
+	return h.fg
+}
+
+func (h *highlightContext) DrawCell(x, y int, ch rune, fg, bg color.Color) {
+	h.RenderContext.DrawCell(x, y, ch, h.foreground(fg), h.bg)
+}
+
+func (h *highlightContext) DrawStyledCell(x, y int, ch rune, fg, bg color.Color, s style.Style) {
+	h.RenderContext.DrawStyledCell(x, y, ch, h.foreground(fg), h.bg, s)
+}
+
+func (h *highlightContext) DrawText(pos geometry.Point, text string, s style.Style) {
+	s.ForegroundColor = h.foreground(s.ForegroundColor)
+	s.BackgroundColor = h.bg
+	h.RenderContext.DrawText(pos, text, s)
+}
+
+func (h *highlightContext) PaintBorder(rect geometry.Rect, border style.Border) {
+	border.Walk(rect, h.Capabilities().SupportsUnicode, func(x, y int, ch rune) {
+		h.DrawStyledCell(x, y, ch, border.ForegroundColor, border.BackgroundColor, border.Style)
+	})
+}
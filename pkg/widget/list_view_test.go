@@ -0,0 +1,322 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// countingBuilder builds numbered text items and counts how often each
+// index is built
+func countingBuilder(builds map[int]int) IndexedWidgetBuilder {
+	return func(context BuildContext, index int) Widget {
+		builds[index]++
+		return NewText("item" + strconv.Itoa(index))
+	}
+}
+
+// layoutListView mounts a list view, focuses it and lays it out in a
+// viewport of the given size
+func layoutListView(w Widget, width, height int) (*BuildOwner, *RenderListView) {
+	owner := NewBuildOwner()
+	root := NewElement(w)
+	owner.MountRoot(root)
+	r := root.RenderObject().(*RenderListView)
+	r.focus.RequestFocus()
+	owner.BuildScope()
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	return owner, r
+}
+
+// relayout rebuilds what the list scheduled and lays it out again
+func relayout(owner *BuildOwner, r *RenderListView) {
+	owner.BuildScope()
+	r.Layout(r.Constraints())
+}
+
+func TestListView_BuildsOnlyItemsInRange(t *testing.T) {
+	builds := map[int]int{}
+	c := NewScrollController()
+	list := NewListViewBuilder(1_000_000, countingBuilder(builds)).
+		WithCacheExtent(2).
+		WithController(c)
+	owner, r := layoutListView(list, 10, 5)
+
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, r.indices)
+	assert.Len(t, builds, 7)
+	assert.Equal(t, 1_000_000, c.ContentExtent())
+	assert.Equal(t, geometry.Size{Width: 10, Height: 5}, r.Size())
+
+	c.JumpTo(500_000)
+	relayout(owner, r)
+
+	assert.Equal(t, []int{499_998, 499_999, 500_000, 500_001, 500_002, 500_003, 500_004, 500_005, 500_006}, r.indices)
+	assert.Equal(t, geometry.Point{Y: -2}, childOffset(r.Children()[0]))
+	assert.Equal(t, geometry.Point{Y: 0}, childOffset(r.Children()[2]))
+
+	// Items that stay in range are not built again
+	c.ScrollBy(1)
+	relayout(owner, r)
+	assert.Equal(t, 1, builds[500_003])
+	assert.Equal(t, 1, builds[500_007])
+}
+
+func TestListView_ItemExtents(t *testing.T) {
+	t.Run("fixed", func(t *testing.T) {
+		list := NewListViewBuilder(10, countingBuilder(map[int]int{})).WithItemExtent(2).WithCacheExtent(0)
+		_, r := layoutListView(list, 10, 5)
+
+		assert.Equal(t, []int{0, 1, 2}, r.indices)
+		assert.Equal(t, 20, r.controller.ContentExtent())
+		assert.Equal(t, 2, r.Children()[1].Size().Height)
+		assert.Equal(t, geometry.Point{Y: 4}, childOffset(r.Children()[2]))
+	})
+
+	t.Run("variable", func(t *testing.T) {
+		extents := []int{1, 3, 2, 4, 1}
+		list := NewListViewBuilder(len(extents), countingBuilder(map[int]int{})).
+			WithItemExtentBuilder(func(index int) int { return extents[index] }, 0).
+			WithCacheExtent(0)
+		owner, r := layoutListView(list, 10, 4)
+
+		assert.Equal(t, 11, r.controller.ContentExtent())
+		assert.Equal(t, []int{0, 1}, r.indices)
+
+		r.controller.JumpTo(5)
+		relayout(owner, r)
+		assert.Equal(t, []int{2, 3}, r.indices)
+		assert.Equal(t, geometry.Point{Y: -1}, childOffset(r.Children()[0]))
+		assert.Equal(t, 4, r.Children()[1].Size().Height)
+	})
+
+	t.Run("shorter than the viewport", func(t *testing.T) {
+		list := NewListViewBuilder(3, countingBuilder(map[int]int{}))
+		_, r := layoutListView(list, 10, 5)
+
+		assert.Equal(t, geometry.Size{Width: 10, Height: 3}, r.Size())
+		assert.False(t, r.showScrollbar)
+	})
+}
+
+// measuredListHost shows a list whose variable extents come from its
+// state's height, through a closure made anew on every build, counting how
+// often each extent is measured
+type measuredListHost struct {
+	BaseWidget
+	state *measuredListHostState
+}
+
+func (w *measuredListHost) CreateState() State {
+	w.state = &measuredListHostState{count: 5, height: 2, measured: map[int]int{}}
+	return w.state
+}
+
+type measuredListHostState struct {
+	BaseState
+	count    int
+	height   int
+	version  int
+	measured map[int]int
+}
+
+func (s *measuredListHostState) Build(context BuildContext) Widget {
+	return NewListViewBuilder(s.count, countingBuilder(map[int]int{})).
+		WithItemExtentBuilder(func(index int) int {
+			s.measured[index]++
+			return s.height
+		}, s.version)
+}
+
+func TestListView_KeepsMeasuredExtents(t *testing.T) {
+	host := &measuredListHost{}
+	owner, r := layoutListView(host, 10, 4)
+	extents := r.extents
+	assert.Equal(t, 1, host.state.measured[4])
+
+	t.Run("rebuilt with the same count and version", func(t *testing.T) {
+		host.state.SetState(func() {})
+		relayout(owner, r)
+
+		assert.Same(t, extents, r.extents)
+		assert.Equal(t, 1, host.state.measured[4])
+	})
+
+	t.Run("rebuilt with another count", func(t *testing.T) {
+		host.state.SetState(func() { host.state.count = 6 })
+		relayout(owner, r)
+
+		assert.NotSame(t, extents, r.extents)
+		assert.Equal(t, 2, host.state.measured[4])
+		assert.Equal(t, 12, r.controller.ContentExtent())
+	})
+
+	t.Run("rebuilt with another version", func(t *testing.T) {
+		extents := r.extents
+		host.state.SetState(func() {
+			host.state.height = 1
+			host.state.version++
+		})
+		relayout(owner, r)
+
+		assert.NotSame(t, extents, r.extents)
+		assert.Equal(t, 3, host.state.measured[4])
+		assert.Equal(t, 6, r.controller.ContentExtent())
+	})
+}
+
+// listHost shows a list view of keyed rows built from its state's ids
+type listHost struct {
+	BaseWidget
+	state *listHostState
+}
+
+func (w *listHost) CreateState() State {
+	w.state = &listHostState{ids: []string{"a", "b", "c"}}
+	return w.state
+}
+
+type listHostState struct {
+	BaseState
+	ids []string
+}
+
+func (s *listHostState) Build(context BuildContext) Widget {
+	ids := s.ids
+	return NewListViewBuilder(len(ids), func(context BuildContext, index int) Widget {
+		return newRow(ids[index], true)
+	})
+}
+
+func TestListView_RecyclesKeyedItems(t *testing.T) {
+	host := &listHost{}
+	owner, r := layoutListView(host, 10, 5)
+	items := r.Children()
+
+	host.state.SetState(func() { host.state.ids = []string{"new", "a", "b", "c"} })
+	relayout(owner, r)
+
+	assert.Len(t, r.Children(), 4)
+	assert.Same(t, items[0], r.Children()[1], "a keeps its element at its new index")
+	assert.Equal(t, geometry.Point{Y: 1}, childOffset(r.Children()[1]))
+}
+
+func TestListView_Selection(t *testing.T) {
+	key := func(k tcell.Key) terminal.Event { return terminal.KeyEvent{Key: k} }
+	click := func(y int) terminal.Event {
+		return terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 1, Y: y}}
+	}
+
+	tests := []struct {
+		name     string
+		events   []terminal.Event
+		selected int
+		offset   int
+	}{
+		{"down", []terminal.Event{key(tcell.KeyDown), key(tcell.KeyDown)}, 2, 0},
+		{"up at the top", []terminal.Event{key(tcell.KeyUp)}, 0, 0},
+		{"page down then past the viewport", []terminal.Event{key(tcell.KeyPgDn), key(tcell.KeyDown)}, 6, 2},
+		{"end", []terminal.Event{key(tcell.KeyEnd)}, 99, 95},
+		{"home after end", []terminal.Event{key(tcell.KeyEnd), key(tcell.KeyHome)}, 0, 0},
+		{"click", []terminal.Event{click(3)}, 3, 0},
+		{"wheel scrolls without selecting", []terminal.Event{terminal.MouseEvent{Buttons: tcell.WheelDown}}, 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selections []int
+			list := NewListViewBuilder(100, countingBuilder(map[int]int{})).
+				WithSelectable(true).
+				WithOnSelect(func(index int) { selections = append(selections, index) })
+			_, r := layoutListView(list, 10, 5)
+
+			for _, event := range tt.events {
+				DispatchEvent(r, event)
+			}
+
+			assert.Equal(t, tt.selected, r.Selected())
+			assert.Equal(t, tt.offset, r.controller.Offset())
+			if tt.selected != 0 {
+				assert.Equal(t, tt.selected, selections[len(selections)-1])
+			}
+		})
+	}
+
+	t.Run("keys scroll an unselectable list", func(t *testing.T) {
+		list := NewListViewBuilder(100, countingBuilder(map[int]int{}))
+		_, r := layoutListView(list, 10, 5)
+
+		assert.True(t, DispatchEvent(r, key(tcell.KeyPgDn)))
+		assert.Equal(t, 5, r.controller.Offset())
+	})
+}
+
+func TestListView_KeysNeedFocus(t *testing.T) {
+	for _, selectable := range []bool{true, false} {
+		t.Run("selectable "+strconv.FormatBool(selectable), func(t *testing.T) {
+			list := NewListViewBuilder(100, countingBuilder(map[int]int{})).WithSelectable(selectable)
+			owner, r := layoutListView(list, 10, 5)
+			r.focus.Unfocus()
+
+			for _, k := range []tcell.Key{tcell.KeyDown, tcell.KeyPgDn, tcell.KeyEnd} {
+				assert.False(t, DispatchEvent(r, terminal.KeyEvent{Key: k}))
+			}
+			assert.Equal(t, 0, r.Selected())
+			assert.Equal(t, 0, r.controller.Offset())
+
+			DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 1, Y: 0}})
+			relayout(owner, r)
+			assert.True(t, r.focus.HasFocus(), "a click focuses the list")
+			assert.True(t, DispatchEvent(r, terminal.KeyEvent{Key: tcell.KeyEnd}))
+			assert.Equal(t, 95, r.controller.Offset())
+		})
+	}
+
+	t.Run("wheel without focus", func(t *testing.T) {
+		list := NewListViewBuilder(100, countingBuilder(map[int]int{}))
+		_, r := layoutListView(list, 10, 5)
+		r.focus.Unfocus()
+
+		assert.True(t, DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.WheelDown}))
+		assert.Equal(t, 3, r.controller.Offset())
+	})
+}
+
+func TestListView_ScrollsWhenControllerJumps(t *testing.T) {
+	controller := NewScrollController()
+	list := NewListViewBuilder(100, countingBuilder(map[int]int{})).
+		WithController(controller).
+		WithScrollbar(false)
+	tree := mountControls(NewColumn(NewText("title"), NewExpanded(list)), 10, 4)
+	assert.Equal(t, "item0", tree.lines()[1])
+
+	// Jumping outside an event still lays the nested list out again
+	controller.JumpTo(5)
+	tree.pump()
+	assert.Equal(t, []string{"title", "item5", "item6", "item7"}, tree.lines())
+}
+
+func TestListView_PaintsSelection(t *testing.T) {
+	list := NewListViewBuilder(3, countingBuilder(map[int]int{})).WithSelectable(true)
+	owner, r := layoutListView(list, 8, 3)
+	DispatchEvent(r, terminal.KeyEvent{Key: tcell.KeyDown})
+	relayout(owner, r)
+
+	m := NewMockRenderContext()
+	r.Paint(m)
+
+	assert.Equal(t, 'i', m.cells[geometry.Point{X: 0, Y: 1}].Rune)
+	for x := 0; x < 8; x++ {
+		assert.Equal(t, color.Blue, m.cells[geometry.Point{X: x, Y: 1}].Bg, "cell %d of the selected row", x)
+		assert.NotEqual(t, color.Blue, m.cells[geometry.Point{X: x, Y: 0}].Bg)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// ParentData is information stored on a render object for the use of its
+// parent. Every kind of parent data carries the offset the parent's layout
+// placed the child at; layouts that need more, such as a flex factor or a
+// grid cell, embed BoxParentData in their own type.
+type ParentData interface {
+	// Offset returns the position of the child relative to its parent's
+	// top left corner
+	Offset() geometry.Point
+	SetOffset(offset geometry.Point)
+}
+
+// BoxParentData holds the position of a child within its parent
+type BoxParentData struct {
+	offset geometry.Point
+}
+
+func (d *BoxParentData) Offset() geometry.Point {
+	return d.offset
+}
+
+func (d *BoxParentData) SetOffset(offset geometry.Point) {
+	d.offset = offset
+}
+
+// setChildOffset records where layout placed a child, giving the child box
+// parent data if it has none yet
+func setChildOffset(child RenderObject, offset geometry.Point) {
+	data := child.ParentData()
+	if data == nil {
+		data = &BoxParentData{}
+		child.SetParentData(data)
+	}
+	data.SetOffset(offset)
+}
+
+// childOffset returns the position of a child within its parent, or the
+// parent's origin if the child has not been placed
+func childOffset(child RenderObject) geometry.Point {
+	if data := child.ParentData(); data != nil {
+		return data.Offset()
+	}
+	return geometry.Point{}
+}
+
+// paintChild paints a child at the offset layout placed it at
+func paintChild(context engine.RenderContext, child RenderObject) {
+	context.PushOffset(childOffset(child))
+	child.Paint(context)
+	context.PopOffset()
+	markPainted(child)
+}
+
+// HitTest returns the render objects under position, which is relative to
+// root, from the deepest one up to root itself. Later children are tested
+// first since they paint over earlier ones, and a child can only be hit
+// within the bounds of its parent. It returns nil when root is missed.
+func HitTest(root RenderObject, position geometry.Point) []RenderObject {
+	size := root.Size()
+	if position.X < 0 || position.Y < 0 || position.X >= size.Width || position.Y >= size.Height {
+		return nil
+	}
+
+	children := root.Children()
+	for i := len(children) - 1; i >= 0; i-- {
+		child := children[i]
+		if path := HitTest(child, position.Sub(childOffset(child))); path != nil {
+			return append(path, root)
+		}
+	}
+	return []RenderObject{root}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// childOffsets returns the offset layout gave each child of r
+func childOffsets(r RenderObject) []geometry.Point {
+	offsets := make([]geometry.Point, len(r.Children()))
+	for i, child := range r.Children() {
+		offsets[i] = childOffset(child)
+	}
+	return offsets
+}
+
+func TestParentData_Layouts(t *testing.T) {
+	t.Run("box places children at the content origin", func(t *testing.T) {
+		box := NewBox()
+		box.WithStyle(NewWidgetStyle().WithPadding(NewEdgeInsets(1, 0, 0, 2)))
+		box.AppendChild(NewText("ab"))
+		box.AppendChild(NewText("c"))
+		r := mountRenderObject(box)
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 5}))
+
+		assert.Equal(t, []geometry.Point{{X: 2, Y: 1}, {X: 2, Y: 1}}, childOffsets(r))
+		assert.Equal(t, geometry.Size{Width: 4, Height: 2}, r.Size())
+	})
+
+	t.Run("wrapper widgets carry layout data", func(t *testing.T) {
+		r := mountRenderObject(NewRow(
+			NewExpanded(NewText("a")).WithFlex(2),
+			NewPositioned(NewText("b")).WithLeft(3),
+			NewGridItem(NewText("c")).WithCell(1, 2),
+		))
+		children := r.Children()
+
+		flex, ok := children[0].ParentData().(*FlexParentData)
+		assert.True(t, ok)
+		assert.Equal(t, 2, flex.Flex)
+		assert.Equal(t, FlexFitTight, flex.Fit)
+
+		stack, ok := children[1].ParentData().(*StackParentData)
+		assert.True(t, ok)
+		assert.Equal(t, 3, *stack.Left)
+		assert.Nil(t, stack.Top)
+
+		grid, ok := children[2].ParentData().(*GridParentData)
+		assert.True(t, ok)
+		assert.Equal(t, []int{1, 2}, []int{grid.Row, grid.Column})
+	})
+
+	t.Run("layout data survives layout", func(t *testing.T) {
+		r := mountRenderObject(NewRow(NewText("ab"), NewExpanded(NewText("c"))).WithSpacing(1))
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 1}))
+
+		flex := r.Children()[1].ParentData().(*FlexParentData)
+		assert.Equal(t, 1, flex.Flex)
+		assert.Equal(t, geometry.Point{X: 3, Y: 0}, flex.Offset())
+	})
+
+	t.Run("siblings paint side by side", func(t *testing.T) {
+		box := NewBox()
+		box.AppendChild(NewColumn(NewText("ab"), NewText("cd")))
+		r := mountRenderObject(box)
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 5}))
+
+		ctx := NewMockRenderContext()
+		r.Paint(ctx)
+
+		assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 0, Y: 0}].Rune)
+		assert.Equal(t, 'c', ctx.cells[geometry.Point{X: 0, Y: 1}].Rune)
+	})
+}
+
+func TestHitTest(t *testing.T) {
+	style := NewWidgetStyle().WithPadding(NewEdgeInsets(1, 1, 1, 1))
+	r := mountRenderObject(NewRow(NewText("ab"), NewText("cd")).
+		WithSpacing(1).
+		WithMainAxisSize(MainAxisSizeMin).
+		WithStyle(style))
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 5}))
+	first, second := r.Children()[0], r.Children()[1]
+
+	tests := []struct {
+		name     string
+		position geometry.Point
+		want     []RenderObject
+	}{
+		{"first child", geometry.Point{X: 2, Y: 1}, []RenderObject{first, r}},
+		{"second child", geometry.Point{X: 4, Y: 1}, []RenderObject{second, r}},
+		{"spacing", geometry.Point{X: 3, Y: 1}, []RenderObject{r}},
+		{"padding", geometry.Point{X: 0, Y: 0}, []RenderObject{r}},
+		{"outside", geometry.Point{X: 7, Y: 1}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HitTest(r, tt.position))
+		})
+	}
+
+	t.Run("later children are hit first", func(t *testing.T) {
+		r := mountRenderObject(NewStack(
+			NewText("aaa"),
+			NewPositioned(NewText("b")).WithLeft(1),
+		))
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 5}))
+		text, proxy := r.Children()[1].Children()[0], r.Children()[1]
+
+		assert.Equal(t, []RenderObject{text, proxy, r}, HitTest(r, geometry.Point{X: 1, Y: 0}))
+		assert.Equal(t, []RenderObject{r.Children()[0], r}, HitTest(r, geometry.Point{X: 2, Y: 0}))
+	})
+}
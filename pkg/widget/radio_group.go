@@ -0,0 +1,116 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "sort"
+
+// RadioGroup holds the selected value of a set of radios. Selecting one
+// radio of a group unselects the rest, and the arrow keys move the
+// selection along the group's radios in the order they appear in the
+// tree. Like the rest of the tree, a group must only be used from the
+// build goroutine.
+type RadioGroup struct {
+	value     string
+	listeners []*radioListener
+	// radios are the enabled radios of the group that are mounted
+	radios []*radioMember
+}
+
+// radioListener wraps a listener so it can be found again for removal
+type radioListener struct {
+	fn func()
+}
+
+// radioMember is a mounted radio of a group
+type radioMember struct {
+	value string
+	focus *FocusNode
+}
+
+// NewRadioGroup creates a group with value selected. An empty value
+// selects none of its radios.
+func NewRadioGroup(value string) *RadioGroup {
+	return &RadioGroup{value: value}
+}
+
+// Value returns the selected value
+func (g *RadioGroup) Value() string {
+	return g.value
+}
+
+// Select selects value, telling the listeners if it changed
+func (g *RadioGroup) Select(value string) {
+	if value == g.value {
+		return
+	}
+	g.value = value
+	for _, listener := range append([]*radioListener(nil), g.listeners...) {
+		listener.fn()
+	}
+}
+
+// AddListener registers fn to be called whenever the selected value
+// changes and returns a function that removes it again
+func (g *RadioGroup) AddListener(fn func()) (remove func()) {
+	listener := &radioListener{fn: fn}
+	g.listeners = append(g.listeners, listener)
+	return func() {
+		for i, l := range g.listeners {
+			if l == listener {
+				g.listeners = append(g.listeners[:i:i], g.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// join adds a mounted radio to the group
+func (g *RadioGroup) join(member *radioMember) {
+	for _, m := range g.radios {
+		if m == member {
+			return
+		}
+	}
+	g.radios = append(g.radios, member)
+}
+
+// leave removes a radio from the group
+func (g *RadioGroup) leave(member *radioMember) {
+	for i, m := range g.radios {
+		if m == member {
+			g.radios = append(g.radios[:i:i], g.radios[i+1:]...)
+			return
+		}
+	}
+}
+
+// step selects and focuses the radio after from in tree order, or the one
+// before it when direction is negative, wrapping around
+func (g *RadioGroup) step(from *radioMember, direction int) {
+	radios := g.ordered()
+	for i, m := range radios {
+		if m != from {
+			continue
+		}
+		next := radios[((i+direction)%len(radios)+len(radios))%len(radios)]
+		g.Select(next.value)
+		next.focus.RequestFocus()
+		return
+	}
+}
+
+// ordered returns the group's radios in the order they appear in the tree
+func (g *RadioGroup) ordered() []*radioMember {
+	radios := append([]*radioMember(nil), g.radios...)
+	paths := make(map[*radioMember][]int, len(radios))
+	for _, m := range radios {
+		paths[m] = treePath(m.focus.element)
+	}
+	sort.SliceStable(radios, func(i, j int) bool {
+		return comparePaths(paths[radios[i]], paths[radios[j]]) < 0
+	})
+	return radios
+}
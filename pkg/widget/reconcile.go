@@ -0,0 +1,253 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "reflect"
+
+// MultiChildWidget is implemented by widgets that render themselves and lay
+// out a list of child widgets, such as Box
+type MultiChildWidget interface {
+	Widget
+	GetChildren() []Widget
+}
+
+// canUpdate reports whether an element built for oldWidget can be updated
+// in place to newWidget rather than being replaced. Both widgets must have
+// the same type and the same key.
+func canUpdate(oldWidget, newWidget Widget) bool {
+	if reflect.TypeOf(oldWidget) != reflect.TypeOf(newWidget) {
+		return false
+	}
+	return oldWidget.GetType() == newWidget.GetType() &&
+		keysEqual(oldWidget.GetKey(), newWidget.GetKey())
+}
+
+// sameWidget reports whether two widgets are the same instance, in which
+// case the existing element is already up to date
+func sameWidget(a, b Widget) bool {
+	typ := reflect.TypeOf(a)
+	return typ != nil && typ.Kind() == reflect.Pointer && a == b
+}
+
+// updateChild reconciles a single child element with a new widget. It
+// returns the element now representing the widget, which is child itself
+// when it could be updated in place, or nil when newWidget is nil.
+func (e *BaseElement) updateChild(child Element, newWidget Widget) Element {
+	if newWidget == nil {
+		if child != nil {
+			e.deactivateChild(child)
+		}
+		return nil
+	}
+
+	if child != nil {
+		if sameWidget(child.Widget(), newWidget) {
+			return child
+		}
+		if canUpdate(child.Widget(), newWidget) {
+			child.Update(newWidget)
+			if e.owner == nil {
+				// Without an owner nobody else will rebuild the child
+				child.RebuildIfNeeded()
+			}
+			return child
+		}
+		e.deactivateChild(child)
+	}
+
+	return e.inflateWidget(newWidget)
+}
+
+// inflateWidget creates and mounts an element for a widget. A widget with a
+// global key reclaims the element mounted for that key, keeping its state.
+func (e *BaseElement) inflateWidget(w Widget) Element {
+	if key, ok := w.GetKey().(*GlobalKey); ok {
+		if child := e.retakeGlobalKey(key, w); child != nil {
+			return e.updateChild(child, w)
+		}
+	}
+
+	child := NewElement(w)
+	child.Mount(e.element())
+	return child
+}
+
+// updateSingleChild reconciles the only child of a component element
+func (e *BaseElement) updateSingleChild(newWidget Widget) {
+	var old Element
+	if len(e.children) > 0 {
+		old = e.children[0]
+	}
+
+	child := e.updateChild(old, newWidget)
+	if child == nil {
+		e.children = nil
+	} else {
+		e.children = []Element{child}
+	}
+
+	// On the first build the parent picks up the render object itself
+	if e.built && child != old {
+		e.childRenderObjectChanged()
+	}
+}
+
+// deactivateChild removes a child from the tree. With a build owner the
+// child is unmounted at the end of the build scope, so a global key can
+// still move it elsewhere; otherwise it is unmounted right away.
+func (e *BaseElement) deactivateChild(child Element) {
+	if e.owner != nil {
+		e.owner.deactivate(child)
+		return
+	}
+	child.Unmount()
+}
+
+// updateChildren reconciles a list of child elements with a new list of
+// widgets and returns the new child list. Children are matched in order
+// from the top and the bottom of the list; the remaining middle section
+// is matched by key, so reordered keyed children keep their elements and
+// state. Unkeyed children in the middle are matched by position only when
+// they line up, otherwise they are recreated.
+func (e *BaseElement) updateChildren(oldChildren []Element, newWidgets []Widget) []Element {
+	newChildren := make([]Element, len(newWidgets))
+
+	oldTop, newTop := 0, 0
+	oldBottom, newBottom := len(oldChildren)-1, len(newWidgets)-1
+
+	// Update children that line up at the top
+	for oldTop <= oldBottom && newTop <= newBottom {
+		oldChild := oldChildren[oldTop]
+		if !canUpdate(oldChild.Widget(), newWidgets[newTop]) {
+			break
+		}
+		newChildren[newTop] = e.updateChild(oldChild, newWidgets[newTop])
+		oldTop++
+		newTop++
+	}
+
+	// Find children that line up at the bottom; they are updated last so
+	// children are visited in order
+	for oldTop <= oldBottom && newTop <= newBottom {
+		if !canUpdate(oldChildren[oldBottom].Widget(), newWidgets[newBottom]) {
+			break
+		}
+		oldBottom--
+		newBottom--
+	}
+
+	// Match the middle by key
+	keyed := e.indexKeyedChildren(oldChildren[oldTop : oldBottom+1])
+	for ; newTop <= newBottom; newTop++ {
+		newChildren[newTop] = e.updateChild(takeKeyed(keyed, newWidgets[newTop]), newWidgets[newTop])
+	}
+
+	// Update the bottom
+	oldTop = oldBottom + 1
+	for ; newTop < len(newWidgets); newTop++ {
+		newChildren[newTop] = e.updateChild(oldChildren[oldTop], newWidgets[newTop])
+		oldTop++
+	}
+
+	// Whatever was not reused is gone
+	for _, child := range keyed {
+		e.deactivateChild(child)
+	}
+
+	return newChildren
+}
+
+// indexKeyedChildren maps the keyed elements of children by key and
+// unmounts the unkeyed ones, which cannot be matched reliably
+func (e *BaseElement) indexKeyedChildren(children []Element) map[keyID]Element {
+	if len(children) == 0 {
+		return nil
+	}
+
+	keyed := make(map[keyID]Element, len(children))
+	for _, child := range children {
+		key := child.Widget().GetKey()
+		if key == nil {
+			e.deactivateChild(child)
+			continue
+		}
+
+		id := keyIdentity(key)
+		if duplicate, ok := keyed[id]; ok {
+			e.deactivateChild(duplicate)
+		}
+		keyed[id] = child
+	}
+	return keyed
+}
+
+// takeKeyed removes and returns the element in keyed that can be updated
+// to w, or nil if there is none
+func takeKeyed(keyed map[keyID]Element, w Widget) Element {
+	key := w.GetKey()
+	if key == nil || len(keyed) == 0 {
+		return nil
+	}
+
+	id := keyIdentity(key)
+	child, ok := keyed[id]
+	if !ok || !canUpdate(child.Widget(), w) {
+		return nil
+	}
+	delete(keyed, id)
+	return child
+}
+
+// renderContainer is implemented by elements that can resync the render
+// children of their render object
+type renderContainer interface {
+	isComponent() bool
+	syncRenderChildren()
+}
+
+func (e *BaseElement) isComponent() bool {
+	return e.component
+}
+
+// syncRenderChildren hands the render objects of the element's children to
+// its render object, in child order
+func (e *BaseElement) syncRenderChildren() {
+	if _, ok := e.widget.(MultiChildWidget); !ok {
+		return
+	}
+	if container, ok := e.renderObject.(ContainerRenderObject); ok {
+		container.SetChildren(e.childRenderObjects())
+	}
+}
+
+// childRenderObjects returns the render objects of the element's children
+func (e *BaseElement) childRenderObjects() []RenderObject {
+	children := make([]RenderObject, 0, len(e.children))
+	for _, child := range e.children {
+		if renderObject := child.RenderObject(); renderObject != nil {
+			children = append(children, renderObject)
+		}
+	}
+	return children
+}
+
+// childRenderObjectChanged is called when a child element was replaced, so
+// the render object representing this element may have changed. The
+// nearest element that owns a render object resyncs its render children.
+func (e *BaseElement) childRenderObjectChanged() {
+	var current Element = e.element()
+	for current != nil {
+		container, ok := current.(renderContainer)
+		if !ok {
+			return
+		}
+		if !container.isComponent() {
+			container.syncRenderChildren()
+			return
+		}
+		current = current.Parent()
+	}
+}
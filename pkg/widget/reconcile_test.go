@@ -0,0 +1,216 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// rowWidget is a keyed stateful list row
+type rowWidget struct {
+	BaseWidget
+	id string
+}
+
+func newRow(id string, keyed bool) *rowWidget {
+	row := &rowWidget{id: id}
+	if keyed {
+		row.WithKey(ValueKey(id))
+	}
+	return row
+}
+
+func (w *rowWidget) CreateState() State {
+	return &rowState{}
+}
+
+// rowState remembers the id of the row it was first built for
+type rowState struct {
+	BaseState
+	firstID string
+}
+
+func (s *rowState) Build(context BuildContext) Widget {
+	id := s.Widget().(*rowWidget).id
+	if s.firstID == "" {
+		s.firstID = id
+	}
+	return NewText(id)
+}
+
+// listWidget builds a box of rows from a list of ids
+type listWidget struct {
+	BaseWidget
+	ids   []string
+	keyed bool
+	state *listState
+}
+
+func (w *listWidget) CreateState() State {
+	w.state = &listState{ids: w.ids}
+	return w.state
+}
+
+type listState struct {
+	BaseState
+	ids []string
+}
+
+func (s *listState) Build(context BuildContext) Widget {
+	keyed := s.Widget().(*listWidget).keyed
+	box := NewBox()
+	for _, id := range s.ids {
+		box.AppendChild(newRow(id, keyed))
+	}
+	return box
+}
+
+func mountList(ids []string, keyed bool) (*BuildOwner, *listWidget, Element) {
+	owner := NewBuildOwner()
+	list := &listWidget{ids: ids, keyed: keyed}
+	root := NewElement(list)
+	owner.MountRoot(root)
+	return owner, list, root
+}
+
+// rows returns the row elements under a mounted list
+func rows(root Element) []Element {
+	return root.Children()[0].Children()
+}
+
+func rowIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	return ids
+}
+
+func TestCanUpdate(t *testing.T) {
+	keyed := func(key Key) Widget {
+		w := &MockWidget{}
+		w.WithKey(key)
+		return w
+	}
+
+	tests := []struct {
+		name     string
+		old, new Widget
+		expected bool
+	}{
+		{"same type", &MockWidget{}, &MockWidget{}, true},
+		{"different type", &MockWidget{}, NewText("text"), false},
+		{"same key", keyed(ValueKey("a")), keyed(ValueKey("a")), true},
+		{"different key", keyed(ValueKey("a")), keyed(ValueKey("b")), false},
+		{"key added", &MockWidget{}, keyed(ValueKey("a")), false},
+		{"different key types", keyed(ValueKey("a")), keyed(StringKey("a")), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canUpdate(tt.old, tt.new))
+		})
+	}
+}
+
+func TestUpdateChildren_KeyedReorder(t *testing.T) {
+	ids := rowIDs(10000)
+	owner, list, root := mountList(ids, true)
+
+	before := make(map[string]Element, len(ids))
+	for _, row := range rows(root) {
+		before[row.Widget().(*rowWidget).id] = row
+	}
+
+	reversed := make([]string, len(ids))
+	for i, id := range ids {
+		reversed[len(ids)-1-i] = id
+	}
+	list.state.SetState(func() { list.state.ids = reversed })
+	owner.BuildScope()
+
+	after := rows(root)
+	assert.Len(t, after, len(ids))
+	for i, row := range after {
+		id := reversed[i]
+		if !assert.Same(t, before[id], row, "row %s was recreated", id) {
+			return
+		}
+		assert.Equal(t, id, row.(StatefulElement).State().(*rowState).firstID)
+	}
+}
+
+func TestUpdateChildren_KeyedInsertAndRemove(t *testing.T) {
+	owner, list, root := mountList([]string{"a", "b", "c", "d"}, true)
+	before := rows(root)
+
+	list.state.SetState(func() { list.state.ids = []string{"a", "x", "c", "d"} })
+	owner.BuildScope()
+
+	after := rows(root)
+	assert.Len(t, after, 4)
+	assert.Same(t, before[0], after[0])
+	assert.Same(t, before[2], after[2])
+	assert.Same(t, before[3], after[3])
+	assert.Equal(t, "x", after[1].Widget().(*rowWidget).id)
+
+	// The removed row is unmounted and its state disposed
+	assert.Nil(t, before[1].(StatefulElement).State())
+}
+
+func TestUpdateChildren_Unkeyed(t *testing.T) {
+	owner, list, root := mountList([]string{"a", "b", "c"}, false)
+	before := rows(root)
+
+	// Unkeyed rows are updated in place by position
+	list.state.SetState(func() { list.state.ids = []string{"c", "b", "a", "d"} })
+	owner.BuildScope()
+
+	after := rows(root)
+	assert.Len(t, after, 4)
+	for i := range before {
+		assert.Same(t, before[i], after[i])
+	}
+	assert.Equal(t, "c", after[0].Widget().(*rowWidget).id)
+	assert.Equal(t, "a", after[0].(StatefulElement).State().(*rowState).firstID)
+}
+
+func TestUpdateChildren_TypeChangeReplaces(t *testing.T) {
+	owner := NewBuildOwner()
+	box := NewBox()
+	box.AppendChild(NewText("text"))
+	root := NewElement(box)
+	owner.MountRoot(root)
+	before := root.Children()[0]
+
+	box.children = []Widget{&MockWidget{}}
+	root.(*BaseElement).rebuild()
+
+	after := root.Children()[0]
+	assert.NotSame(t, before, after)
+	assert.IsType(t, &MockWidget{}, after.Widget())
+}
+
+func TestUpdateChildren_SyncsRenderChildren(t *testing.T) {
+	owner, list, root := mountList([]string{"a", "b", "c"}, true)
+	boxElement := root.Children()[0]
+
+	renderChildren := func() []string {
+		var ids []string
+		for _, child := range boxElement.RenderObject().Children() {
+			ids = append(ids, child.(*TextRenderObject).content)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, renderChildren())
+
+	list.state.SetState(func() { list.state.ids = []string{"c", "a"} })
+	owner.BuildScope()
+	assert.Equal(t, []string{"c", "a"}, renderChildren())
+}
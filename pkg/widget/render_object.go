@@ -6,6 +6,8 @@
 package widget
 
 import (
+	"slices"
+
 	"github.com/watzon/tide/pkg/core/geometry"
 	"github.com/watzon/tide/pkg/engine"
 )
@@ -17,10 +19,23 @@ type RenderObject interface {
 	Size() geometry.Size
 	Constraints() Constraints
 
+	// Intrinsic sizes report how big the object would like to be without
+	// laying it out. Widths are given for a height and heights for a
+	// width; math.MaxInt32 means the other dimension is unbounded.
+	MinIntrinsicWidth(height int) int
+	MaxIntrinsicWidth(height int) int
+	MinIntrinsicHeight(width int) int
+	MaxIntrinsicHeight(width int) int
+
 	// Parent/child relationships
 	Parent() RenderObject
 	Children() []RenderObject
 
+	// Parent data is set on the object by its parent, which uses it to
+	// position and paint the object
+	ParentData() ParentData
+	SetParentData(data ParentData)
+
 	// Style and appearance
 	Style() WidgetStyle
 
@@ -28,13 +43,29 @@ type RenderObject interface {
 	Paint(context engine.RenderContext)
 }
 
+// ContainerRenderObject is implemented by render objects that lay out and
+// paint the render objects of their child elements
+type ContainerRenderObject interface {
+	RenderObject
+	SetChildren(children []RenderObject)
+}
+
 // BaseRenderObject provides default implementation for RenderObjects
 type BaseRenderObject struct {
 	size        geometry.Size
 	constraints Constraints
 	style       WidgetStyle
 	parent      RenderObject
+	parentData  ParentData
 	children    []RenderObject
+
+	// container is the render object holding this one as a child. Marking
+	// this object dirty marks it and every container above it.
+	container *BaseRenderObject
+	// laidOut is cleared when the object needs layout and painted when it
+	// needs paint; new objects need both
+	laidOut bool
+	painted bool
 }
 
 // Layout and sizing
@@ -62,17 +93,105 @@ func (r *BaseRenderObject) Children() []RenderObject {
 	return r.children
 }
 
+func (r *BaseRenderObject) ParentData() ParentData {
+	return r.parentData
+}
+
+func (r *BaseRenderObject) SetParentData(data ParentData) {
+	r.parentData = data
+}
+
 // Style access
 func (r *BaseRenderObject) Style() WidgetStyle {
 	return r.style
 }
 
+// MarkNeedsLayout records that the object changed in a way that affects
+// its size or the layout of its children. The object and everything above
+// it are laid out and painted again on the next frame; clean siblings keep
+// their layout.
+func (r *BaseRenderObject) MarkNeedsLayout() {
+	for node := r; node != nil; node = node.container {
+		node.laidOut = false
+		node.painted = false
+	}
+}
+
+// MarkNeedsPaint records that the object looks different but keeps its
+// size, so the next frame paints it without laying it out
+func (r *BaseRenderObject) MarkNeedsPaint() {
+	for node := r; node != nil; node = node.container {
+		node.painted = false
+	}
+}
+
+// renderBase returns the BaseRenderObject, which render objects embedding
+// it share, so dirty tracking works for any of them
+func (r *BaseRenderObject) renderBase() *BaseRenderObject {
+	return r
+}
+
+// renderNode is implemented by render objects embedding BaseRenderObject.
+// Other render objects are laid out and painted on every frame.
+type renderNode interface {
+	renderBase() *BaseRenderObject
+}
+
+// markNeedsLayout marks a render object as needing layout if it tracks it
+func markNeedsLayout(renderObject RenderObject) {
+	if node, ok := renderObject.(renderNode); ok {
+		node.renderBase().MarkNeedsLayout()
+	}
+}
+
+// LayoutIfNeeded lays out a render object, unless it was last laid out with
+// the same constraints and hasn't been marked as needing layout since, in
+// which case it keeps its size. Parents lay out their children through it,
+// so laying out the root only reaches the render objects that changed.
+func LayoutIfNeeded(renderObject RenderObject, constraints Constraints) geometry.Size {
+	node, ok := renderObject.(renderNode)
+	if !ok {
+		return renderObject.Layout(constraints)
+	}
+	base := node.renderBase()
+	if base.laidOut && renderObject.Constraints() == constraints {
+		return renderObject.Size()
+	}
+	size := renderObject.Layout(constraints)
+	base.laidOut = true
+	return size
+}
+
+// NeedsPaint reports whether a render object, or anything below it, changed
+// since it was last painted
+func NeedsPaint(renderObject RenderObject) bool {
+	node, ok := renderObject.(renderNode)
+	return !ok || !node.renderBase().painted
+}
+
+// PaintRoot paints the render tree under root and records it as painted
+func PaintRoot(context engine.RenderContext, root RenderObject) {
+	root.Paint(context)
+	markPainted(root)
+}
+
+// markPainted clears the paint flag of a render object that was just painted
+func markPainted(renderObject RenderObject) {
+	if node, ok := renderObject.(renderNode); ok {
+		node.renderBase().painted = true
+	}
+}
+
 // Child management
 func (r *BaseRenderObject) AppendChild(child RenderObject) {
 	if baseChild, ok := child.(*BaseRenderObject); ok {
 		baseChild.parent = r
 	}
+	if node, ok := child.(renderNode); ok {
+		node.renderBase().container = r
+	}
 	r.children = append(r.children, child)
+	r.MarkNeedsLayout()
 }
 
 func (r *BaseRenderObject) RemoveChild(child RenderObject) {
@@ -81,19 +200,43 @@ func (r *BaseRenderObject) RemoveChild(child RenderObject) {
 			if baseChild, ok := child.(*BaseRenderObject); ok {
 				baseChild.parent = nil
 			}
+			r.releaseChild(child)
 			r.children = append(r.children[:i], r.children[i+1:]...)
+			r.MarkNeedsLayout()
 			return
 		}
 	}
 }
 
+// SetChildren replaces the children with the given render objects. The
+// object only needs layout again if they differ from its current children.
+func (r *BaseRenderObject) SetChildren(children []RenderObject) {
+	if slices.Equal(r.children, children) {
+		return
+	}
+	r.ClearChildren()
+	for _, child := range children {
+		r.AppendChild(child)
+	}
+}
+
 func (r *BaseRenderObject) ClearChildren() {
 	for _, child := range r.children {
 		if baseChild, ok := child.(*BaseRenderObject); ok {
 			baseChild.parent = nil
 		}
+		r.releaseChild(child)
 	}
 	r.children = nil
+	r.MarkNeedsLayout()
+}
+
+// releaseChild detaches a removed child from the object, unless the child
+// has already moved to another container
+func (r *BaseRenderObject) releaseChild(child RenderObject) {
+	if node, ok := child.(renderNode); ok && node.renderBase().container == r {
+		node.renderBase().container = nil
+	}
 }
 
 // Paint provides a default implementation that paints children
@@ -113,10 +256,28 @@ func (r *BaseRenderObject) Paint(context engine.RenderContext) {
 
 	// Paint children
 	for _, child := range r.children {
-		child.Paint(context)
+		paintChild(context, child)
 	}
 }
 
+// renderProxy lays out its only child with its own constraints and takes
+// on the child's size. Wrapper widgets use it to give their child parent
+// data for the enclosing layout.
+type renderProxy struct {
+	BaseRenderObject
+}
+
+func (r *renderProxy) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	if len(r.children) == 0 {
+		r.size = constraints.Constrain(constraints.MinSize)
+		return r.size
+	}
+	r.size = constraints.Constrain(LayoutIfNeeded(r.children[0], constraints))
+	setChildOffset(r.children[0], geometry.Point{})
+	return r.size
+}
+
 // Helper functions
 
 // paintBackground fills a rectangle with the background color
@@ -159,105 +320,111 @@ type BaseRenderBox struct {
 
 // Layout implements the box model layout algorithm
 func (r *BaseRenderBox) Layout(constraints Constraints) geometry.Size {
-	// 1. Calculate available content space by subtracting padding and border
-	horizontalInsets := r.style.Padding.Left + r.style.Padding.Right +
-		r.style.BorderWidth.Left + r.style.BorderWidth.Right
-	verticalInsets := r.style.Padding.Top + r.style.Padding.Bottom +
-		r.style.BorderWidth.Top + r.style.BorderWidth.Bottom
-
-	// 2. Create content constraints
-	contentConstraints := Constraints{
+	r.constraints = constraints
+
+	// Layout children within content constraints
+	contentSize := r.layoutChildren(r.contentConstraints(constraints))
+
+	// Ensure the final size, including margin, border and padding,
+	// satisfies the original constraints
+	r.size = constraints.Constrain(r.outerSize(contentSize))
+	return r.size
+}
+
+// boxInsets returns the space between the edge of the box and its content,
+// taken up by margin, border and padding
+func (r *BaseRenderBox) boxInsets() EdgeInsets {
+	return r.style.Margin.Add(r.style.BorderWidth).Add(r.style.Padding)
+}
+
+// insets returns the horizontal and vertical space taken up by margin,
+// border and padding
+func (r *BaseRenderBox) insets() (horizontal, vertical int) {
+	insets := r.boxInsets()
+	return insets.Horizontal(), insets.Vertical()
+}
+
+// contentConstraints subtracts margin, border and padding from the box's
+// constraints to give the space available to its content
+func (r *BaseRenderBox) contentConstraints(constraints Constraints) Constraints {
+	horizontal, vertical := r.insets()
+	return Constraints{
 		MinSize: geometry.Size{
-			Width:  max(0, constraints.MinSize.Width-horizontalInsets),
-			Height: max(0, constraints.MinSize.Height-verticalInsets),
+			Width:  max(0, constraints.MinSize.Width-horizontal),
+			Height: max(0, constraints.MinSize.Height-vertical),
 		},
 		MaxSize: geometry.Size{
-			Width:  max(0, constraints.MaxSize.Width-horizontalInsets),
-			Height: max(0, constraints.MaxSize.Height-verticalInsets),
+			Width:  max(0, constraints.MaxSize.Width-horizontal),
+			Height: max(0, constraints.MaxSize.Height-vertical),
 		},
 	}
+}
 
-	// 3. Layout children within content constraints
-	contentSize := r.layoutChildren(contentConstraints)
-
-	// 4. Calculate final size including padding and border
-	r.size = geometry.Size{
-		Width:  contentSize.Width + horizontalInsets,
-		Height: contentSize.Height + verticalInsets,
+// outerSize adds margin, border and padding to a content size
+func (r *BaseRenderBox) outerSize(content geometry.Size) geometry.Size {
+	horizontal, vertical := r.insets()
+	return geometry.Size{
+		Width:  content.Width + horizontal,
+		Height: content.Height + vertical,
 	}
-
-	// 5. Ensure size satisfies original constraints
-	r.size = constraints.Constrain(r.size)
-	return r.size
 }
 
+// layoutChildren lays out the children on top of each other at the start
+// of the content rect and returns the size of the largest
 func (r *BaseRenderBox) layoutChildren(constraints Constraints) geometry.Size {
-	if len(r.children) == 0 {
-		return constraints.MinSize
+	size := constraints.MinSize
+	origin := r.ContentRect().Min
+	for _, child := range r.children {
+		childSize := LayoutIfNeeded(child, constraints)
+		setChildOffset(child, origin)
+		size.Width = max(size.Width, childSize.Width)
+		size.Height = max(size.Height, childSize.Height)
 	}
-
-	// For now, just layout the first child with full constraints
-	// This will be expanded when we add different layout behaviors (row, column, etc.)
-	child := r.children[0]
-	return child.Layout(constraints)
+	return size
 }
 
+// PaintBackground fills the border rect, leaving the margin transparent
 func (r *BaseRenderBox) PaintBackground(context engine.RenderContext) {
 	if r.style.BackgroundColor.A > 0 {
-		paintBackground(context, r.style, r.PaddingRect())
+		paintBackground(context, r.style, r.BorderRect())
 	}
 }
 
+// PaintBorder draws the sides of the border that have a width around the
+// border rect
 func (r *BaseRenderBox) PaintBorder(context engine.RenderContext) {
-	if r.style.BorderWidth.IsZero() {
+	if r.style.BorderWidth.IsZero() || r.style.BorderStyle == BorderNone {
 		return
 	}
 	// Let the backend handle the border painting
-	context.PaintBorder(r.BorderRect(), r.style.Style)
+	context.PaintBorder(r.BorderRect(), r.style.Border())
 }
 
-func (r *BaseRenderBox) ContentRect() geometry.Rect {
-	insets := r.style.Padding
-	return geometry.Rect{
-		Min: geometry.Point{X: insets.Left, Y: insets.Top},
-		Max: geometry.Point{
-			X: r.size.Width - insets.Right,
-			Y: r.size.Height - insets.Bottom,
-		},
-	}
-}
+// The box is laid out like a CSS box: its size covers the margin, inside
+// which the border, padding and content each take up space in turn. All
+// rects are relative to the box's top left corner.
 
-func (r *BaseRenderBox) PaddingRect() geometry.Rect {
+// MarginRect returns the whole area of the box, including its margin
+func (r *BaseRenderBox) MarginRect() geometry.Rect {
 	return geometry.Rect{
-		Min: geometry.Point{X: 0, Y: 0},
 		Max: geometry.Point{X: r.size.Width, Y: r.size.Height},
 	}
 }
 
+// BorderRect returns the area inside the margin, where the border is drawn
 func (r *BaseRenderBox) BorderRect() geometry.Rect {
-	insets := r.style.Margin
-	return geometry.Rect{
-		Min: geometry.Point{X: -insets.Left, Y: -insets.Top},
-		Max: geometry.Point{
-			X: r.size.Width + insets.Right,
-			Y: r.size.Height + insets.Bottom,
-		},
-	}
+	return r.style.Margin.Deflate(r.MarginRect())
 }
 
-func (r *BaseRenderBox) MarginRect() geometry.Rect {
-	border := r.BorderRect()
-	insets := r.style.Margin
-	return geometry.Rect{
-		Min: geometry.Point{
-			X: border.Min.X - insets.Left,
-			Y: border.Min.Y - insets.Top,
-		},
-		Max: geometry.Point{
-			X: border.Max.X + insets.Right,
-			Y: border.Max.Y + insets.Bottom,
-		},
-	}
+// PaddingRect returns the area inside the border
+func (r *BaseRenderBox) PaddingRect() geometry.Rect {
+	return r.style.BorderWidth.Deflate(r.BorderRect())
+}
+
+// ContentRect returns the area inside the padding, where children are laid
+// out
+func (r *BaseRenderBox) ContentRect() geometry.Rect {
+	return r.style.Padding.Deflate(r.PaddingRect())
 }
 
 // NewBaseRenderBox creates a new BaseRenderBox with default style
@@ -6,11 +6,14 @@
 package widget
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/capabilities"
 	"github.com/watzon/tide/pkg/core/color"
 	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
 	"github.com/watzon/tide/pkg/core/style"
 	"github.com/watzon/tide/pkg/engine"
 )
@@ -18,35 +21,80 @@ import (
 // Cell represents a single character cell for testing purposes
 type Cell struct {
 	Rune rune
-	Fg   color.Color
-	Bg   color.Color
+	// Cluster is the whole character drawn by DrawText, which may be
+	// several runes
+	Cluster string
+	Fg      color.Color
+	Bg      color.Color
 }
 
 // MockRenderContext implements engine.RenderContext for testing
 type MockRenderContext struct {
 	engine.RenderContext
-	cells  map[geometry.Point]Cell
-	offset geometry.Point // Add offset tracking
+	cells   map[geometry.Point]Cell
+	offset  geometry.Point // Add offset tracking
+	offsets []geometry.Point
+	clips   []geometry.Rect
+	// cursor is where the cursor was last set, hidden at -1, -1
+	cursor geometry.Point
 }
 
 func NewMockRenderContext() *MockRenderContext {
 	return &MockRenderContext{
-		cells: make(map[geometry.Point]Cell),
+		cells:  make(map[geometry.Point]Cell),
+		cursor: geometry.Point{X: -1, Y: -1},
 	}
 }
 
 func (m *MockRenderContext) DrawCell(x, y int, ch rune, fg, bg color.Color) {
-	m.cells[geometry.Point{
+	m.setCell(x, y, Cell{Rune: ch, Fg: fg, Bg: bg})
+}
+
+// setCell stores a cell at x, y unless it is clipped
+func (m *MockRenderContext) setCell(x, y int, cell Cell) {
+	p := geometry.Point{
 		X: x + m.offset.X,
 		Y: y + m.offset.Y,
-	}] = Cell{
-		Rune: ch,
-		Fg:   fg,
-		Bg:   bg,
 	}
+	for _, clip := range m.clips {
+		if !clip.Contains(p) {
+			return
+		}
+	}
+	m.cells[p] = cell
+}
+
+func (m *MockRenderContext) DrawStyledCell(x, y int, ch rune, fg, bg color.Color, s style.Style) {
+	m.DrawCell(x, y, ch, fg, bg)
+}
+
+// DrawText draws text a grapheme cluster at a time, leaving the second
+// cell of a wide character empty
+func (m *MockRenderContext) DrawText(pos geometry.Point, text string, s style.Style) {
+	x := pos.X
+	for _, cluster := range grapheme.Clusters(text) {
+		m.setCell(x, pos.Y, Cell{
+			Rune:    []rune(cluster.Text)[0],
+			Cluster: cluster.Text,
+			Fg:      s.ForegroundColor,
+			Bg:      s.BackgroundColor,
+		})
+		x += cluster.Width
+	}
+}
+
+func (m *MockRenderContext) SetCursor(x, y int) {
+	p := geometry.Point{X: x, Y: y}.Add(m.offset)
+	for _, clip := range m.clips {
+		if !clip.Contains(p) {
+			return
+		}
+	}
+	m.cursor = p
 }
 
 func (m *MockRenderContext) PushOffset(offset geometry.Point) {
+	m.offsets = append(m.offsets, m.offset)
 	m.offset = geometry.Point{
 		X: m.offset.X + offset.X,
 		Y: m.offset.Y + offset.Y,
@@ -54,11 +102,32 @@ func (m *MockRenderContext) PushOffset(offset geometry.Point) {
 }
 
 func (m *MockRenderContext) PopOffset() {
-	m.offset = geometry.Point{X: 0, Y: 0}
+	if len(m.offsets) == 0 {
+		m.offset = geometry.Point{X: 0, Y: 0}
+		return
+	}
+	m.offset = m.offsets[len(m.offsets)-1]
+	m.offsets = m.offsets[:len(m.offsets)-1]
+}
+
+func (m *MockRenderContext) PushClipRect(rect geometry.Rect) {
+	m.clips = append(m.clips, geometry.Rect{Min: rect.Min.Add(m.offset), Max: rect.Max.Add(m.offset)})
+}
+
+func (m *MockRenderContext) PopClipRect() {
+	if len(m.clips) > 0 {
+		m.clips = m.clips[:len(m.clips)-1]
+	}
+}
+
+func (m *MockRenderContext) Capabilities() capabilities.Capabilities {
+	return capabilities.Capabilities{SupportsUnicode: true}
 }
 
-func (m *MockRenderContext) PaintBorder(rect geometry.Rect, style style.Style) {
-	// No-op for now, or implement basic border painting if needed for tests
+func (m *MockRenderContext) PaintBorder(rect geometry.Rect, border style.Border) {
+	border.Walk(rect, true, func(x, y int, ch rune) {
+		m.DrawCell(x, y, ch, border.ForegroundColor, border.BackgroundColor)
+	})
 }
 
 // MockChildRenderObject implements RenderObject for testing child paint calls
@@ -154,8 +223,9 @@ func TestBaseRenderObject_Paint(t *testing.T) {
 // BaseRenderBox tests
 func TestBaseRenderBox_Rects(t *testing.T) {
 	style := WidgetStyle{
-		Padding: EdgeInsetsAll(5),
-		Margin:  EdgeInsetsAll(10),
+		Padding:     EdgeInsetsAll(5),
+		Margin:      EdgeInsetsAll(10),
+		BorderWidth: EdgeInsetsAll(1),
 	}
 	box := &BaseRenderBox{
 		BaseRenderObject: BaseRenderObject{
@@ -164,25 +234,118 @@ func TestBaseRenderBox_Rects(t *testing.T) {
 		},
 	}
 
-	// Test ContentRect
-	contentRect := box.ContentRect()
-	assert.Equal(t, geometry.Point{X: 5, Y: 5}, contentRect.Min)
-	assert.Equal(t, geometry.Point{X: 95, Y: 95}, contentRect.Max)
+	// Test MarginRect
+	marginRect := box.MarginRect()
+	assert.Equal(t, geometry.Point{X: 0, Y: 0}, marginRect.Min)
+	assert.Equal(t, geometry.Point{X: 100, Y: 100}, marginRect.Max)
+
+	// Test BorderRect
+	borderRect := box.BorderRect()
+	assert.Equal(t, geometry.Point{X: 10, Y: 10}, borderRect.Min)
+	assert.Equal(t, geometry.Point{X: 90, Y: 90}, borderRect.Max)
 
 	// Test PaddingRect
 	paddingRect := box.PaddingRect()
-	assert.Equal(t, geometry.Point{X: 0, Y: 0}, paddingRect.Min)
-	assert.Equal(t, geometry.Point{X: 100, Y: 100}, paddingRect.Max)
+	assert.Equal(t, geometry.Point{X: 11, Y: 11}, paddingRect.Min)
+	assert.Equal(t, geometry.Point{X: 89, Y: 89}, paddingRect.Max)
 
-	// Test BorderRect
-	borderRect := box.BorderRect()
-	assert.Equal(t, geometry.Point{X: -10, Y: -10}, borderRect.Min)
-	assert.Equal(t, geometry.Point{X: 110, Y: 110}, borderRect.Max)
+	// Test ContentRect
+	contentRect := box.ContentRect()
+	assert.Equal(t, geometry.Point{X: 16, Y: 16}, contentRect.Min)
+	assert.Equal(t, geometry.Point{X: 84, Y: 84}, contentRect.Max)
+}
 
-	// Test MarginRect
-	marginRect := box.MarginRect()
-	assert.Equal(t, geometry.Point{X: -20, Y: -20}, marginRect.Min)
-	assert.Equal(t, geometry.Point{X: 120, Y: 120}, marginRect.Max)
+func TestBaseRenderBox_RectsTooSmall(t *testing.T) {
+	box := &BaseRenderBox{
+		BaseRenderObject: BaseRenderObject{
+			style: WidgetStyle{Padding: EdgeInsetsAll(2), Margin: EdgeInsetsAll(1)},
+			size:  geometry.Size{Width: 3, Height: 3},
+		},
+	}
+
+	assert.True(t, box.ContentRect().IsEmpty())
+	assert.Equal(t, geometry.Point{X: 3, Y: 3}, box.ContentRect().Min)
+	assert.Equal(t, geometry.Point{X: 3, Y: 3}, box.ContentRect().Max)
+}
+
+func TestBaseRenderBox_LayoutReservesMargin(t *testing.T) {
+	style := NewWidgetStyle().
+		WithMargin(NewEdgeInsets(1, 2, 1, 2)).
+		WithBorder(BorderSingle, color.Red, EdgeInsetsAll(1)).
+		WithPadding(EdgeInsetsSymmetric(0, 1))
+	box := NewBaseRenderBox()
+	box.WithStyle(style)
+	box.AppendChild(NewTextRenderObject(NewWidgetStyle(), "ab"))
+
+	size := box.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 10}))
+
+	// margin 4 + border 2 + padding 2 + text 2 by margin 2 + border 2 + text 1
+	assert.Equal(t, geometry.Size{Width: 10, Height: 5}, size)
+	assert.Equal(t, geometry.Point{X: 4, Y: 2}, childOffset(box.children[0]))
+}
+
+func TestBaseRenderBox_PaintBorder(t *testing.T) {
+	tests := []struct {
+		name  string
+		style WidgetStyle
+		want  []string
+	}{
+		{
+			name:  "double border inside the margin",
+			style: NewWidgetStyle().WithMargin(EdgeInsetsAll(1)).WithBorder(BorderDouble, color.Red, EdgeInsetsAll(1)),
+			want:  []string{"     ", " ╔═╗ ", " ║ ║ ", " ╚═╝ ", "     "},
+		},
+		{
+			name:  "rounded border with a title",
+			style: NewWidgetStyle().WithBorder(BorderRounded, color.Red, EdgeInsetsAll(1)).WithBorderTitle("Hi"),
+			want:  []string{"╭Hi─╮", "│   │", "╰───╯"},
+		},
+		{
+			name:  "bottom border only",
+			style: NewWidgetStyle().WithBorder(BorderHeavy, color.Red, EdgeInsets{Bottom: 1}),
+			want:  []string{"     ", "━━━━━"},
+		},
+		{
+			name:  "no border style draws nothing",
+			style: NewWidgetStyle().WithBorderWidth(EdgeInsetsAll(1)),
+			want:  []string{"     ", "     "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := NewBaseRenderBox()
+			box.WithStyle(tt.style)
+			box.size = geometry.Size{Width: 5, Height: len(tt.want)}
+
+			ctx := NewMockRenderContext()
+			box.Paint(ctx)
+
+			for y, row := range tt.want {
+				for x, want := range []rune(row) {
+					got := ctx.cells[geometry.Point{X: x, Y: y}].Rune
+					if got == 0 {
+						got = ' '
+					}
+					assert.Equal(t, want, got, "cell (%d, %d)", x, y)
+				}
+			}
+		})
+	}
+
+	t.Run("border cells use the border color", func(t *testing.T) {
+		box := NewBaseRenderBox()
+		box.WithStyle(NewWidgetStyle().WithBackground(color.Blue).WithBorder(BorderSingle, color.Red, EdgeInsetsAll(1)))
+		box.size = geometry.Size{Width: 3, Height: 3}
+
+		ctx := NewMockRenderContext()
+		box.Paint(ctx)
+
+		corner := ctx.cells[geometry.Point{X: 0, Y: 0}]
+		assert.Equal(t, '┌', corner.Rune)
+		assert.Equal(t, color.Red, corner.Fg)
+		assert.Equal(t, color.Blue, corner.Bg)
+	})
 }
 
 func TestBaseRenderBox_Paint(t *testing.T) {
@@ -389,7 +552,79 @@ func TestBaseRenderBox_LayoutWithMultipleChildren(t *testing.T) {
 	)
 	size := box.Layout(constraints)
 
-	// Currently, only first child affects layout
+	// Children are laid out on top of each other with the same constraints
 	assert.Equal(t, constraints.MinSize, size)
 	assert.Equal(t, constraints, child1.Constraints())
 }
+
+// countingRenderObject counts how often it is laid out
+type countingRenderObject struct {
+	BaseRenderObject
+	layouts int
+}
+
+func (r *countingRenderObject) Layout(constraints Constraints) geometry.Size {
+	r.layouts++
+	return r.BaseRenderObject.Layout(constraints)
+}
+
+func TestLayoutIfNeeded(t *testing.T) {
+	constraints := NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 10})
+
+	t.Run("only marked render objects are laid out again", func(t *testing.T) {
+		box := &BaseRenderBox{}
+		first := &countingRenderObject{}
+		second := &countingRenderObject{}
+		box.SetChildren([]RenderObject{first, second})
+
+		LayoutIfNeeded(box, constraints)
+		LayoutIfNeeded(box, constraints)
+		assert.Equal(t, 1, first.layouts)
+		assert.Equal(t, 1, second.layouts)
+
+		second.MarkNeedsLayout()
+		LayoutIfNeeded(box, constraints)
+		assert.Equal(t, 1, first.layouts)
+		assert.Equal(t, 2, second.layouts)
+	})
+
+	t.Run("new constraints lay out again", func(t *testing.T) {
+		child := &countingRenderObject{}
+		LayoutIfNeeded(child, constraints)
+		LayoutIfNeeded(child, NewConstraints(geometry.Size{}, geometry.Size{Width: 5, Height: 5}))
+		assert.Equal(t, 2, child.layouts)
+	})
+
+	t.Run("new children lay out their parent again", func(t *testing.T) {
+		parent := &countingRenderObject{}
+		LayoutIfNeeded(parent, constraints)
+
+		parent.SetChildren([]RenderObject{&countingRenderObject{}})
+		LayoutIfNeeded(parent, constraints)
+		assert.Equal(t, 2, parent.layouts)
+
+		// The same children again change nothing
+		parent.SetChildren(slices.Clone(parent.Children()))
+		LayoutIfNeeded(parent, constraints)
+		assert.Equal(t, 2, parent.layouts)
+	})
+}
+
+func TestNeedsPaint(t *testing.T) {
+	ctx := NewMockRenderContext()
+	parent := &BaseRenderBox{}
+	child := &countingRenderObject{}
+	parent.SetChildren([]RenderObject{child})
+	LayoutIfNeeded(parent, NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 10}))
+
+	assert.True(t, NeedsPaint(parent))
+	PaintRoot(ctx, parent)
+	assert.False(t, NeedsPaint(parent))
+	assert.False(t, NeedsPaint(child))
+
+	// Paint changes are painted without a new layout
+	child.MarkNeedsPaint()
+	assert.True(t, NeedsPaint(parent))
+	LayoutIfNeeded(parent, NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 10}))
+	assert.Equal(t, 1, child.layouts)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+
+	"github.com/watzon/tide/pkg/core/style"
+)
+
+// TextSpan is a piece of styled text and the spans that follow it. A span
+// inherits its parent's style: colors it leaves transparent show its
+// parent's, and the text properties it sets add to its parent's.
+type TextSpan struct {
+	text     string
+	style    style.Style
+	children []*TextSpan
+}
+
+// NewTextSpan creates a span of text followed by children
+func NewTextSpan(text string, children ...*TextSpan) *TextSpan {
+	return &TextSpan{text: text, children: children}
+}
+
+// WithStyle sets the style of the span and, where they don't set their
+// own, its children
+func (s *TextSpan) WithStyle(style style.Style) *TextSpan {
+	s.style = style
+	return s
+}
+
+// PlainText returns the text of the span and its children without styles
+func (s *TextSpan) PlainText() string {
+	var text strings.Builder
+	s.flatten(style.Style{}, &text, nil)
+	return text.String()
+}
+
+// textRun is a piece of content in one style, from where the run before
+// ends up to end, a byte offset into the content
+type textRun struct {
+	end   int
+	style style.Style
+}
+
+// flatten writes the text of the span and its children to text, appending
+// a run for each piece with its style laid over parent
+func (s *TextSpan) flatten(parent style.Style, text *strings.Builder, runs []textRun) []textRun {
+	resolved := parent.Merge(s.style)
+	if s.text != "" {
+		text.WriteString(s.text)
+		runs = append(runs, textRun{end: text.Len(), style: resolved})
+	}
+	for _, child := range s.children {
+		if child != nil {
+			runs = child.flatten(resolved, text, runs)
+		}
+	}
+	return runs
+}
+
+// RichText displays a tree of TextSpans as one paragraph, each piece in
+// its own style. It wraps, aligns and cuts off its text like Text, and the
+// widget style is the base the root span inherits from.
+type RichText struct {
+	BaseWidget
+	root    *TextSpan
+	options textOptions
+}
+
+// NewRichText creates a widget showing the span tree under root
+func NewRichText(root *TextSpan) *RichText {
+	return &RichText{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		root:       root,
+		options:    defaultTextOptions(),
+	}
+}
+
+func (t *RichText) Build(context BuildContext) Widget {
+	return t
+}
+
+func (t *RichText) CreateRenderObject() RenderObject {
+	r := NewTextRenderObject(t.GetStyle(), "")
+	t.UpdateRenderObject(r)
+	return r
+}
+
+func (t *RichText) UpdateRenderObject(renderObject RenderObject) {
+	r, ok := renderObject.(*TextRenderObject)
+	if !ok {
+		return
+	}
+	r.style = t.GetStyle()
+	r.options = t.options
+	r.content, r.runs = "", nil
+	if t.root != nil {
+		var text strings.Builder
+		r.runs = t.root.flatten(r.style.Style, &text, nil)
+		r.content = text.String()
+	}
+}
+
+func (t *RichText) WithStyle(style WidgetStyle) *RichText {
+	t.style = style
+	return t
+}
+
+// WithSoftWrap sets whether lines wrap to fit the width. Without wrapping
+// lines only break at newlines.
+func (t *RichText) WithSoftWrap(softWrap bool) *RichText {
+	t.options.softWrap = softWrap
+	return t
+}
+
+// WithLineBreak sets where wrapping may break lines
+func (t *RichText) WithLineBreak(lineBreak LineBreak) *RichText {
+	t.options.lineBreak = lineBreak
+	return t
+}
+
+// WithTextAlign sets where lines sit across the width of the text
+func (t *RichText) WithTextAlign(align TextAlign) *RichText {
+	t.options.align = align
+	return t
+}
+
+// WithMaxLines limits the lines shown, or removes the limit when zero
+func (t *RichText) WithMaxLines(maxLines int) *RichText {
+	t.options.maxLines = max(0, maxLines)
+	return t
+}
+
+// WithOverflow sets how text that doesn't fit is cut off
+func (t *RichText) WithOverflow(overflow TextOverflow) *RichText {
+	t.options.overflow = overflow
+	return t
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/color"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/style"
+)
+
+// logLine is a log line with its level and a path highlighted
+func logLine() *TextSpan {
+	return NewTextSpan("",
+		NewTextSpan("error:").WithStyle(style.Style{ForegroundColor: color.Red, Bold: true}),
+		NewTextSpan(" open ",
+			NewTextSpan("/tmp/x").WithStyle(style.Style{Underline: true}),
+		).WithStyle(style.Style{ForegroundColor: color.Gray}),
+	)
+}
+
+func TestTextSpan_Flatten(t *testing.T) {
+	root := logLine()
+	assert.Equal(t, "error: open /tmp/x", root.PlainText())
+
+	var text strings.Builder
+	runs := root.flatten(style.Style{ForegroundColor: color.White, Italic: true}, &text, nil)
+	assert.Equal(t, []textRun{
+		{end: 6, style: style.Style{ForegroundColor: color.Red, Bold: true, Italic: true}},
+		{end: 12, style: style.Style{ForegroundColor: color.Gray, Italic: true}},
+		{end: 18, style: style.Style{ForegroundColor: color.Gray, Italic: true, Underline: true}},
+	}, runs, "spans inherit and add to their parent's style")
+}
+
+// paintRichText lays text out filling width and paints it, returning the
+// mock and the painted lines without trailing space
+func paintRichText(text *RichText, width int) (*MockRenderContext, []string) {
+	r := text.CreateRenderObject()
+	size := r.Layout(NewConstraints(geometry.Size{Width: width}, geometry.Size{Width: width, Height: 10}))
+	m := NewMockRenderContext()
+	r.Paint(m)
+	lines := make([]string, size.Height)
+	for y := range lines {
+		lines[y] = strings.TrimRight(screenRow(m, y, width), " ")
+	}
+	return m, lines
+}
+
+func TestRichText_Paint(t *testing.T) {
+	base := NewWidgetStyle().WithForeground(color.White).WithBackground(color.Blue)
+
+	t.Run("wraps across spans as one paragraph", func(t *testing.T) {
+		m, lines := paintRichText(NewRichText(logLine()).WithStyle(base), 11)
+		assert.Equal(t, []string{"error: open", "/tmp/x"}, lines)
+
+		assert.Equal(t, color.Red, m.cells[geometry.Point{X: 0, Y: 0}].Fg)
+		assert.Equal(t, color.Gray, m.cells[geometry.Point{X: 7, Y: 0}].Fg)
+		assert.Equal(t, color.Gray, m.cells[geometry.Point{X: 0, Y: 1}].Fg)
+		assert.Equal(t, color.Blue, m.cells[geometry.Point{X: 0, Y: 1}].Bg, "transparent backgrounds show the widget's")
+	})
+
+	t.Run("styles follow justified text", func(t *testing.T) {
+		m, lines := paintRichText(NewRichText(logLine()).WithStyle(base).WithTextAlign(TextAlignJustify), 12)
+		assert.Equal(t, []string{"error:  open", "/tmp/x"}, lines)
+		assert.Equal(t, color.Red, m.cells[geometry.Point{X: 5, Y: 0}].Fg)
+		assert.Equal(t, color.Gray, m.cells[geometry.Point{X: 8, Y: 0}].Fg)
+	})
+
+	t.Run("styles stay with their text across dropped spaces", func(t *testing.T) {
+		root := NewTextSpan("",
+			NewTextSpan("a   ").WithStyle(style.Style{ForegroundColor: color.Red}),
+			NewTextSpan("b").WithStyle(style.Style{ForegroundColor: color.Green}),
+		)
+		m, lines := paintRichText(NewRichText(root), 2)
+		assert.Equal(t, []string{"a", "b"}, lines)
+		assert.Equal(t, color.Green, m.cells[geometry.Point{X: 0, Y: 1}].Fg)
+	})
+
+	t.Run("an ellipsis takes the style of the text it cuts", func(t *testing.T) {
+		m, lines := paintRichText(NewRichText(logLine()).WithSoftWrap(false).WithOverflow(OverflowEllipsis), 5)
+		assert.Equal(t, []string{"erro…"}, lines)
+		assert.Equal(t, color.Red, m.cells[geometry.Point{X: 4, Y: 0}].Fg)
+	})
+
+	t.Run("without spans", func(t *testing.T) {
+		_, lines := paintRichText(NewRichText(nil), 5)
+		assert.Equal(t, []string{""}, lines)
+	})
+}
+
+func TestRichText_UpdateRenderObject(t *testing.T) {
+	r := NewRichText(logLine()).CreateRenderObject().(*TextRenderObject)
+	NewRichText(NewTextSpan("plain")).UpdateRenderObject(r)
+	assert.Equal(t, "plain", r.content)
+	assert.Len(t, r.runs, 1)
+
+	NewText("text").UpdateRenderObject(r)
+	assert.Empty(t, r.runs, "Text paints in its own style")
+}
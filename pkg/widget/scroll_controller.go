@@ -0,0 +1,188 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"time"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// scrollFrameInterval is the time between the steps of a scroll animation
+const scrollFrameInterval = 16 * time.Millisecond
+
+// ScrollController reads and changes the scroll offset of a scroll view.
+// The view it is attached to reports the size of the viewport and the
+// content, which bound the offset. Like the rest of the tree, a controller
+// must only be used from the build goroutine.
+type ScrollController struct {
+	offset    int
+	viewport  int
+	content   int
+	listeners []*scrollListener
+
+	// post runs animation steps on the build goroutine
+	post func(func())
+	// generation changes whenever a new scroll starts, stopping any
+	// animation that is running
+	generation int
+	// view is the scroll view the controller is attached to
+	view *RenderScrollView
+}
+
+// scrollListener wraps a listener so it can be found again for removal
+type scrollListener struct {
+	fn func()
+}
+
+// NewScrollController creates a controller starting at the top of the
+// content
+func NewScrollController() *ScrollController {
+	return &ScrollController{}
+}
+
+// Offset returns how many cells the content is scrolled by
+func (c *ScrollController) Offset() int {
+	return c.offset
+}
+
+// MaxOffset returns the largest offset, where the end of the content is at
+// the end of the viewport
+func (c *ScrollController) MaxOffset() int {
+	return max(0, c.content-c.viewport)
+}
+
+// ViewportExtent returns the length of the viewport along the scroll axis
+func (c *ScrollController) ViewportExtent() int {
+	return c.viewport
+}
+
+// ContentExtent returns the length of the content along the scroll axis
+func (c *ScrollController) ContentExtent() int {
+	return c.content
+}
+
+// AddListener registers fn to be called whenever the offset changes and
+// returns a function that removes it again
+func (c *ScrollController) AddListener(fn func()) (remove func()) {
+	listener := &scrollListener{fn: fn}
+	c.listeners = append(c.listeners, listener)
+	return func() {
+		for i, l := range c.listeners {
+			if l == listener {
+				c.listeners = append(c.listeners[:i:i], c.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// JumpTo scrolls to offset immediately, stopping any running animation.
+// The offset is clamped to the scrollable range.
+func (c *ScrollController) JumpTo(offset int) {
+	c.generation++
+	c.setOffset(offset)
+}
+
+// ScrollBy scrolls by delta cells, towards the end of the content when
+// positive
+func (c *ScrollController) ScrollBy(delta int) {
+	c.JumpTo(c.offset + delta)
+}
+
+// AnimateTo scrolls to offset over duration, slowing down towards the end.
+// Without an attached scroll view, or with no duration, it jumps instead.
+func (c *ScrollController) AnimateTo(offset int, duration time.Duration) {
+	c.generation++
+	if duration <= 0 || c.post == nil {
+		c.setOffset(offset)
+		return
+	}
+
+	generation, from, start := c.generation, c.offset, time.Now()
+	to := c.clamp(offset)
+	var step func()
+	step = func() {
+		if generation != c.generation {
+			return
+		}
+		t := min(1, float64(time.Since(start))/float64(duration))
+		// Ease out so the scroll settles gently on its target
+		eased := 1 - math.Pow(1-t, 3)
+		c.setOffset(from + int(math.Round(float64(to-from)*eased)))
+		if t < 1 {
+			time.AfterFunc(scrollFrameInterval, func() { c.post(step) })
+		}
+	}
+	step()
+}
+
+// ShowRange scrolls the least distance that brings the content between
+// start and start+extent into view. A range longer than the viewport is
+// aligned to its start.
+func (c *ScrollController) ShowRange(start, extent int) {
+	end := start + extent
+	switch {
+	case start < c.offset || extent > c.viewport:
+		c.JumpTo(start)
+	case end > c.offset+c.viewport:
+		c.JumpTo(end - c.viewport)
+	}
+}
+
+// EnsureVisible scrolls the attached scroll view until the widget with key
+// is in view. It reports false when the key is not mounted inside the
+// view's content.
+func (c *ScrollController) EnsureVisible(key *GlobalKey) bool {
+	if c.view == nil || len(c.view.children) == 0 {
+		return false
+	}
+	element := key.CurrentElement()
+	if element == nil || element.RenderObject() == nil {
+		return false
+	}
+
+	origin, ok := renderOffsetWithin(element, c.view.children[0])
+	if !ok {
+		return false
+	}
+	axis := c.view.direction
+	c.ShowRange(axis.main(geometry.Size{Width: origin.X, Height: origin.Y}), axis.main(element.RenderObject().Size()))
+	return true
+}
+
+// attach gives the controller a way to run animation steps on the build
+// goroutine
+func (c *ScrollController) attach(post func(func())) {
+	c.post = post
+}
+
+// updateExtents records the size of the viewport and content after layout
+// and keeps the offset in range without notifying listeners
+func (c *ScrollController) updateExtents(viewport, content int) {
+	c.viewport = viewport
+	c.content = content
+	c.offset = c.clamp(c.offset)
+}
+
+// clamp limits an offset to the scrollable range
+func (c *ScrollController) clamp(offset int) int {
+	return max(0, min(offset, c.MaxOffset()))
+}
+
+// setOffset moves to a clamped offset and notifies the listeners if it
+// changed
+func (c *ScrollController) setOffset(offset int) {
+	offset = c.clamp(offset)
+	if offset == c.offset {
+		return
+	}
+	c.offset = offset
+	for _, listener := range append([]*scrollListener(nil), c.listeners...) {
+		listener.fn()
+	}
+}
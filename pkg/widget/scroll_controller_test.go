@@ -0,0 +1,160 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newExtentController creates a controller as if laid out in a viewport of
+// the given extent over content of the given extent
+func newExtentController(viewport, content int) *ScrollController {
+	c := NewScrollController()
+	c.updateExtents(viewport, content)
+	return c
+}
+
+func TestScrollController_JumpTo(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int
+		want   int
+	}{
+		{"within range", 4, 4},
+		{"before the start", -3, 0},
+		{"past the end", 50, 16},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newExtentController(4, 20)
+			c.JumpTo(tt.offset)
+			assert.Equal(t, tt.want, c.Offset())
+		})
+	}
+
+	t.Run("content shorter than the viewport", func(t *testing.T) {
+		c := newExtentController(10, 4)
+		c.JumpTo(3)
+		assert.Equal(t, 0, c.Offset())
+		assert.Equal(t, 0, c.MaxOffset())
+	})
+
+	t.Run("shrinking content clamps the offset", func(t *testing.T) {
+		c := newExtentController(4, 20)
+		c.JumpTo(16)
+		c.updateExtents(4, 10)
+		assert.Equal(t, 6, c.Offset())
+	})
+}
+
+func TestScrollController_Listeners(t *testing.T) {
+	c := newExtentController(4, 20)
+	calls := 0
+	remove := c.AddListener(func() { calls++ })
+
+	c.ScrollBy(2)
+	assert.Equal(t, 1, calls)
+
+	// Scrolling to the same offset isn't a change
+	c.JumpTo(2)
+	assert.Equal(t, 1, calls)
+
+	remove()
+	c.ScrollBy(2)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 4, c.Offset())
+}
+
+func TestScrollController_ShowRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		offset        int
+		start, extent int
+		want          int
+	}{
+		{"already visible", 5, 6, 2, 5},
+		{"above the viewport", 5, 2, 1, 2},
+		{"below the viewport", 5, 10, 2, 8},
+		{"longer than the viewport", 5, 10, 8, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newExtentController(4, 20)
+			c.JumpTo(tt.offset)
+			c.ShowRange(tt.start, tt.extent)
+			assert.Equal(t, tt.want, c.Offset())
+		})
+	}
+}
+
+func TestScrollController_AnimateTo(t *testing.T) {
+	t.Run("jumps without a build owner", func(t *testing.T) {
+		c := newExtentController(4, 20)
+		c.AnimateTo(10, time.Second)
+		assert.Equal(t, 10, c.Offset())
+	})
+
+	t.Run("steps on the build goroutine", func(t *testing.T) {
+		owner := NewBuildOwner()
+		c := newExtentController(4, 20)
+		c.attach(owner.Post)
+
+		var offsets []int
+		c.AddListener(func() { offsets = append(offsets, c.Offset()) })
+		c.AnimateTo(12, 50*time.Millisecond)
+
+		deadline := time.Now().Add(time.Second)
+		for c.Offset() != 12 && time.Now().Before(deadline) {
+			owner.FlushPosted()
+			time.Sleep(time.Millisecond)
+		}
+
+		assert.Equal(t, 12, c.Offset())
+		assert.Greater(t, len(offsets), 1, "the animation passes through intermediate offsets")
+		for i := 1; i < len(offsets); i++ {
+			assert.Greater(t, offsets[i], offsets[i-1])
+		}
+	})
+
+	t.Run("a jump stops the animation", func(t *testing.T) {
+		owner := NewBuildOwner()
+		c := newExtentController(4, 20)
+		c.attach(owner.Post)
+
+		c.AnimateTo(16, 30*time.Millisecond)
+		c.JumpTo(1)
+		time.Sleep(60 * time.Millisecond)
+		owner.FlushPosted()
+
+		assert.Equal(t, 1, c.Offset())
+	})
+}
+
+func TestScrollController_EnsureVisible(t *testing.T) {
+	key := NewGlobalKey("target")
+	target := NewText("target")
+	target.WithKey(key)
+
+	items := []Widget{}
+	for i := 0; i < 10; i++ {
+		items = append(items, NewText("item"))
+	}
+	items = append(items, target)
+
+	c := NewScrollController()
+	mountScrollView(NewSingleChildScrollView(NewColumn(items...)).WithController(c), 10, 4)
+
+	assert.True(t, c.EnsureVisible(key))
+	assert.Equal(t, 7, c.Offset())
+
+	other := NewGlobalKey("other")
+	assert.False(t, c.EnsureVisible(other))
+}
@@ -0,0 +1,374 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// wheelScrollLines is how far one notch of the mouse wheel scrolls
+const wheelScrollLines = 3
+
+// SingleChildScrollView lets a child that is longer than the available
+// space be scrolled along one axis. The child is laid out without a limit
+// along that axis and clipped to the viewport. The view scrolls with the
+// mouse wheel, through a ScrollController, and with PgUp/PgDn, Home/End
+// and the arrow keys while it has focus, which a click gives it.
+type SingleChildScrollView struct {
+	BaseWidget
+	child      Widget
+	direction  Axis
+	controller *ScrollController
+	focusNode  *FocusNode
+	scrollbar  bool
+}
+
+// NewSingleChildScrollView creates a vertical scroll view around child with
+// a scrollbar
+func NewSingleChildScrollView(child Widget) *SingleChildScrollView {
+	return &SingleChildScrollView{
+		child:     child,
+		direction: AxisVertical,
+		scrollbar: true,
+	}
+}
+
+// WithDirection sets the axis the view scrolls along
+func (w *SingleChildScrollView) WithDirection(direction Axis) *SingleChildScrollView {
+	w.direction = direction
+	return w
+}
+
+// WithController sets the controller that reads and changes the offset.
+// Without one the view creates its own.
+func (w *SingleChildScrollView) WithController(controller *ScrollController) *SingleChildScrollView {
+	w.controller = controller
+	return w
+}
+
+// WithFocusNode sets the node that gives the view focus. Without one the
+// view creates its own.
+func (w *SingleChildScrollView) WithFocusNode(node *FocusNode) *SingleChildScrollView {
+	w.focusNode = node
+	return w
+}
+
+// WithScrollbar sets whether a scrollbar is drawn when the child overflows
+func (w *SingleChildScrollView) WithScrollbar(scrollbar bool) *SingleChildScrollView {
+	w.scrollbar = scrollbar
+	return w
+}
+
+func (w *SingleChildScrollView) WithStyle(style WidgetStyle) *SingleChildScrollView {
+	w.style = style
+	return w
+}
+
+func (w *SingleChildScrollView) CreateState() State {
+	return &scrollViewState{}
+}
+
+// scrollBinding keeps a listener on the controller a widget was given, or
+// on a controller of its own when it has none
+type scrollBinding struct {
+	controller *ScrollController
+	owned      *ScrollController
+	remove     func()
+}
+
+// bind listens to controller, or to the owned controller if it is nil,
+// moving the listener over if the controller changed. post runs animation
+// steps on the build goroutine. It returns the controller in use.
+func (b *scrollBinding) bind(controller *ScrollController, listener func(), post func(func())) *ScrollController {
+	if controller == nil {
+		if b.owned == nil {
+			b.owned = NewScrollController()
+		}
+		controller = b.owned
+	}
+	if controller != b.controller {
+		b.unbind()
+		b.controller = controller
+		b.remove = controller.AddListener(listener)
+		controller.attach(post)
+	}
+	return controller
+}
+
+// unbind removes the listener
+func (b *scrollBinding) unbind() {
+	if b.remove != nil {
+		b.remove()
+		b.remove = nil
+	}
+}
+
+// scrollViewState rebuilds the view whenever its controller scrolls
+type scrollViewState struct {
+	BaseState
+	binding scrollBinding
+	focus   focusBinding
+}
+
+func (s *scrollViewState) InitState() {
+	s.bind()
+	s.focus.bind(s.Widget().(*SingleChildScrollView).focusNode, s.Element(), s.changed)
+}
+
+func (s *scrollViewState) Dispose() {
+	s.binding.unbind()
+	s.focus.unbind()
+}
+
+func (s *scrollViewState) Build(context BuildContext) Widget {
+	w := s.Widget().(*SingleChildScrollView)
+	return &scrollViewport{
+		BaseWidget: BaseWidget{style: w.style},
+		child:      w.child,
+		direction:  w.direction,
+		controller: s.bind(),
+		focus:      s.focus.bind(w.focusNode, s.Element(), s.changed),
+		scrollbar:  w.scrollbar,
+	}
+}
+
+// bind listens to the widget's controller and returns it
+func (s *scrollViewState) bind() *ScrollController {
+	controller := s.Widget().(*SingleChildScrollView).controller
+	return s.binding.bind(controller, s.changed, s.Context().Post)
+}
+
+func (s *scrollViewState) changed() {
+	s.SetState(nil)
+}
+
+// scrollViewport is the widget the scroll view builds, which creates the
+// render object doing the scrolling
+type scrollViewport struct {
+	BaseWidget
+	child      Widget
+	direction  Axis
+	controller *ScrollController
+	focus      *FocusNode
+	scrollbar  bool
+}
+
+// GetChildren returns the scrolled child, if any
+func (w *scrollViewport) GetChildren() []Widget {
+	if w.child == nil {
+		return nil
+	}
+	return []Widget{w.child}
+}
+
+func (w *scrollViewport) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *scrollViewport) CreateRenderObject() RenderObject {
+	r := &RenderScrollView{}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *scrollViewport) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderScrollView); ok {
+		r.style = w.style
+		r.direction = w.direction
+		r.controller = w.controller
+		r.focus = w.focus
+		r.scrollbar = w.scrollbar
+	}
+}
+
+// RenderScrollView shows the part of its child selected by the offset of
+// its controller
+type RenderScrollView struct {
+	BaseRenderObject
+	direction  Axis
+	controller *ScrollController
+	focus      *FocusNode
+	scrollbar  bool
+	// showScrollbar is set by layout when the child overflows and a
+	// scrollbar was asked for
+	showScrollbar bool
+}
+
+func (r *RenderScrollView) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	r.showScrollbar = false
+	if len(r.children) == 0 {
+		r.size = constraints.Constrain(constraints.MinSize)
+		r.controller.updateExtents(r.direction.main(r.size), 0)
+		return r.size
+	}
+
+	axis := r.direction
+	child := r.children[0]
+	childSize := LayoutIfNeeded(child, r.childConstraints(constraints, 0))
+	viewport := axis.main(constraints.Constrain(childSize))
+
+	// The scrollbar takes the last cell across the axis
+	if r.scrollbar && axis.main(childSize) > viewport && axis.cross(constraints.MaxSize) > 1 {
+		r.showScrollbar = true
+		childSize = LayoutIfNeeded(child, r.childConstraints(constraints, 1))
+		childSize = axis.size(axis.main(childSize), axis.cross(childSize)+1)
+	}
+
+	r.size = constraints.Constrain(childSize)
+	r.controller.view = r
+	r.controller.updateExtents(axis.main(r.size), axis.main(child.Size()))
+	r.placeChild()
+	return r.size
+}
+
+// childConstraints passes the cross axis constraints less reserved cells
+// to the child and leaves the scroll axis unbounded
+func (r *RenderScrollView) childConstraints(constraints Constraints, reserved int) Constraints {
+	axis := r.direction
+	maxCross := deflateExtent(axis.cross(constraints.MaxSize), reserved)
+	minCross := min(axis.cross(constraints.MinSize), maxCross)
+	return NewConstraints(axis.size(0, minCross), axis.size(math.MaxInt32, maxCross))
+}
+
+// placeChild moves the child so the scrolled to part is in the viewport
+func (r *RenderScrollView) placeChild() {
+	if len(r.children) > 0 {
+		setChildOffset(r.children[0], r.direction.point(-r.controller.Offset(), 0))
+	}
+}
+
+func (r *RenderScrollView) Paint(context engine.RenderContext) {
+	if len(r.children) > 0 {
+		context.PushClipRect(geometry.NewRect(0, 0, r.size.Width, r.size.Height))
+		paintChild(context, r.children[0])
+		context.PopClipRect()
+	}
+	if r.showScrollbar {
+		paintScrollbar(context, r.direction, r.size, r.controller, r.style)
+	}
+}
+
+// paintScrollbar draws a track along the last cell across the axis of a
+// viewport of the given size, with a thumb sized and placed like the
+// viewport within the content
+func paintScrollbar(context engine.RenderContext, axis Axis, size geometry.Size, controller *ScrollController, style WidgetStyle) {
+	length := axis.main(size)
+	content := controller.ContentExtent()
+	if length <= 0 || content <= 0 {
+		return
+	}
+
+	thumb := max(1, length*length/content)
+	start := 0
+	if maxOffset := controller.MaxOffset(); maxOffset > 0 {
+		start = (length - thumb) * controller.Offset() / maxOffset
+	}
+
+	thumbGlyph, trackGlyph := '█', '░'
+	if !context.Capabilities().SupportsUnicode {
+		thumbGlyph, trackGlyph = '#', '|'
+		if axis == AxisHorizontal {
+			trackGlyph = '-'
+		}
+	}
+
+	cross := axis.cross(size) - 1
+	for i := 0; i < length; i++ {
+		ch := trackGlyph
+		if i >= start && i < start+thumb {
+			ch = thumbGlyph
+		}
+		p := axis.point(i, cross)
+		context.DrawCell(p.X, p.Y, ch, style.ForegroundColor, style.BackgroundColor)
+	}
+}
+
+// HandleEvent scrolls for the mouse wheel, and for paging and arrow keys
+// while the view has focus. A click focuses the view. Events that would
+// not move the view are left for other handlers.
+func (r *RenderScrollView) HandleEvent(event terminal.Event) bool {
+	if r.controller == nil {
+		return false
+	}
+
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+	case terminal.MouseEvent:
+		if ev.Buttons&tcell.ButtonPrimary != 0 && !ev.Motion && r.focus != nil && !r.focus.HasFocus() {
+			r.focus.RequestFocus()
+			return true
+		}
+	}
+
+	if !scrollForEvent(r.controller, r.direction, event) {
+		return false
+	}
+	r.placeChild()
+	return true
+}
+
+// scrollForEvent scrolls controller for paging and arrow keys and the
+// mouse wheel, and reports whether the offset changed
+func scrollForEvent(controller *ScrollController, axis Axis, event terminal.Event) bool {
+	var delta int
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		delta = scrollKeyDelta(controller, axis, ev.Key)
+	case terminal.MouseEvent:
+		delta = wheelDelta(ev.Buttons)
+	}
+	if delta == 0 {
+		return false
+	}
+
+	before := controller.Offset()
+	controller.ScrollBy(delta)
+	return controller.Offset() != before
+}
+
+// scrollKeyDelta returns how far a key scrolls a view along axis
+func scrollKeyDelta(controller *ScrollController, axis Axis, key tcell.Key) int {
+	back, forward := tcell.KeyUp, tcell.KeyDown
+	if axis == AxisHorizontal {
+		back, forward = tcell.KeyLeft, tcell.KeyRight
+	}
+
+	switch key {
+	case back:
+		return -1
+	case forward:
+		return 1
+	case tcell.KeyPgUp:
+		return -max(1, controller.ViewportExtent())
+	case tcell.KeyPgDn:
+		return max(1, controller.ViewportExtent())
+	case tcell.KeyHome:
+		return -controller.Offset()
+	case tcell.KeyEnd:
+		return controller.MaxOffset() - controller.Offset()
+	}
+	return 0
+}
+
+// wheelDelta returns how far a mouse wheel event scrolls a view
+func wheelDelta(buttons tcell.ButtonMask) int {
+	switch {
+	case buttons&(tcell.WheelUp|tcell.WheelLeft) != 0:
+		return -wheelScrollLines
+	case buttons&(tcell.WheelDown|tcell.WheelRight) != 0:
+		return wheelScrollLines
+	}
+	return 0
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// mountScrollView mounts a scroll view, focuses it and lays it out in a
+// viewport of the given size
+func mountScrollView(w *SingleChildScrollView, width, height int) *RenderScrollView {
+	owner := NewBuildOwner()
+	root := NewElement(w)
+	owner.MountRoot(root)
+	r := root.RenderObject().(*RenderScrollView)
+	r.focus.RequestFocus()
+	owner.BuildScope()
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	return r
+}
+
+// numberedLines returns a column of n lines reading "line0", "line1", ...
+func numberedLines(n int) *Flex {
+	lines := make([]Widget, n)
+	for i := range lines {
+		lines[i] = NewText(fmt.Sprintf("line%d", i))
+	}
+	return NewColumn(lines...)
+}
+
+// screenRow returns the characters painted in row y between x = 0 and width
+func screenRow(m *MockRenderContext, y, width int) string {
+	var b strings.Builder
+	for x := 0; x < width; x++ {
+		if cell, ok := m.cells[geometry.Point{X: x, Y: y}]; ok && cell.Cluster != "" {
+			b.WriteString(cell.Cluster)
+		} else if ok {
+			b.WriteRune(cell.Rune)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
+func TestRenderScrollView_Layout(t *testing.T) {
+	t.Run("child is unbounded along the scroll axis", func(t *testing.T) {
+		r := mountScrollView(NewSingleChildScrollView(numberedLines(10)), 10, 4)
+
+		// The view hugs the child across the axis, plus the scrollbar
+		assert.Equal(t, geometry.Size{Width: 6, Height: 4}, r.Size())
+		assert.Equal(t, 10, r.Children()[0].Size().Height)
+		assert.Equal(t, 4, r.controller.ViewportExtent())
+		assert.Equal(t, 10, r.controller.ContentExtent())
+	})
+
+	t.Run("scrollbar takes a column when the child overflows", func(t *testing.T) {
+		r := mountScrollView(NewSingleChildScrollView(numberedLines(10)), 10, 4)
+		assert.Equal(t, 9, r.Children()[0].Constraints().MaxSize.Width)
+
+		r = mountScrollView(NewSingleChildScrollView(numberedLines(10)).WithScrollbar(false), 10, 4)
+		assert.Equal(t, 10, r.Children()[0].Constraints().MaxSize.Width)
+	})
+
+	t.Run("no scrollbar when the child fits", func(t *testing.T) {
+		r := mountScrollView(NewSingleChildScrollView(numberedLines(3)), 10, 4)
+		assert.False(t, r.showScrollbar)
+		assert.Equal(t, 3, r.Size().Height)
+	})
+
+	t.Run("horizontal", func(t *testing.T) {
+		row := NewRow(NewText("abcdef"), NewText("ghijkl"))
+		r := mountScrollView(NewSingleChildScrollView(row).WithDirection(AxisHorizontal), 5, 3)
+
+		assert.Equal(t, geometry.Size{Width: 5, Height: 2}, r.Size())
+		assert.Equal(t, 12, r.controller.ContentExtent())
+	})
+}
+
+func TestRenderScrollView_Paint(t *testing.T) {
+	c := NewScrollController()
+	r := mountScrollView(NewSingleChildScrollView(numberedLines(10)).WithController(c), 8, 4)
+	c.JumpTo(3)
+	r.placeChild()
+
+	m := NewMockRenderContext()
+	r.Paint(m)
+
+	// Lines are clipped to the viewport and the scrollbar fills the last
+	// column, with its thumb partway down
+	assert.Equal(t, []string{
+		"line3░",
+		"line4█",
+		"line5░",
+		"line6░",
+	}, []string{screenRow(m, 0, 6), screenRow(m, 1, 6), screenRow(m, 2, 6), screenRow(m, 3, 6)})
+	assert.Equal(t, "      ", screenRow(m, 4, 6))
+	assert.Equal(t, "      ", screenRow(m, -1, 6))
+}
+
+func TestRenderScrollView_HandleEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		start   int
+		event   terminal.Event
+		want    int
+		handled bool
+	}{
+		{"down arrow", 0, terminal.KeyEvent{Key: tcell.KeyDown}, 1, true},
+		{"up arrow at the top", 0, terminal.KeyEvent{Key: tcell.KeyUp}, 0, false},
+		{"page down", 0, terminal.KeyEvent{Key: tcell.KeyPgDn}, 4, true},
+		{"page up", 5, terminal.KeyEvent{Key: tcell.KeyPgUp}, 1, true},
+		{"end", 2, terminal.KeyEvent{Key: tcell.KeyEnd}, 16, true},
+		{"home", 9, terminal.KeyEvent{Key: tcell.KeyHome}, 0, true},
+		{"other keys", 0, terminal.KeyEvent{Key: tcell.KeyRune, Rune: 'j'}, 0, false},
+		{"wheel down", 0, terminal.MouseEvent{Buttons: tcell.WheelDown, Position: geometry.Point{X: 2, Y: 2}}, 3, true},
+		{"wheel up", 5, terminal.MouseEvent{Buttons: tcell.WheelUp, Position: geometry.Point{X: 2, Y: 2}}, 2, true},
+		{"wheel outside the view", 0, terminal.MouseEvent{Buttons: tcell.WheelDown, Position: geometry.Point{X: 2, Y: 9}}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewScrollController()
+			r := mountScrollView(NewSingleChildScrollView(numberedLines(20)).WithController(c), 10, 4)
+			c.JumpTo(tt.start)
+
+			assert.Equal(t, tt.handled, DispatchEvent(r, tt.event))
+			assert.Equal(t, tt.want, c.Offset())
+			assert.Equal(t, geometry.Point{Y: -tt.want}, childOffset(r.Children()[0]))
+		})
+	}
+}
+
+func TestSingleChildScrollView_KeysNeedFocus(t *testing.T) {
+	text := NewTextEditingController("")
+	input, viewFocus := NewFocusNode(), NewFocusNode()
+	c := NewScrollController()
+	tree := mountControls(NewColumn(
+		NewTextInput().WithController(text).WithFocusNode(input),
+		NewExpanded(NewSingleChildScrollView(numberedLines(20)).WithController(c).WithFocusNode(viewFocus)),
+	), 20, 8)
+	send := func(event terminal.Event) bool {
+		handled := tree.owner.FocusManager().DispatchEvent(tree.root.RenderObject(), event)
+		tree.pump()
+		return handled
+	}
+
+	input.RequestFocus()
+	tree.pump()
+	assert.False(t, send(terminal.KeyEvent{Key: tcell.KeyDown}))
+	assert.False(t, send(terminal.KeyEvent{Key: tcell.KeyPgDn}))
+	assert.Equal(t, 0, c.Offset(), "the unfocused view leaves keys alone")
+
+	assert.True(t, send(terminal.MouseEvent{Buttons: tcell.WheelDown, Position: geometry.Point{X: 2, Y: 7}}))
+	assert.Equal(t, 3, c.Offset(), "the wheel scrolls without focus")
+
+	assert.True(t, send(terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 2, Y: 7}}))
+	assert.True(t, viewFocus.HasFocus(), "a click focuses the view")
+	assert.True(t, send(terminal.KeyEvent{Key: tcell.KeyDown}))
+	assert.Equal(t, 4, c.Offset())
+	assert.Empty(t, text.Text())
+}
+
+func TestSingleChildScrollView_RebuildsOnScroll(t *testing.T) {
+	c := NewScrollController()
+	owner := NewBuildOwner()
+	root := NewElement(NewSingleChildScrollView(numberedLines(10)).WithController(c))
+	owner.MountRoot(root)
+	root.RenderObject().Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 4}))
+
+	c.JumpTo(2)
+	assert.True(t, owner.HasDirtyElements())
+	owner.BuildScope()
+
+	// Swapping the controller moves the listener over
+	other := NewScrollController()
+	root.Update(NewSingleChildScrollView(numberedLines(10)).WithController(other))
+	owner.BuildScope()
+	c.JumpTo(0)
+	assert.False(t, owner.HasDirtyElements())
+}
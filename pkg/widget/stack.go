@@ -0,0 +1,384 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// StackFit controls the constraints a stack passes to its non-positioned
+// children
+type StackFit int
+
+const (
+	// StackFitLoose lets non-positioned children be any size up to the
+	// stack's maximum size
+	StackFitLoose StackFit = iota
+	// StackFitExpand makes non-positioned children as big as the stack allows
+	StackFitExpand
+)
+
+// Clip controls whether a layout's children may paint outside its bounds
+type Clip int
+
+const (
+	// ClipHardEdge cuts children off at the layout's bounds
+	ClipHardEdge Clip = iota
+	// ClipNone lets children paint outside the layout's bounds
+	ClipNone
+)
+
+// Stack overlays its children on top of each other, painting them in order
+// so later children cover earlier ones. The stack sizes itself to its
+// non-positioned children, which sit in its top left corner; children
+// wrapped in Positioned are placed relative to the stack's edges.
+type Stack struct {
+	BaseWidget
+	fit          StackFit
+	clipBehavior Clip
+	children     []Widget
+}
+
+// NewStack creates a stack of children, painted first to last
+func NewStack(children ...Widget) *Stack {
+	return &Stack{
+		BaseWidget: BaseWidget{
+			style: NewWidgetStyle(),
+		},
+		children: children,
+	}
+}
+
+func (s *Stack) AppendChild(child Widget) {
+	s.children = append(s.children, child)
+}
+
+// GetChildren returns the child widgets of the stack
+func (s *Stack) GetChildren() []Widget {
+	return s.children
+}
+
+// WithFit sets the constraints passed to non-positioned children
+func (s *Stack) WithFit(fit StackFit) *Stack {
+	s.fit = fit
+	return s
+}
+
+// WithClipBehavior sets whether children are cut off at the stack's bounds
+func (s *Stack) WithClipBehavior(clip Clip) *Stack {
+	s.clipBehavior = clip
+	return s
+}
+
+// WithStyle sets the style of the stack
+func (s *Stack) WithStyle(style WidgetStyle) *Stack {
+	s.style = style
+	return s
+}
+
+func (s *Stack) Build(context BuildContext) Widget {
+	return s
+}
+
+// CreateRenderObject creates the stack's render object. The render objects
+// of the children are attached by the stack's element.
+func (s *Stack) CreateRenderObject() RenderObject {
+	r := NewRenderStack()
+	s.UpdateRenderObject(r)
+	return r
+}
+
+func (s *Stack) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderStack); ok {
+		r.WithStyle(s.style)
+		r.fit = s.fit
+		r.clipBehavior = s.clipBehavior
+	}
+}
+
+// Positioned places a child of a Stack relative to the stack's edges. A
+// child with both left and right, or both top and bottom, set is stretched
+// between them; otherwise width and height size it along that axis.
+type Positioned struct {
+	BaseWidget
+	child  Widget
+	left   *int
+	top    *int
+	right  *int
+	bottom *int
+	width  *int
+	height *int
+}
+
+// NewPositioned creates a positioned child. Edges that are not set leave
+// the child in the stack's top left corner.
+func NewPositioned(child Widget) *Positioned {
+	return &Positioned{child: child}
+}
+
+// NewPositionedFill creates a positioned child that covers the whole stack
+func NewPositionedFill(child Widget) *Positioned {
+	return NewPositioned(child).WithLeft(0).WithTop(0).WithRight(0).WithBottom(0)
+}
+
+// WithLeft sets the distance from the stack's left edge to the child's
+func (p *Positioned) WithLeft(left int) *Positioned {
+	p.left = &left
+	return p
+}
+
+// WithTop sets the distance from the stack's top edge to the child's
+func (p *Positioned) WithTop(top int) *Positioned {
+	p.top = &top
+	return p
+}
+
+// WithRight sets the distance from the stack's right edge to the child's
+func (p *Positioned) WithRight(right int) *Positioned {
+	p.right = &right
+	return p
+}
+
+// WithBottom sets the distance from the stack's bottom edge to the child's
+func (p *Positioned) WithBottom(bottom int) *Positioned {
+	p.bottom = &bottom
+	return p
+}
+
+// WithWidth sets the width of the child
+func (p *Positioned) WithWidth(width int) *Positioned {
+	p.width = &width
+	return p
+}
+
+// WithHeight sets the height of the child
+func (p *Positioned) WithHeight(height int) *Positioned {
+	p.height = &height
+	return p
+}
+
+// GetChildren returns the wrapped child, if any
+func (p *Positioned) GetChildren() []Widget {
+	if p.child == nil {
+		return nil
+	}
+	return []Widget{p.child}
+}
+
+func (p *Positioned) Build(context BuildContext) Widget {
+	return p
+}
+
+func (p *Positioned) CreateRenderObject() RenderObject {
+	r := &renderProxy{}
+	p.UpdateRenderObject(r)
+	return r
+}
+
+func (p *Positioned) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*renderProxy); ok {
+		r.style = p.style
+		data, ok := r.parentData.(*StackParentData)
+		if !ok {
+			data = &StackParentData{}
+			r.parentData = data
+		}
+		data.Left, data.Top, data.Right, data.Bottom = p.left, p.top, p.right, p.bottom
+		data.Width, data.Height = p.width, p.height
+	}
+}
+
+// StackParentData is the parent data of a positioned child of a
+// RenderStack. Unset edges and sizes are nil.
+type StackParentData struct {
+	BoxParentData
+	Left   *int
+	Top    *int
+	Right  *int
+	Bottom *int
+	Width  *int
+	Height *int
+}
+
+// horizontal returns the child's position along the horizontal axis
+func (d *StackParentData) horizontal() stackEdges {
+	return stackEdges{start: d.Left, end: d.Right, extent: d.Width}
+}
+
+// vertical returns the child's position along the vertical axis
+func (d *StackParentData) vertical() stackEdges {
+	return stackEdges{start: d.Top, end: d.Bottom, extent: d.Height}
+}
+
+// isPositioned reports whether a child of a stack is placed by its parent
+// data rather than in the stack's top left corner
+func isPositioned(child RenderObject) bool {
+	_, ok := child.ParentData().(*StackParentData)
+	return ok
+}
+
+// stackEdges holds the position of a positioned child along one axis
+type stackEdges struct {
+	start  *int
+	end    *int
+	extent *int
+}
+
+// constraints returns the minimum and maximum extent of the child along the
+// axis for a stack of the given extent
+func (e stackEdges) constraints(stack int) (int, int) {
+	switch {
+	case e.start != nil && e.end != nil:
+		extent := max(0, stack-*e.start-*e.end)
+		return extent, extent
+	case e.extent != nil:
+		return *e.extent, *e.extent
+	default:
+		return 0, math.MaxInt32
+	}
+}
+
+// offset returns the position of the child along the axis
+func (e stackEdges) offset(stack, child int) int {
+	switch {
+	case e.start != nil:
+		return *e.start
+	case e.end != nil:
+		return stack - *e.end - child
+	default:
+		return 0
+	}
+}
+
+// RenderStack overlays its children inside the box model of its style
+type RenderStack struct {
+	BaseRenderBox
+	fit          StackFit
+	clipBehavior Clip
+}
+
+// NewRenderStack creates an empty render stack
+func NewRenderStack() *RenderStack {
+	return &RenderStack{
+		BaseRenderBox: *NewBaseRenderBox(),
+	}
+}
+
+func (r *RenderStack) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	content := r.contentConstraints(constraints)
+
+	size := r.layoutNonPositioned(content)
+	r.layoutPositioned(size)
+
+	r.size = constraints.Constrain(r.outerSize(size))
+	return r.size
+}
+
+// layoutNonPositioned lays out the children that are not positioned and
+// returns the size of the stack's content, which is just big enough to
+// hold them. A stack with only positioned children is as big as allowed.
+func (r *RenderStack) layoutNonPositioned(constraints Constraints) geometry.Size {
+	childConstraints := NewConstraints(geometry.Size{}, constraints.MaxSize)
+	if r.fit == StackFitExpand {
+		childConstraints = TightConstraints(constraints.MaxSize)
+	}
+
+	size := constraints.MinSize
+	found := false
+	for _, child := range r.children {
+		if isPositioned(child) {
+			continue
+		}
+		found = true
+		childSize := LayoutIfNeeded(child, childConstraints)
+		setChildOffset(child, r.ContentRect().Min)
+		size.Width = max(size.Width, childSize.Width)
+		size.Height = max(size.Height, childSize.Height)
+	}
+
+	if !found && constraints.MaxSize.Width < math.MaxInt32 && constraints.MaxSize.Height < math.MaxInt32 {
+		return constraints.MaxSize
+	}
+	return constraints.Constrain(size)
+}
+
+// layoutPositioned lays out and places the positioned children against the
+// stack's content size
+func (r *RenderStack) layoutPositioned(size geometry.Size) {
+	for _, child := range r.children {
+		data, ok := child.ParentData().(*StackParentData)
+		if !ok {
+			continue
+		}
+
+		horizontal, vertical := data.horizontal(), data.vertical()
+		minWidth, maxWidth := horizontal.constraints(size.Width)
+		minHeight, maxHeight := vertical.constraints(size.Height)
+		childSize := LayoutIfNeeded(child, NewConstraints(
+			geometry.Size{Width: minWidth, Height: minHeight},
+			geometry.Size{Width: maxWidth, Height: maxHeight},
+		))
+
+		data.SetOffset(r.ContentRect().Min.Add(geometry.Point{
+			X: horizontal.offset(size.Width, childSize.Width),
+			Y: vertical.offset(size.Height, childSize.Height),
+		}))
+	}
+}
+
+// stackIntrinsic measures the stack as its largest non-positioned child
+// plus its insets, since positioned children don't size the stack
+func (r *RenderStack) stackIntrinsic(axis Axis, extent int, minimum bool) int {
+	var children []RenderObject
+	for _, child := range r.children {
+		if !isPositioned(child) {
+			children = append(children, child)
+		}
+	}
+	return r.intrinsicWith(axis, extent, func(extent int) int {
+		return maxIntrinsic(children, extent, intrinsicMeasure(axis, minimum))
+	})
+}
+
+func (r *RenderStack) MinIntrinsicWidth(height int) int {
+	return r.stackIntrinsic(AxisHorizontal, height, true)
+}
+
+func (r *RenderStack) MaxIntrinsicWidth(height int) int {
+	return r.stackIntrinsic(AxisHorizontal, height, false)
+}
+
+func (r *RenderStack) MinIntrinsicHeight(width int) int {
+	return r.stackIntrinsic(AxisVertical, width, true)
+}
+
+func (r *RenderStack) MaxIntrinsicHeight(width int) int {
+	return r.stackIntrinsic(AxisVertical, width, false)
+}
+
+func (r *RenderStack) Paint(context engine.RenderContext) {
+	r.PaintBackground(context)
+	r.PaintBorder(context)
+	r.PaintContent(context)
+}
+
+// PaintContent paints the children in order, so later children are drawn
+// over earlier ones, cutting them off at the content rect unless clipping
+// is disabled
+func (r *RenderStack) PaintContent(context engine.RenderContext) {
+	if r.clipBehavior == ClipHardEdge {
+		context.PushClipRect(r.ContentRect())
+		defer context.PopClipRect()
+	}
+
+	for _, child := range r.children {
+		paintChild(context, child)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// layoutStack mounts a stack widget and lays it out within maxSize
+func layoutStack(s *Stack, maxSize geometry.Size) *RenderStack {
+	elem := NewElement(s)
+	elem.Mount(nil)
+	r := elem.RenderObject().(*RenderStack)
+	r.Layout(NewConstraints(geometry.Size{}, maxSize))
+	return r
+}
+
+func TestStack_Sizing(t *testing.T) {
+	t.Run("sizes to non-positioned children", func(t *testing.T) {
+		s := NewStack(
+			NewText("abc"),
+			NewText("de\nfg"),
+			NewPositioned(NewText("positioned")).WithLeft(5),
+		)
+		r := layoutStack(s, geometry.Size{Width: 20, Height: 10})
+
+		assert.Equal(t, geometry.Size{Width: 3, Height: 2}, r.Size())
+		assert.Equal(t, geometry.Point{}, childOffsets(r)[0])
+		assert.Equal(t, geometry.Point{}, childOffsets(r)[1])
+	})
+
+	t.Run("expand fit fills the constraints", func(t *testing.T) {
+		s := NewStack(NewText("a")).WithFit(StackFitExpand)
+		r := layoutStack(s, geometry.Size{Width: 20, Height: 10})
+
+		assert.Equal(t, geometry.Size{Width: 20, Height: 10}, r.Size())
+		assert.Equal(t, geometry.Size{Width: 20, Height: 10}, r.children[0].Size())
+	})
+
+	t.Run("only positioned children fill the constraints", func(t *testing.T) {
+		s := NewStack(NewPositioned(NewText("a")))
+		r := layoutStack(s, geometry.Size{Width: 20, Height: 10})
+
+		assert.Equal(t, geometry.Size{Width: 20, Height: 10}, r.Size())
+	})
+}
+
+func TestStack_Positioned(t *testing.T) {
+	tests := []struct {
+		name       string
+		positioned *Positioned
+		wantOffset geometry.Point
+		wantSize   geometry.Size
+	}{
+		{
+			name:       "left and top",
+			positioned: NewPositioned(NewText("x")).WithLeft(2).WithTop(1),
+			wantOffset: geometry.Point{X: 2, Y: 1},
+			wantSize:   geometry.Size{Width: 1, Height: 1},
+		},
+		{
+			name:       "right and bottom",
+			positioned: NewPositioned(NewText("x")).WithRight(0).WithBottom(0),
+			wantOffset: geometry.Point{X: 9, Y: 4},
+			wantSize:   geometry.Size{Width: 1, Height: 1},
+		},
+		{
+			name:       "stretched between left and right",
+			positioned: NewPositioned(NewText("x")).WithLeft(1).WithRight(1),
+			wantOffset: geometry.Point{X: 1, Y: 0},
+			wantSize:   geometry.Size{Width: 8, Height: 1},
+		},
+		{
+			name:       "fixed size from the right",
+			positioned: NewPositioned(NewText("x")).WithRight(1).WithWidth(4).WithHeight(2),
+			wantOffset: geometry.Point{X: 5, Y: 0},
+			wantSize:   geometry.Size{Width: 4, Height: 2},
+		},
+		{
+			name:       "fill",
+			positioned: NewPositionedFill(NewText("x")),
+			wantOffset: geometry.Point{},
+			wantSize:   geometry.Size{Width: 10, Height: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStack(NewText("base"), tt.positioned).WithFit(StackFitExpand)
+			r := layoutStack(s, geometry.Size{Width: 10, Height: 5})
+
+			assert.Equal(t, tt.wantOffset, childOffset(r.children[1]))
+			assert.Equal(t, tt.wantSize, r.children[1].Size())
+		})
+	}
+}
+
+func TestStack_Paint(t *testing.T) {
+	t.Run("later children paint over earlier ones", func(t *testing.T) {
+		s := NewStack(
+			NewText("aaa"),
+			NewPositioned(NewText("b")).WithLeft(1),
+		)
+		r := layoutStack(s, geometry.Size{Width: 10, Height: 5})
+
+		ctx := NewMockRenderContext()
+		r.Paint(ctx)
+
+		assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 0, Y: 0}].Rune)
+		assert.Equal(t, 'b', ctx.cells[geometry.Point{X: 1, Y: 0}].Rune)
+		assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 2, Y: 0}].Rune)
+	})
+
+	tests := []struct {
+		name        string
+		clip        Clip
+		wantOutside bool
+	}{
+		{"hard edge clips overflow", ClipHardEdge, false},
+		{"none lets children overflow", ClipNone, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStack(
+				NewText("abc"),
+				NewPositioned(NewText("xyz")).WithLeft(2),
+			).WithClipBehavior(tt.clip)
+			r := layoutStack(s, geometry.Size{Width: 10, Height: 5})
+			assert.Equal(t, geometry.Size{Width: 3, Height: 1}, r.Size())
+
+			ctx := NewMockRenderContext()
+			r.Paint(ctx)
+
+			assert.Equal(t, 'x', ctx.cells[geometry.Point{X: 2, Y: 0}].Rune)
+			_, outside := ctx.cells[geometry.Point{X: 3, Y: 0}]
+			assert.Equal(t, tt.wantOutside, outside)
+			assert.Empty(t, ctx.clips)
+		})
+	}
+}
@@ -13,13 +13,26 @@ func (s *BaseState) Widget() StatefulWidget   { return s.widget }
 func (s *BaseState) Element() StatefulElement { return s.element }
 func (s *BaseState) Context() BuildContext    { return s.context }
 
+// widgetUpdater is implemented by states that track the widget of their
+// element when it is updated
+type widgetUpdater interface {
+	updateWidget(StatefulWidget)
+}
+
+func (s *BaseState) updateWidget(widget StatefulWidget) {
+	s.widget = widget
+}
+
+// MountState attaches the state to its element, which calls InitState next
 func (s *BaseState) MountState(element StatefulElement) {
 	s.element = element
 	s.widget = element.Widget().(StatefulWidget)
 	s.context = element.BuildContext()
-	s.InitState()
 }
 
+// SetState runs fn and schedules the element for rebuilding. Like every
+// other state access it must happen on the build goroutine; background
+// goroutines should wrap the call in Context().Post.
 func (s *BaseState) SetState(fn func()) {
 	if fn != nil {
 		fn()
@@ -17,23 +17,25 @@ type WidgetStyle struct {
 	MinSize geometry.Size
 	MaxSize geometry.Size
 
-	// Border properties
+	// Border properties. A side is drawn when its border width is
+	// positive; any width beyond one cell is left blank.
 	BorderStyle BorderStyle
 	BorderColor color.Color
 	BorderWidth EdgeInsets
+	BorderTitle string
 }
 
 // BorderStyle represents different border types
-type BorderStyle int
+type BorderStyle = style.BorderStyle
 
 const (
-	BorderNone BorderStyle = iota
-	BorderSingle
-	BorderDouble
-	BorderRounded
-	BorderHeavy
-	BorderDashed
-	BorderDotted
+	BorderNone    = style.BorderNone
+	BorderSingle  = style.BorderSingle
+	BorderDouble  = style.BorderDouble
+	BorderRounded = style.BorderRounded
+	BorderHeavy   = style.BorderHeavy
+	BorderDashed  = style.BorderDashed
+	BorderDotted  = style.BorderDotted
 )
 
 // NewWidgetStyle creates a new style with default values
@@ -67,6 +69,12 @@ func (s WidgetStyle) WithBorderColor(c color.Color) WidgetStyle {
 	return s
 }
 
+// WithBorderTitle sets the title drawn over the top border
+func (s WidgetStyle) WithBorderTitle(title string) WidgetStyle {
+	s.BorderTitle = title
+	return s
+}
+
 func (s WidgetStyle) WithForeground(c color.Color) WidgetStyle {
 	s.ForegroundColor = c
 	return s
@@ -118,19 +126,8 @@ func (s WidgetStyle) WithBorder(style BorderStyle, color color.Color, width Edge
 func (s WidgetStyle) Merge(other WidgetStyle) WidgetStyle {
 	result := s
 
-	// Only override colors if they're not transparent
-	if other.ForegroundColor.A > 0 {
-		result.ForegroundColor = other.ForegroundColor
-	}
-	if other.BackgroundColor.A > 0 {
-		result.BackgroundColor = other.BackgroundColor
-	}
-
-	// Text properties
-	result.Bold = result.Bold || other.Bold
-	result.Italic = result.Italic || other.Italic
-	result.Underline = result.Underline || other.Underline
-	result.StrikeThrough = result.StrikeThrough || other.StrikeThrough
+	// Colors and text properties
+	result.Style = s.Style.Merge(other.Style)
 
 	// Layout properties (other takes precedence)
 	result.Padding = other.Padding
@@ -141,11 +138,33 @@ func (s WidgetStyle) Merge(other WidgetStyle) WidgetStyle {
 		result.BorderStyle = other.BorderStyle
 		result.BorderColor = other.BorderColor
 		result.BorderWidth = other.BorderWidth
+		result.BorderTitle = other.BorderTitle
 	}
 
 	return result
 }
 
+// Border returns the border to draw for the style. Its cells use the
+// border color, falling back to the foreground color, on the background.
+func (s WidgetStyle) Border() style.Border {
+	cellStyle := style.Style{
+		ForegroundColor: s.ForegroundColor,
+		BackgroundColor: s.BackgroundColor,
+	}
+	if s.BorderColor.A > 0 {
+		cellStyle.ForegroundColor = s.BorderColor
+	}
+	return style.Border{
+		Style:  cellStyle,
+		Line:   s.BorderStyle,
+		Top:    s.BorderWidth.Top > 0,
+		Right:  s.BorderWidth.Right > 0,
+		Bottom: s.BorderWidth.Bottom > 0,
+		Left:   s.BorderWidth.Left > 0,
+		Title:  s.BorderTitle,
+	}
+}
+
 // AdaptStyle adapts the style for specific backend capabilities
 func (s WidgetStyle) AdaptStyle(caps capabilities.Capabilities) WidgetStyle {
 	adapted := s
@@ -6,16 +6,22 @@
 package widget
 
 import (
+	"math"
+	"sort"
 	"strings"
 
+	"github.com/watzon/tide/pkg/core/color"
 	"github.com/watzon/tide/pkg/core/geometry"
 	"github.com/watzon/tide/pkg/engine"
 )
 
-// Text is a widget that displays text
+// Text is a widget that displays text. By default it wraps between words
+// to fit the width it is given, left aligned, and clips what still doesn't
+// fit. Widths are measured in terminal cells, so wide characters take two.
 type Text struct {
 	BaseWidget // This gives us GetConstraints, GetStyle, etc.
 	content    string
+	options    textOptions
 }
 
 func NewText(content string) *Text {
@@ -24,6 +30,7 @@ func NewText(content string) *Text {
 		BaseWidget: BaseWidget{
 			style: NewWidgetStyle(), // Initialize with default style
 		},
+		options: defaultTextOptions(),
 	}
 }
 
@@ -35,6 +42,15 @@ func (t *Text) Build(context BuildContext) Widget {
 type TextRenderObject struct {
 	BaseRenderObject
 	content string
+	options textOptions
+
+	// rows are the lines laid out, up to the maximum number of lines
+	rows []textRow
+	// truncated is set when lines were left out for the maximum
+	truncated bool
+	// runs style the content piece by piece for RichText, and are empty
+	// when the style covers all of it
+	runs []textRun
 }
 
 func NewTextRenderObject(style WidgetStyle, content string) *TextRenderObject {
@@ -43,59 +59,177 @@ func NewTextRenderObject(style WidgetStyle, content string) *TextRenderObject {
 			style: style,
 		},
 		content: content,
+		options: defaultTextOptions(),
 	}
 }
 
+// newLineRenderObject creates a text render object that doesn't wrap, for
+// views that paint a line of text at a time through one
+func newLineRenderObject() *TextRenderObject {
+	r := NewTextRenderObject(NewWidgetStyle(), "")
+	r.options.softWrap = false
+	return r
+}
+
 func (r *TextRenderObject) Paint(context engine.RenderContext) {
 	// Paint background using BaseRenderObject's functionality
 	r.BaseRenderObject.Paint(context)
 
-	// Split content into lines
-	lines := strings.Split(r.content, "\n")
+	rows := r.rows[:min(len(r.rows), r.size.Height)]
+	truncated := r.truncated || len(rows) < len(r.rows)
+	unicode := context.Capabilities().SupportsUnicode
+
+	context.PushClipRect(geometry.NewRect(0, 0, r.size.Width, r.size.Height))
+	for y, row := range rows {
+		overflows := row.width > r.size.Width || truncated && y == len(rows)-1
+		r.paintRow(context, y, row, overflows, unicode)
+	}
+	context.PopClipRect()
+}
+
+// paintRow draws a laid out line at y, aligned across the width and cut
+// off as the overflow asks when it doesn't fit
+func (r *TextRenderObject) paintRow(context engine.RenderContext, y int, row textRow, overflows, unicode bool) {
+	text, width := row.text, row.width
+	if overflows && r.options.overflow == OverflowEllipsis {
+		text = withEllipsis(text, r.size.Width, unicode)
+		width = cellWidth(text)
+	}
 
-	// Paint each line
-	for y, line := range lines {
-		if y >= r.size.Height {
-			break // Don't exceed height
+	x, extra := 0, 0
+	switch r.options.align {
+	case TextAlignCenter:
+		x = max(0, (r.size.Width-width)/2)
+	case TextAlignRight:
+		x = max(0, r.size.Width-width)
+	case TextAlignJustify:
+		if !row.last && !overflows {
+			extra = r.size.Width - width
 		}
-		for x, ch := range line {
-			if x >= r.size.Width {
-				break // Don't exceed width
-			}
-			context.DrawCell(x, y, ch, r.style.ForegroundColor, r.style.BackgroundColor)
+	}
+
+	// The last cells shown fade when the overflow asks for it
+	fadeStart := math.MaxInt32
+	if overflows && r.options.overflow == OverflowFade {
+		fadeStart = min(r.size.Width, x+width+extra) - fadeCells
+	}
+	for _, cluster := range placeClusters(text, x, extra) {
+		s := r.styleAt(row.start + cluster.at)
+		if cluster.x >= fadeStart {
+			s = fadedStyle(s, float64(cluster.x-fadeStart+1)/float64(fadeCells+1))
 		}
+		drawCluster(context, cluster.x, y, cluster.runes, s)
 	}
 }
 
-func (r *TextRenderObject) Layout(constraints Constraints) geometry.Size {
-	lines := strings.Split(r.content, "\n")
+// styleAt returns the style of the character at a byte offset into the
+// content. An ellipsis past the end of the content takes the last style.
+func (r *TextRenderObject) styleAt(offset int) WidgetStyle {
+	if len(r.runs) == 0 {
+		return r.style
+	}
+	i := sort.Search(len(r.runs), func(i int) bool { return r.runs[i].end > offset })
+	s := r.style
+	s.Style = r.runs[min(i, len(r.runs)-1)].style
+	return s
+}
+
+// placedCluster is a character of a line and the column it is drawn at
+type placedCluster struct {
+	x     int
+	runes []rune
+	// at is the byte offset of the character in the line
+	at int
+}
+
+// placeClusters lays the characters of text out from x, spreading extra
+// cells over the spaces between its words to justify it
+func placeClusters(text string, x, extra int) []placedCluster {
+	runes := []rune(text)
+	trimmed := []rune(strings.TrimLeft(text, " "))
+	gaps := strings.Count(string(trimmed), " ")
+	indent := len(runes) - len(trimmed)
 
-	// Calculate required size
-	width := 0
-	for _, line := range lines {
-		if len(line) > width {
-			width = len(line)
+	var placed []placedCluster
+	gap, at := 0, 0
+	for i := 0; i < len(runes); {
+		next := clusterEnd(runes, i)
+		placed = append(placed, placedCluster{x: x, runes: runes[i:next], at: at})
+		x += clusterWidth(runes[i:next])
+		at += len(string(runes[i:next]))
+		if runes[i] == ' ' && i >= indent && gaps > 0 {
+			x += extra / gaps
+			if gap < extra%gaps {
+				x++
+			}
+			gap++
 		}
+		i = next
 	}
-	height := len(lines)
+	return placed
+}
 
-	// Apply constraints
-	r.size = constraints.Constrain(geometry.Size{
-		Width:  width,
-		Height: height,
-	})
+// fadedStyle blends the foreground of style amount of the way into its
+// background, or into black when the background is transparent
+func fadedStyle(style WidgetStyle, amount float64) WidgetStyle {
+	background := style.BackgroundColor
+	if background.A == 0 {
+		background = color.Black
+	}
+	return style.WithForeground(style.ForegroundColor.Blend(background, amount))
+}
+
+func (r *TextRenderObject) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	r.rows, r.truncated = r.layoutRows(constraints.MaxSize.Width)
 
+	// Apply constraints
+	r.size = constraints.Constrain(rowsSize(r.rows))
 	return r.size
 }
 
+// layoutRows lays the text out within width, keeping the rows up to the
+// maximum number of lines, and reports whether any were left out
+func (r *TextRenderObject) layoutRows(width int) ([]textRow, bool) {
+	rows := r.options.layout(r.content, width)
+	if r.options.maxLines > 0 && len(rows) > r.options.maxLines {
+		return rows[:r.options.maxLines], true
+	}
+	return rows, false
+}
+
+// Wrapping text can be as narrow as its widest word and as wide as its
+// widest line, and takes as many lines as it wraps into at a width
+
+func (r *TextRenderObject) MinIntrinsicWidth(height int) int {
+	return r.options.minWidth(r.content)
+}
+
+func (r *TextRenderObject) MaxIntrinsicWidth(height int) int {
+	return rowsSize(r.options.layout(r.content, math.MaxInt32)).Width
+}
+
+func (r *TextRenderObject) MinIntrinsicHeight(width int) int {
+	rows, _ := r.layoutRows(width)
+	return len(rows)
+}
+
+func (r *TextRenderObject) MaxIntrinsicHeight(width int) int {
+	return r.MinIntrinsicHeight(width)
+}
+
 func (t *Text) CreateRenderObject() RenderObject {
-	return NewTextRenderObject(t.GetStyle(), t.content)
+	r := NewTextRenderObject(t.GetStyle(), t.content)
+	r.options = t.options
+	return r
 }
 
 func (t *Text) UpdateRenderObject(renderObject RenderObject) {
 	if textRenderObj, ok := renderObject.(*TextRenderObject); ok {
 		textRenderObj.style = t.GetStyle()
 		textRenderObj.content = t.content
+		textRenderObj.options = t.options
+		textRenderObj.runs = nil
 	}
 }
 
@@ -107,3 +241,39 @@ func (t *Text) WithContent(content string) *Text {
 	t.content = content
 	return t
 }
+
+func (t *Text) WithStyle(style WidgetStyle) *Text {
+	t.style = style
+	return t
+}
+
+// WithSoftWrap sets whether lines wrap to fit the width. Without wrapping
+// lines only break at newlines.
+func (t *Text) WithSoftWrap(softWrap bool) *Text {
+	t.options.softWrap = softWrap
+	return t
+}
+
+// WithLineBreak sets where wrapping may break lines
+func (t *Text) WithLineBreak(lineBreak LineBreak) *Text {
+	t.options.lineBreak = lineBreak
+	return t
+}
+
+// WithTextAlign sets where lines sit across the width of the text
+func (t *Text) WithTextAlign(align TextAlign) *Text {
+	t.options.align = align
+	return t
+}
+
+// WithMaxLines limits the lines shown, or removes the limit when zero
+func (t *Text) WithMaxLines(maxLines int) *Text {
+	t.options.maxLines = max(0, maxLines)
+	return t
+}
+
+// WithOverflow sets how text that doesn't fit is cut off
+func (t *Text) WithOverflow(overflow TextOverflow) *Text {
+	t.options.overflow = overflow
+	return t
+}
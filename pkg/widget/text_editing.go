@@ -0,0 +1,363 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/watzon/tide/pkg/core/grapheme"
+)
+
+// maxUndoSteps is how many edits a controller can undo
+const maxUndoSteps = 100
+
+// editKind is the kind of the last edit, which decides whether the next
+// edit of the same kind joins its undo step
+type editKind int
+
+const (
+	editNone editKind = iota
+	editInsert
+	editDelete
+)
+
+// textSnapshot is the text and selection an edit can be undone to
+type textSnapshot struct {
+	text           []rune
+	anchor, cursor int
+}
+
+// TextEditingController holds the text of an editable text widget and its
+// selection, and keeps the history undo and redo walk through. Positions
+// are rune offsets into the text and always fall between characters, so a
+// base character and the combining marks after it move and delete as one.
+// Like the rest of the tree, a controller must only be used from the build
+// goroutine.
+type TextEditingController struct {
+	text []rune
+	// The selection runs from anchor to cursor; they are equal when
+	// nothing is selected
+	anchor, cursor int
+	// boundaries holds the offset where each character of the text starts,
+	// then the length of the text. It is segmented when first needed after
+	// the text changes.
+	boundaries []int
+
+	undo, redo []textSnapshot
+	// last is the kind of the last edit, reset whenever the cursor moves
+	// so typing after moving starts a new undo step
+	last      editKind
+	listeners []*textListener
+}
+
+// textListener wraps a listener so it can be found again for removal
+type textListener struct {
+	fn func()
+}
+
+// NewTextEditingController creates a controller holding text with the
+// cursor at its end
+func NewTextEditingController(text string) *TextEditingController {
+	c := &TextEditingController{text: []rune(text)}
+	c.anchor, c.cursor = len(c.text), len(c.text)
+	return c
+}
+
+// Text returns the text being edited
+func (c *TextEditingController) Text() string {
+	return string(c.text)
+}
+
+// SetText replaces the text, moving the cursor to its end. It can be
+// undone like any edit.
+func (c *TextEditingController) SetText(text string) {
+	c.anchor, c.cursor = 0, len(c.text)
+	c.replace(text, editNone)
+}
+
+// Cursor returns the offset of the cursor
+func (c *TextEditingController) Cursor() int {
+	return c.cursor
+}
+
+// Selection returns the start and end offsets of the selection, which are
+// equal when nothing is selected
+func (c *TextEditingController) Selection() (start, end int) {
+	return min(c.anchor, c.cursor), max(c.anchor, c.cursor)
+}
+
+// SetSelection selects from anchor to cursor, clamped to the text and
+// moved to the nearest character boundary. Equal offsets place the
+// cursor without selecting.
+func (c *TextEditingController) SetSelection(anchor, cursor int) {
+	anchor, cursor = c.boundary(anchor), c.boundary(cursor)
+	c.last = editNone
+	if anchor == c.anchor && cursor == c.cursor {
+		return
+	}
+	c.anchor, c.cursor = anchor, cursor
+	c.notify()
+}
+
+// SelectAll selects the whole text
+func (c *TextEditingController) SelectAll() {
+	c.SetSelection(0, len(c.text))
+}
+
+// SelectedText returns the selected text
+func (c *TextEditingController) SelectedText() string {
+	start, end := c.Selection()
+	return string(c.text[start:end])
+}
+
+// Insert replaces the selection with text and places the cursor after it
+func (c *TextEditingController) Insert(text string) {
+	if text == "" && c.anchor == c.cursor {
+		return
+	}
+	c.replace(text, editInsert)
+}
+
+// paste inserts text as an undo step of its own
+func (c *TextEditingController) paste(text string) {
+	if text != "" || c.anchor != c.cursor {
+		c.replace(text, editNone)
+	}
+}
+
+// DeleteBackward deletes the selection, or the character before the cursor
+func (c *TextEditingController) DeleteBackward() {
+	c.deleteTo(c.prevCluster(c.cursor))
+}
+
+// DeleteForward deletes the selection, or the character after the cursor
+func (c *TextEditingController) DeleteForward() {
+	c.deleteTo(c.nextCluster(c.cursor))
+}
+
+// DeleteWordBackward deletes the selection, or back to the start of the
+// word before the cursor
+func (c *TextEditingController) DeleteWordBackward() {
+	c.deleteTo(c.prevWord(c.cursor))
+}
+
+// DeleteWordForward deletes the selection, or up to the end of the word
+// after the cursor
+func (c *TextEditingController) DeleteWordForward() {
+	c.deleteTo(c.nextWord(c.cursor))
+}
+
+// deleteTo deletes the selection, or from the cursor to offset when
+// nothing is selected
+func (c *TextEditingController) deleteTo(offset int) {
+	if c.anchor == c.cursor {
+		if offset == c.cursor {
+			return
+		}
+		c.anchor = offset
+	}
+	c.replace("", editDelete)
+}
+
+// replace swaps the selection for text as one undoable edit of kind. An
+// edit continuing a run of the same kind joins the run's undo step.
+// Selecting resets the run, so replacing a selection always starts a step.
+func (c *TextEditingController) replace(text string, kind editKind) {
+	if kind == editNone || kind != c.last {
+		c.pushUndo(c.snapshot())
+	}
+	c.redo = nil
+
+	start, end := c.Selection()
+	inserted := []rune(text)
+	c.text = append(append(append([]rune(nil), c.text[:start]...), inserted...), c.text[end:]...)
+	c.boundaries = nil
+	c.anchor = start + len(inserted)
+	c.cursor = c.anchor
+	c.last = kind
+	c.notify()
+}
+
+// Undo reverts the last edit and reports whether there was one
+func (c *TextEditingController) Undo() bool {
+	return c.step(&c.undo, &c.redo)
+}
+
+// Redo repeats the last undone edit and reports whether there was one
+func (c *TextEditingController) Redo() bool {
+	return c.step(&c.redo, &c.undo)
+}
+
+// step restores the snapshot on top of from, saving the current state on
+// to
+func (c *TextEditingController) step(from, to *[]textSnapshot) bool {
+	if len(*from) == 0 {
+		return false
+	}
+	snapshot := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, c.snapshot())
+
+	c.text, c.anchor, c.cursor = snapshot.text, snapshot.anchor, snapshot.cursor
+	c.boundaries = nil
+	c.last = editNone
+	c.notify()
+	return true
+}
+
+func (c *TextEditingController) snapshot() textSnapshot {
+	return textSnapshot{text: c.text, anchor: c.anchor, cursor: c.cursor}
+}
+
+func (c *TextEditingController) pushUndo(snapshot textSnapshot) {
+	c.undo = append(c.undo, snapshot)
+	if len(c.undo) > maxUndoSteps {
+		c.undo = c.undo[len(c.undo)-maxUndoSteps:]
+	}
+}
+
+// AddListener registers fn to be called whenever the text or selection
+// changes and returns a function that removes it again
+func (c *TextEditingController) AddListener(fn func()) (remove func()) {
+	listener := &textListener{fn: fn}
+	c.listeners = append(c.listeners, listener)
+	return func() {
+		for i, l := range c.listeners {
+			if l == listener {
+				c.listeners = append(c.listeners[:i:i], c.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (c *TextEditingController) notify() {
+	for _, listener := range append([]*textListener(nil), c.listeners...) {
+		listener.fn()
+	}
+}
+
+// moveTo moves the cursor to offset, keeping the anchor where it is when
+// extending the selection
+func (c *TextEditingController) moveTo(offset int, extend bool) {
+	anchor := c.anchor
+	if !extend {
+		anchor = offset
+	}
+	c.SetSelection(anchor, offset)
+}
+
+// boundary clamps offset to the text and moves it back to the start of
+// the character it falls inside
+func (c *TextEditingController) boundary(offset int) int {
+	offset = max(0, min(offset, len(c.text)))
+	boundaries := c.clusterBoundaries()
+	i := sort.SearchInts(boundaries, offset)
+	if boundaries[i] == offset {
+		return offset
+	}
+	return boundaries[i-1]
+}
+
+// nextCluster returns the offset after the character at offset
+func (c *TextEditingController) nextCluster(offset int) int {
+	if offset >= len(c.text) {
+		return len(c.text)
+	}
+	boundaries := c.clusterBoundaries()
+	return boundaries[sort.SearchInts(boundaries, offset+1)]
+}
+
+// prevCluster returns the offset of the character before offset
+func (c *TextEditingController) prevCluster(offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	boundaries := c.clusterBoundaries()
+	return boundaries[sort.SearchInts(boundaries, min(offset, len(c.text)))-1]
+}
+
+// clusterBoundaries returns where each character of the text starts,
+// followed by the length of the text, segmenting the text if it changed
+func (c *TextEditingController) clusterBoundaries() []int {
+	if c.boundaries == nil {
+		c.boundaries = make([]int, 0, len(c.text)+1)
+		for offset := 0; offset < len(c.text); offset = clusterEnd(c.text, offset) {
+			c.boundaries = append(c.boundaries, offset)
+		}
+		c.boundaries = append(c.boundaries, len(c.text))
+	}
+	return c.boundaries
+}
+
+// nextWord returns the offset at the end of the word after offset
+func (c *TextEditingController) nextWord(offset int) int {
+	for offset < len(c.text) && !isWordRune(c.text[offset]) {
+		offset++
+	}
+	for offset < len(c.text) && isWordRune(c.text[offset]) {
+		offset++
+	}
+	return c.boundary(offset)
+}
+
+// prevWord returns the offset at the start of the word before offset
+func (c *TextEditingController) prevWord(offset int) int {
+	for offset > 0 && !isWordRune(c.text[offset-1]) {
+		offset--
+	}
+	for offset > 0 && isWordRune(c.text[offset-1]) {
+		offset--
+	}
+	return c.boundary(offset)
+}
+
+// lineStart returns the offset where the line holding offset starts
+func (c *TextEditingController) lineStart(offset int) int {
+	for offset > 0 && c.text[offset-1] != '\n' {
+		offset--
+	}
+	return offset
+}
+
+// lineEnd returns the offset where the line holding offset ends, before
+// its newline
+func (c *TextEditingController) lineEnd(offset int) int {
+	for offset < len(c.text) && c.text[offset] != '\n' {
+		offset++
+	}
+	return offset
+}
+
+// isWordRune reports whether r is part of a word for word-wise movement
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.M, r)
+}
+
+// clusterEnd returns the offset after the character starting at offset
+func clusterEnd(text []rune, offset int) int {
+	if offset >= len(text) {
+		return len(text)
+	}
+	length, _ := grapheme.Next(text[offset:])
+	return offset + length
+}
+
+// clusterWidth returns the cells taken by a character, at least one so
+// control characters still show as a blank
+func clusterWidth(cluster []rune) int {
+	_, width := grapheme.Next(cluster)
+	return width
+}
+
+// singleLine flattens line breaks into spaces, for pasting into a single
+// line input
+func singleLine(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", " ")
+	return strings.NewReplacer("\n", " ", "\r", " ").Replace(text)
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextEditingController_Edits(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		edit   func(c *TextEditingController)
+		want   string
+		cursor int
+	}{
+		{"insert at the end", "ab", func(c *TextEditingController) { c.Insert("c") }, "abc", 3},
+		{"insert replaces the selection", "abcd", func(c *TextEditingController) {
+			c.SetSelection(1, 3)
+			c.Insert("X")
+		}, "aXd", 2},
+		{"delete backward", "abc", func(c *TextEditingController) { c.DeleteBackward() }, "ab", 2},
+		{"delete backward at the start", "abc", func(c *TextEditingController) {
+			c.SetSelection(0, 0)
+			c.DeleteBackward()
+		}, "abc", 0},
+		{"delete forward", "abc", func(c *TextEditingController) {
+			c.SetSelection(1, 1)
+			c.DeleteForward()
+		}, "ac", 1},
+		{"delete the selection", "abcd", func(c *TextEditingController) {
+			c.SetSelection(3, 1)
+			c.DeleteForward()
+		}, "ad", 1},
+		{"delete word backward", "foo bar  ", func(c *TextEditingController) { c.DeleteWordBackward() }, "foo ", 4},
+		{"delete word forward", "foo.bar baz", func(c *TextEditingController) {
+			c.SetSelection(3, 3)
+			c.DeleteWordForward()
+		}, "foo baz", 3},
+		{"combining marks delete with their base", "café", func(c *TextEditingController) { c.DeleteBackward() }, "caf", 3},
+		{"joined emoji delete as one", "a👩‍💻", func(c *TextEditingController) { c.DeleteBackward() }, "a", 1},
+		{"skin tones delete with their emoji", "a👍🏽", func(c *TextEditingController) { c.DeleteBackward() }, "a", 1},
+		{"flags delete backward as one", "🇯🇵🇫🇷", func(c *TextEditingController) { c.DeleteBackward() }, "🇯🇵", 2},
+		{"flags delete forward as one", "🇯🇵🇫🇷", func(c *TextEditingController) {
+			c.SetSelection(0, 0)
+			c.DeleteForward()
+		}, "🇫🇷", 0},
+		{"set text", "old", func(c *TextEditingController) { c.SetText("new text") }, "new text", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewTextEditingController(tt.text)
+			tt.edit(c)
+			assert.Equal(t, tt.want, c.Text())
+			assert.Equal(t, tt.cursor, c.Cursor())
+		})
+	}
+}
+
+func TestTextEditingController_Selection(t *testing.T) {
+	c := NewTextEditingController("aéz")
+
+	c.SetSelection(2, 2)
+	assert.Equal(t, 1, c.Cursor(), "offsets inside a character move to its start")
+
+	c.SetSelection(9, 0)
+	start, end := c.Selection()
+	assert.Equal(t, []int{0, 4}, []int{start, end})
+	assert.Equal(t, "aéz", c.SelectedText())
+
+	c.SelectAll()
+	assert.Equal(t, 4, c.Cursor())
+}
+
+func TestTextEditingController_Clusters(t *testing.T) {
+	// e and a combining accent, a flag of two regional indicators, and a
+	// CRLF line break are one character each
+	c := NewTextEditingController("ae\u0301\U0001F1EF\U0001F1F5\r\nz")
+	assert.Equal(t, []int{0, 1, 3, 5, 7, 8}, c.clusterBoundaries())
+
+	tests := []struct {
+		from, next, prev int
+	}{
+		{0, 1, 0},
+		{1, 3, 0},
+		{2, 3, 1},
+		{3, 5, 1},
+		{5, 7, 3},
+		{7, 8, 5},
+		{8, 8, 7},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.next, c.nextCluster(tt.from), "next character from %d", tt.from)
+		assert.Equal(t, tt.prev, c.prevCluster(tt.from), "previous character from %d", tt.from)
+	}
+
+	t.Run("segmented once per change", func(t *testing.T) {
+		boundaries := c.clusterBoundaries()
+		c.SetSelection(3, 3)
+		c.prevCluster(5)
+		assert.Same(t, &boundaries[0], &c.clusterBoundaries()[0])
+
+		c.Insert("\u0301")
+		assert.Equal(t, "ae\u0301\u0301\U0001F1EF\U0001F1F5\r\nz", c.Text())
+		assert.Equal(t, []int{0, 1, 4, 6, 8, 9}, c.clusterBoundaries())
+
+		c.Undo()
+		assert.Equal(t, []int{0, 1, 3, 5, 7, 8}, c.clusterBoundaries())
+	})
+}
+
+func TestTextEditingController_Words(t *testing.T) {
+	c := NewTextEditingController("one, two_2 three")
+	tests := []struct {
+		from, next, prev int
+	}{
+		{0, 3, 0},
+		{3, 10, 0},
+		{5, 10, 0},
+		{11, 16, 5},
+		{16, 16, 11},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.next, c.nextWord(tt.from), "next word from %d", tt.from)
+		assert.Equal(t, tt.prev, c.prevWord(tt.from), "previous word from %d", tt.from)
+	}
+}
+
+func TestTextEditingController_UndoRedo(t *testing.T) {
+	c := NewTextEditingController("")
+	for _, ch := range "hello" {
+		c.Insert(string(ch))
+	}
+	c.moveTo(0, false)
+	c.Insert(">")
+	c.DeleteBackward()
+	c.DeleteBackward()
+
+	assert.Equal(t, "hello", c.Text())
+
+	steps := []string{">hello", "hello", ""}
+	for _, want := range steps {
+		assert.True(t, c.Undo())
+		assert.Equal(t, want, c.Text())
+	}
+	assert.False(t, c.Undo())
+
+	assert.True(t, c.Redo())
+	assert.Equal(t, "hello", c.Text(), "typed characters redo as one step")
+
+	c.Insert("!")
+	assert.False(t, c.Redo(), "editing drops what was undone")
+}
+
+func TestTextEditingController_Listeners(t *testing.T) {
+	c := NewTextEditingController("ab")
+	calls := 0
+	remove := c.AddListener(func() { calls++ })
+
+	c.Insert("c")
+	c.SetSelection(0, 0)
+	c.SetSelection(0, 0)
+	assert.Equal(t, 2, calls, "only changes are reported")
+
+	remove()
+	c.Insert("x")
+	assert.Equal(t, 2, calls)
+}
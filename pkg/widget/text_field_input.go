@@ -0,0 +1,253 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// HandleEvent edits the text from keys while the field has focus, and
+// focuses it and places the cursor on a click
+func (r *RenderTextField) HandleEvent(event terminal.Event) bool {
+	if r.controller == nil {
+		return false
+	}
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+		handled := r.handleKey(ev)
+		if handled {
+			r.scrollToCursor()
+		}
+		return handled
+	case terminal.MouseEvent:
+		return r.handleMouse(ev)
+	}
+	return false
+}
+
+func (r *RenderTextField) handleKey(ev terminal.KeyEvent) bool {
+	if r.handleMove(ev) {
+		return true
+	}
+	r.goalX = -1
+	if r.handleShortcut(ev.Key) {
+		return true
+	}
+
+	before := r.controller.Text()
+	if !r.handleEdit(ev) {
+		return false
+	}
+	if text := r.controller.Text(); text != before && r.config.onChanged != nil {
+		r.config.onChanged(text)
+	}
+	return true
+}
+
+// handleMove moves the cursor, extending the selection when Shift is held
+func (r *RenderTextField) handleMove(ev terminal.KeyEvent) bool {
+	c := r.controller
+	extend := ev.Modifiers&tcell.ModShift != 0
+	word := ev.Modifiers&(tcell.ModCtrl|tcell.ModAlt) != 0
+	start, end := c.Selection()
+
+	var target int
+	switch ev.Key {
+	case tcell.KeyLeft:
+		switch {
+		case word:
+			target = c.prevWord(c.cursor)
+		case start != end && !extend:
+			// Left collapses a selection to its start
+			target = start
+		default:
+			target = c.prevCluster(c.cursor)
+		}
+	case tcell.KeyRight:
+		switch {
+		case word:
+			target = c.nextWord(c.cursor)
+		case start != end && !extend:
+			target = end
+		default:
+			target = c.nextCluster(c.cursor)
+		}
+	case tcell.KeyHome:
+		target = c.lineStart(c.cursor)
+		if ev.Modifiers&tcell.ModCtrl != 0 {
+			target = 0
+		}
+	case tcell.KeyEnd:
+		target = c.lineEnd(c.cursor)
+		if ev.Modifiers&tcell.ModCtrl != 0 {
+			target = len(c.text)
+		}
+	case tcell.KeyUp, tcell.KeyDown:
+		return r.config.multiline && r.moveLine(ev.Key, extend)
+	default:
+		return false
+	}
+
+	r.goalX = -1
+	c.moveTo(target, extend)
+	return true
+}
+
+// moveLine moves the cursor a line up or down, keeping to the column it
+// was at before moving between lines
+func (r *RenderTextField) moveLine(key tcell.Key, extend bool) bool {
+	cursor := r.position(r.controller.cursor)
+	if r.goalX < 0 {
+		r.goalX = cursor.X
+	}
+	line := cursor.Y + 1
+	if key == tcell.KeyUp {
+		line = cursor.Y - 1
+	}
+
+	switch {
+	case line < 0:
+		r.controller.moveTo(0, extend)
+	case line >= len(r.lines()):
+		r.controller.moveTo(len(r.controller.text), extend)
+	default:
+		r.controller.moveTo(r.offsetAt(r.goalX, line), extend)
+	}
+	return true
+}
+
+// handleShortcut runs the selection, clipboard and history shortcuts
+func (r *RenderTextField) handleShortcut(key tcell.Key) bool {
+	c := r.controller
+	switch key {
+	case tcell.KeyCtrlA:
+		c.SelectAll()
+	case tcell.KeyCtrlC:
+		// With nothing to copy, Ctrl+C is left for the app
+		return r.copySelection()
+	case tcell.KeyCtrlZ:
+		c.Undo()
+	case tcell.KeyCtrlY:
+		c.Redo()
+	default:
+		return false
+	}
+	return true
+}
+
+// handleEdit changes the text for typed characters and editing keys
+func (r *RenderTextField) handleEdit(ev terminal.KeyEvent) bool {
+	c := r.controller
+	word := ev.Modifiers&(tcell.ModCtrl|tcell.ModAlt) != 0
+	switch ev.Key {
+	case tcell.KeyRune:
+		if ev.Modifiers&tcell.ModAlt != 0 {
+			return false
+		}
+		c.Insert(string(ev.Rune))
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if word {
+			c.DeleteWordBackward()
+		} else {
+			c.DeleteBackward()
+		}
+	case tcell.KeyCtrlW:
+		c.DeleteWordBackward()
+	case tcell.KeyDelete:
+		if word {
+			c.DeleteWordForward()
+		} else {
+			c.DeleteForward()
+		}
+	case tcell.KeyCtrlX:
+		if !r.copySelection() {
+			return false
+		}
+		c.paste("")
+	case tcell.KeyCtrlV:
+		r.pasteClipboard()
+	case tcell.KeyEnter:
+		return r.enter()
+	default:
+		return false
+	}
+	return true
+}
+
+// enter starts a new line in a text area and submits an input
+func (r *RenderTextField) enter() bool {
+	if r.config.multiline {
+		r.controller.Insert("\n")
+		return true
+	}
+	if r.config.onSubmitted != nil {
+		r.config.onSubmitted(r.controller.Text())
+	}
+	return true
+}
+
+// copySelection puts the selected text on the clipboard and reports
+// whether there was any. Masked text is never copied.
+func (r *RenderTextField) copySelection() bool {
+	text := r.controller.SelectedText()
+	if text == "" || r.config.mask != 0 || r.clipboard == nil {
+		return false
+	}
+	return r.clipboard.SetClipboard(text) == nil
+}
+
+// pasteClipboard replaces the selection with the clipboard text
+func (r *RenderTextField) pasteClipboard() {
+	if r.clipboard == nil {
+		return
+	}
+	text, err := r.clipboard.GetClipboard()
+	if err != nil {
+		return
+	}
+	if !r.config.multiline {
+		text = singleLine(text)
+	}
+	r.controller.paste(text)
+}
+
+// handleMouse focuses the field and places the cursor on a click, and
+// selects while the pointer is dragged
+func (r *RenderTextField) handleMouse(ev terminal.MouseEvent) bool {
+	if ev.Buttons&tcell.ButtonPrimary == 0 {
+		r.dragging = false
+		return false
+	}
+	if ev.Motion && !r.dragging {
+		return false
+	}
+
+	offset := r.offsetAtPoint(ev.Position)
+	if ev.Motion {
+		r.controller.moveTo(offset, true)
+	} else {
+		r.dragging = true
+		if r.focus != nil {
+			r.focus.RequestFocus()
+		}
+		r.controller.moveTo(offset, false)
+	}
+	r.goalX = -1
+	r.scrollToCursor()
+	return true
+}
+
+// offsetAtPoint returns the offset of the character at p, relative to the
+// field
+func (r *RenderTextField) offsetAtPoint(p geometry.Point) int {
+	p = p.Add(r.scroll)
+	return r.offsetAt(p.X-r.labelWidth(), p.Y)
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// RenderTextField lays out and paints editable text, keeping the cursor in
+// view, and edits it from key and mouse input
+type RenderTextField struct {
+	BaseRenderObject
+	config     textFieldConfig
+	controller *TextEditingController
+	focus      *FocusNode
+	clipboard  Clipboard
+
+	// scroll is how far the text is scrolled, in cells across and lines
+	// down
+	scroll geometry.Point
+	// goalX is the column moving up and down keeps the cursor at, or -1
+	// for the cursor's own column
+	goalX int
+	// dragging is set while the primary button, pressed on the field,
+	// extends the selection
+	dragging bool
+}
+
+// textLine is the range of runes a line of the text takes, without its
+// newline
+type textLine struct {
+	start, end int
+}
+
+func (r *RenderTextField) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	lines := r.lines()
+
+	width := constraints.MaxSize.Width
+	if width >= ConstraintsUnbounded.MaxSize.Width {
+		content := textWidth(r.config.placeholder)
+		for _, line := range lines {
+			content = max(content, r.cellsBetween(line.start, line.end))
+		}
+		// Leave a cell for the cursor after the last character
+		width = r.labelWidth() + content + 1
+	}
+	height := 1
+	if r.config.multiline {
+		height = constraints.MaxSize.Height
+		if height >= ConstraintsUnbounded.MaxSize.Height {
+			height = len(lines)
+		}
+	}
+
+	r.size = constraints.Constrain(geometry.Size{Width: width, Height: height})
+	r.scrollToCursor()
+	return r.size
+}
+
+// labelWidth returns the cells taken by the label and the space after it
+func (r *RenderTextField) labelWidth() int {
+	if r.config.label == "" {
+		return 0
+	}
+	return grapheme.Width(r.config.label) + 1
+}
+
+// viewWidth returns the cells the text is shown in
+func (r *RenderTextField) viewWidth() int {
+	return max(0, r.size.Width-r.labelWidth())
+}
+
+// lines returns the lines of the text
+func (r *RenderTextField) lines() []textLine {
+	text := r.controller.text
+	var lines []textLine
+	start := 0
+	for i, ch := range text {
+		if ch == '\n' {
+			lines = append(lines, textLine{start, i})
+			start = i + 1
+		}
+	}
+	return append(lines, textLine{start, len(text)})
+}
+
+// cellsBetween returns the cells taken by the runes from start to end
+func (r *RenderTextField) cellsBetween(start, end int) int {
+	text := r.controller.text
+	cells := 0
+	for i := start; i < end; {
+		next := clusterEnd(text, i)
+		cells += clusterWidth(text[i:next])
+		i = next
+	}
+	return cells
+}
+
+// position returns the column and line of offset
+func (r *RenderTextField) position(offset int) geometry.Point {
+	line := 0
+	for _, ch := range r.controller.text[:offset] {
+		if ch == '\n' {
+			line++
+		}
+	}
+	start := r.controller.lineStart(offset)
+	return geometry.Point{X: r.cellsBetween(start, offset), Y: line}
+}
+
+// offsetAt returns the offset of the character at column x of line y,
+// clamped to the lines there are
+func (r *RenderTextField) offsetAt(x, y int) int {
+	lines := r.lines()
+	line := lines[max(0, min(y, len(lines)-1))]
+	text := r.controller.text
+	cells := 0
+	for i := line.start; i < line.end; {
+		next := clusterEnd(text, i)
+		cells += clusterWidth(text[i:next])
+		if cells > x {
+			return i
+		}
+		i = next
+	}
+	return line.end
+}
+
+// scrollToCursor scrolls just far enough to show the cursor
+func (r *RenderTextField) scrollToCursor() {
+	cursor := r.position(r.controller.cursor)
+	width, height := max(1, r.viewWidth()), max(1, r.size.Height)
+	r.scroll.X = max(min(r.scroll.X, cursor.X), cursor.X-width+1)
+	r.scroll.Y = max(min(r.scroll.Y, cursor.Y), cursor.Y-height+1)
+}
+
+func (r *RenderTextField) Paint(context engine.RenderContext) {
+	paintBackground(context, r.style, geometry.NewRect(0, 0, r.size.Width, r.size.Height))
+	drawString(context, 0, 0, r.config.label, r.style)
+
+	labelWidth := r.labelWidth()
+	context.PushClipRect(geometry.NewRect(labelWidth, 0, r.viewWidth(), r.size.Height))
+	context.PushOffset(geometry.Point{X: labelWidth})
+	if len(r.controller.text) == 0 {
+		drawString(context, 0, 0, r.config.placeholder, r.config.placeholderStyle)
+	} else {
+		r.paintText(context)
+	}
+
+	if r.focus != nil && r.focus.HasFocus() {
+		cursor := r.position(r.controller.cursor).Sub(r.scroll)
+		context.SetCursor(cursor.X, cursor.Y)
+	}
+	context.PopOffset()
+	context.PopClipRect()
+}
+
+// paintText paints the lines in view, scrolled and with the selection
+// highlighted
+func (r *RenderTextField) paintText(context engine.RenderContext) {
+	text := r.controller.text
+	selStart, selEnd := r.controller.Selection()
+	mask := r.mask(context)
+	for y, line := range r.lines() {
+		if y < r.scroll.Y || y >= r.scroll.Y+r.size.Height {
+			continue
+		}
+		x := -r.scroll.X
+		for i := line.start; i < line.end; {
+			next := clusterEnd(text, i)
+			cluster := text[i:next]
+			if mask != 0 {
+				cluster = []rune{mask}
+			}
+			s := r.style
+			if i >= selStart && i < selEnd {
+				s = r.config.selectionStyle
+			}
+			drawCluster(context, x, y-r.scroll.Y, cluster, s)
+			x += clusterWidth(cluster)
+			i = next
+		}
+	}
+}
+
+// mask returns the character masked text is shown with, falling back to
+// an asterisk where a wide or unicode mask can't be drawn, or zero
+func (r *RenderTextField) mask(context engine.RenderContext) rune {
+	mask := r.config.mask
+	if mask > 0x7f && !context.Capabilities().SupportsUnicode || grapheme.Width(string(mask)) > 1 {
+		return '*'
+	}
+	return mask
+}
+
+// drawCluster draws a character, with the marks and characters joined to
+// it, at x, y. A character cut off by the left edge is drawn as blanks.
+func drawCluster(context engine.RenderContext, x, y int, cluster []rune, s WidgetStyle) {
+	if x < 0 {
+		for i := x; i < x+clusterWidth(cluster); i++ {
+			context.DrawStyledCell(i, y, ' ', s.ForegroundColor, s.BackgroundColor, s.Style)
+		}
+		return
+	}
+	textStyle := s.Style
+	textStyle.ForegroundColor = s.ForegroundColor
+	textStyle.BackgroundColor = s.BackgroundColor
+	context.DrawText(geometry.Point{X: x, Y: y}, string(cluster), textStyle)
+}
+
+// drawString draws a single line of text from x, y
+func drawString(context engine.RenderContext, x, y int, text string, s WidgetStyle) {
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		next := clusterEnd(runes, i)
+		drawCluster(context, x, y, runes[i:next], s)
+		x += clusterWidth(runes[i:next])
+		i = next
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import "github.com/watzon/tide/pkg/core/color"
+
+// defaultMask is the character password inputs show for each character
+const defaultMask = '•'
+
+// textFieldConfig holds what TextInput and TextArea are configured with
+type textFieldConfig struct {
+	controller       *TextEditingController
+	focusNode        *FocusNode
+	label            string
+	placeholder      string
+	mask             rune
+	multiline        bool
+	onChanged        func(text string)
+	onSubmitted      func(text string)
+	placeholderStyle WidgetStyle
+	selectionStyle   WidgetStyle
+}
+
+func newTextFieldConfig(multiline bool) textFieldConfig {
+	return textFieldConfig{
+		multiline:        multiline,
+		placeholderStyle: NewWidgetStyle().WithForeground(color.Gray),
+		selectionStyle:   NewWidgetStyle().WithBackground(color.Blue),
+	}
+}
+
+// textField is implemented by the editable text widgets
+type textField interface {
+	StatefulWidget
+	GetStyle() WidgetStyle
+	fieldConfig() textFieldConfig
+}
+
+// TextInput is a single line of editable text. It takes key input while
+// it has focus, which a click or Tab gives it, and shows the cursor where
+// typing goes. Text wider than the input scrolls sideways to keep the
+// cursor in view.
+//
+// The arrow keys, Home and End move the cursor, Ctrl or Alt with the
+// arrows moves by word and Shift extends the selection. Backspace and
+// Delete remove a character, or a word with Ctrl or Alt. Ctrl+A selects
+// everything, Ctrl+C, Ctrl+X and Ctrl+V copy, cut and paste through the
+// clipboard, and Ctrl+Z and Ctrl+Y undo and redo.
+type TextInput struct {
+	BaseWidget
+	config textFieldConfig
+}
+
+// NewTextInput creates an empty single line input
+func NewTextInput() *TextInput {
+	return &TextInput{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		config:     newTextFieldConfig(false),
+	}
+}
+
+// NewPasswordInput creates a single line input that masks what is typed
+func NewPasswordInput() *TextInput {
+	return NewTextInput().WithMask(defaultMask)
+}
+
+// WithController sets the controller holding the text. Without one the
+// input creates its own.
+func (w *TextInput) WithController(controller *TextEditingController) *TextInput {
+	w.config.controller = controller
+	return w
+}
+
+// WithFocusNode sets the node that gives the input focus. Without one the
+// input creates its own.
+func (w *TextInput) WithFocusNode(node *FocusNode) *TextInput {
+	w.config.focusNode = node
+	return w
+}
+
+// WithLabel sets the label shown before the input
+func (w *TextInput) WithLabel(label string) *TextInput {
+	w.config.label = label
+	return w
+}
+
+// WithPlaceholder sets the hint shown while the input is empty
+func (w *TextInput) WithPlaceholder(placeholder string) *TextInput {
+	w.config.placeholder = placeholder
+	return w
+}
+
+// WithMask shows every character as mask, or the text itself when mask is
+// zero. Masked text can't be copied or cut.
+func (w *TextInput) WithMask(mask rune) *TextInput {
+	w.config.mask = mask
+	return w
+}
+
+// WithOnChanged sets the function called with the text after each edit
+func (w *TextInput) WithOnChanged(onChanged func(text string)) *TextInput {
+	w.config.onChanged = onChanged
+	return w
+}
+
+// WithOnSubmitted sets the function called with the text when Enter is
+// pressed
+func (w *TextInput) WithOnSubmitted(onSubmitted func(text string)) *TextInput {
+	w.config.onSubmitted = onSubmitted
+	return w
+}
+
+// WithPlaceholderStyle sets the style of the placeholder
+func (w *TextInput) WithPlaceholderStyle(style WidgetStyle) *TextInput {
+	w.config.placeholderStyle = style
+	return w
+}
+
+// WithSelectionStyle sets the style of selected text
+func (w *TextInput) WithSelectionStyle(style WidgetStyle) *TextInput {
+	w.config.selectionStyle = style
+	return w
+}
+
+func (w *TextInput) WithStyle(style WidgetStyle) *TextInput {
+	w.style = style
+	return w
+}
+
+func (w *TextInput) CreateState() State {
+	return &textFieldState{}
+}
+
+func (w *TextInput) fieldConfig() textFieldConfig {
+	return w.config
+}
+
+// TextArea is editable text over many lines. It edits like a TextInput,
+// with Enter starting a new line, the up and down keys moving between
+// lines and Ctrl+Home and Ctrl+End going to the start and end of the
+// text. Lines aren't wrapped; the text scrolls both ways to keep the
+// cursor in view.
+type TextArea struct {
+	BaseWidget
+	config textFieldConfig
+}
+
+// NewTextArea creates an empty text area
+func NewTextArea() *TextArea {
+	return &TextArea{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		config:     newTextFieldConfig(true),
+	}
+}
+
+// WithController sets the controller holding the text. Without one the
+// area creates its own.
+func (w *TextArea) WithController(controller *TextEditingController) *TextArea {
+	w.config.controller = controller
+	return w
+}
+
+// WithFocusNode sets the node that gives the area focus. Without one the
+// area creates its own.
+func (w *TextArea) WithFocusNode(node *FocusNode) *TextArea {
+	w.config.focusNode = node
+	return w
+}
+
+// WithPlaceholder sets the hint shown while the area is empty
+func (w *TextArea) WithPlaceholder(placeholder string) *TextArea {
+	w.config.placeholder = placeholder
+	return w
+}
+
+// WithOnChanged sets the function called with the text after each edit
+func (w *TextArea) WithOnChanged(onChanged func(text string)) *TextArea {
+	w.config.onChanged = onChanged
+	return w
+}
+
+// WithPlaceholderStyle sets the style of the placeholder
+func (w *TextArea) WithPlaceholderStyle(style WidgetStyle) *TextArea {
+	w.config.placeholderStyle = style
+	return w
+}
+
+// WithSelectionStyle sets the style of selected text
+func (w *TextArea) WithSelectionStyle(style WidgetStyle) *TextArea {
+	w.config.selectionStyle = style
+	return w
+}
+
+func (w *TextArea) WithStyle(style WidgetStyle) *TextArea {
+	w.style = style
+	return w
+}
+
+func (w *TextArea) CreateState() State {
+	return &textFieldState{}
+}
+
+func (w *TextArea) fieldConfig() textFieldConfig {
+	return w.config
+}
+
+// textFieldState owns the controller and focus node of a text widget that
+// wasn't given them, and rebuilds it when either changes
+type textFieldState struct {
+	BaseState
+	controller, ownedController *TextEditingController
+	removeText                  func()
+	focus                       focusBinding
+}
+
+func (s *textFieldState) InitState() {
+	s.bind()
+}
+
+func (s *textFieldState) Dispose() {
+	s.unbindController()
+	s.focus.unbind()
+}
+
+func (s *textFieldState) Build(context BuildContext) Widget {
+	w := s.Widget().(textField)
+	focus := s.bind()
+	var clipboard Clipboard
+	if owner := s.Element().Owner(); owner != nil {
+		clipboard = owner.Clipboard()
+	}
+	return &textFieldView{
+		BaseWidget: BaseWidget{style: w.GetStyle()},
+		config:     w.fieldConfig(),
+		controller: s.controller,
+		focus:      focus,
+		clipboard:  clipboard,
+	}
+}
+
+// bind listens to the controller and focus node of the widget, or to owned
+// ones when it has none, moving the listeners over if they changed. It
+// returns the focus node in use.
+func (s *textFieldState) bind() *FocusNode {
+	config := s.Widget().(textField).fieldConfig()
+
+	controller := config.controller
+	if controller == nil {
+		if s.ownedController == nil {
+			s.ownedController = NewTextEditingController("")
+		}
+		controller = s.ownedController
+	}
+	if controller != s.controller {
+		s.unbindController()
+		s.controller = controller
+		s.removeText = controller.AddListener(s.changed)
+	}
+	return s.focus.bind(config.focusNode, s.Element(), s.changed)
+}
+
+func (s *textFieldState) unbindController() {
+	if s.removeText != nil {
+		s.removeText()
+		s.removeText = nil
+	}
+}
+
+func (s *textFieldState) changed() {
+	s.SetState(nil)
+}
+
+// textFieldView is the widget a text field builds, which creates the
+// render object editing the text
+type textFieldView struct {
+	BaseWidget
+	config     textFieldConfig
+	controller *TextEditingController
+	focus      *FocusNode
+	clipboard  Clipboard
+}
+
+func (w *textFieldView) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *textFieldView) CreateRenderObject() RenderObject {
+	r := &RenderTextField{goalX: -1}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *textFieldView) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderTextField); ok {
+		r.style = w.style
+		r.config = w.config
+		r.controller = w.controller
+		r.focus = w.focus
+		r.clipboard = w.clipboard
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// layoutTextField mounts w and lays out the field it shows
+func layoutTextField(w Widget, width, height int) (*BuildOwner, *RenderTextField) {
+	owner := NewBuildOwner()
+	root := NewElement(w)
+	owner.MountRoot(root)
+	r := root.RenderObject().(*RenderTextField)
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	return owner, r
+}
+
+// typeInto dispatches events to the field, rebuilding and laying it out
+// after each
+func typeInto(owner *BuildOwner, r *RenderTextField, events ...terminal.Event) {
+	for _, event := range events {
+		DispatchEvent(r, event)
+		owner.BuildScope()
+		r.Layout(r.Constraints())
+	}
+}
+
+// typeText dispatches a rune event for each character of text
+func typeText(owner *BuildOwner, r *RenderTextField, text string) {
+	for _, ch := range text {
+		typeInto(owner, r, runeOf(ch))
+	}
+}
+
+// paintField paints the field and returns its lines and the cursor
+func paintField(r *RenderTextField) ([]string, geometry.Point) {
+	m := NewMockRenderContext()
+	r.Paint(m)
+	lines := make([]string, r.Size().Height)
+	for y := range lines {
+		lines[y] = strings.TrimRight(screenRow(m, y, r.Size().Width), " ")
+	}
+	return lines, m.cursor
+}
+
+func click(x, y int) terminal.MouseEvent {
+	return terminal.MouseEvent{Position: geometry.Point{X: x, Y: y}, Buttons: tcell.ButtonPrimary}
+}
+
+func TestTextInput_Typing(t *testing.T) {
+	var changes, submitted []string
+	input := NewTextInput().
+		WithLabel("Name:").
+		WithOnChanged(func(text string) { changes = append(changes, text) }).
+		WithOnSubmitted(func(text string) { submitted = append(submitted, text) })
+	owner, r := layoutTextField(input, 20, 1)
+
+	t.Run("ignores keys until focused", func(t *testing.T) {
+		assert.False(t, DispatchEvent(r, runeOf('x')))
+		lines, cursor := paintField(r)
+		assert.Equal(t, []string{"Name:"}, lines)
+		assert.Equal(t, geometry.Point{X: -1, Y: -1}, cursor)
+	})
+
+	t.Run("a click focuses", func(t *testing.T) {
+		assert.True(t, DispatchEvent(r, click(10, 0)))
+		owner.BuildScope()
+		assert.True(t, r.focus.HasFocus())
+	})
+
+	t.Run("typing edits and reports changes", func(t *testing.T) {
+		typeText(owner, r, "bobby")
+		typeInto(owner, r, keyOf(tcell.KeyBackspace2), keyOf(tcell.KeyLeft), keyOf(tcell.KeyLeft))
+		typeInto(owner, r, keyOf(tcell.KeyCtrlA))
+
+		lines, cursor := paintField(r)
+		assert.Equal(t, []string{"Name: bobb"}, lines)
+		assert.Equal(t, geometry.Point{X: 10, Y: 0}, cursor)
+		assert.Equal(t, []string{"b", "bo", "bob", "bobb", "bobby", "bobb"}, changes)
+	})
+
+	t.Run("enter submits", func(t *testing.T) {
+		typeInto(owner, r, keyOf(tcell.KeyEnter))
+		assert.Equal(t, []string{"bobb"}, submitted)
+		assert.Equal(t, "bobb", r.controller.Text())
+	})
+}
+
+func TestTextInput_Placeholder(t *testing.T) {
+	_, r := layoutTextField(NewTextInput().WithPlaceholder("Search"), 10, 1)
+	lines, _ := paintField(r)
+	assert.Equal(t, []string{"Search"}, lines)
+}
+
+func TestTextInput_Clipboard(t *testing.T) {
+	c := NewTextEditingController("hello world")
+	node := NewFocusNode()
+	node.RequestFocus()
+	owner, r := layoutTextField(NewTextInput().WithController(c).WithFocusNode(node), 20, 1)
+
+	t.Run("nothing selected leaves Ctrl+C alone", func(t *testing.T) {
+		assert.False(t, DispatchEvent(r, keyOf(tcell.KeyCtrlC)))
+	})
+
+	t.Run("cut and paste", func(t *testing.T) {
+		c.SetSelection(5, 11)
+		typeInto(owner, r, keyOf(tcell.KeyCtrlX))
+		assert.Equal(t, "hello", c.Text())
+
+		text, err := owner.Clipboard().GetClipboard()
+		assert.NoError(t, err)
+		assert.Equal(t, " world", text)
+
+		typeInto(owner, r, keyOf(tcell.KeyHome), keyOf(tcell.KeyCtrlV))
+		assert.Equal(t, " worldhello", c.Text())
+	})
+
+	t.Run("pasted lines are joined", func(t *testing.T) {
+		assert.NoError(t, owner.Clipboard().SetClipboard("a\nb\r\nc"))
+		c.SetText("")
+		typeInto(owner, r, keyOf(tcell.KeyCtrlV))
+		assert.Equal(t, "a b c", c.Text())
+		typeInto(owner, r, keyOf(tcell.KeyCtrlZ))
+		assert.Equal(t, "", c.Text())
+	})
+}
+
+func TestPasswordInput(t *testing.T) {
+	c := NewTextEditingController("secret")
+	node := NewFocusNode()
+	node.RequestFocus()
+	owner, r := layoutTextField(NewPasswordInput().WithController(c).WithFocusNode(node), 10, 1)
+
+	lines, _ := paintField(r)
+	assert.Equal(t, []string{"••••••"}, lines)
+
+	assert.NoError(t, owner.Clipboard().SetClipboard("before"))
+	typeInto(owner, r, keyOf(tcell.KeyCtrlA))
+	assert.False(t, DispatchEvent(r, keyOf(tcell.KeyCtrlC)))
+	typeInto(owner, r, keyOf(tcell.KeyCtrlX))
+
+	text, _ := owner.Clipboard().GetClipboard()
+	assert.Equal(t, "before", text, "masked text is never copied")
+	assert.Equal(t, "secret", c.Text())
+}
+
+func TestTextInput_ScrollsWideText(t *testing.T) {
+	c := NewTextEditingController("日本語テキスト")
+	node := NewFocusNode()
+	node.RequestFocus()
+	owner, r := layoutTextField(NewTextInput().WithController(c).WithFocusNode(node), 6, 1)
+
+	lines, cursor := paintField(r)
+	// キ is cut off by the left edge and shows as a blank
+	assert.Equal(t, "スト", strings.ReplaceAll(lines[0], " ", ""))
+	assert.Equal(t, geometry.Point{X: 5, Y: 0}, cursor)
+
+	typeInto(owner, r, keyOf(tcell.KeyHome))
+	lines, cursor = paintField(r)
+	assert.Equal(t, "日本語", strings.ReplaceAll(lines[0], " ", ""))
+	assert.Equal(t, geometry.Point{X: 0, Y: 0}, cursor)
+
+	t.Run("a click places the cursor on the character under it", func(t *testing.T) {
+		typeInto(owner, r, click(3, 0))
+		assert.Equal(t, 1, c.Cursor())
+	})
+}
+
+func TestTextInput_DragSelects(t *testing.T) {
+	c := NewTextEditingController("drag me")
+	owner, r := layoutTextField(NewTextInput().WithController(c), 10, 1)
+
+	typeInto(owner, r, click(1, 0))
+	drag := click(4, 0)
+	drag.Motion = true
+	typeInto(owner, r, drag)
+	typeInto(owner, r, terminal.MouseEvent{Position: geometry.Point{X: 4}})
+
+	assert.Equal(t, "rag", c.SelectedText())
+}
+
+func TestTextArea_Lines(t *testing.T) {
+	c := NewTextEditingController("long line\nab\nanother line")
+	node := NewFocusNode()
+	node.RequestFocus()
+	owner, r := layoutTextField(NewTextArea().WithController(c).WithFocusNode(node), 20, 5)
+
+	t.Run("up and down keep the column", func(t *testing.T) {
+		c.SetSelection(7, 7)
+		typeInto(owner, r, keyOf(tcell.KeyDown))
+		assert.Equal(t, 12, c.Cursor(), "a short line puts the cursor at its end")
+		typeInto(owner, r, keyOf(tcell.KeyDown))
+		assert.Equal(t, 20, c.Cursor())
+		typeInto(owner, r, keyOf(tcell.KeyUp), keyOf(tcell.KeyUp))
+		assert.Equal(t, 7, c.Cursor())
+	})
+
+	t.Run("enter starts a line", func(t *testing.T) {
+		typeInto(owner, r, terminal.KeyEvent{Key: tcell.KeyEnd, Modifiers: tcell.ModCtrl}, keyOf(tcell.KeyEnter))
+		typeText(owner, r, "x")
+		lines, cursor := paintField(r)
+		assert.Equal(t, []string{"long line", "ab", "another line", "x", ""}, lines)
+		assert.Equal(t, geometry.Point{X: 1, Y: 3}, cursor)
+	})
+
+	t.Run("scrolls down to the cursor", func(t *testing.T) {
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 2}))
+		lines, cursor := paintField(r)
+		assert.Equal(t, []string{"another line", "x"}, lines)
+		assert.Equal(t, geometry.Point{X: 1, Y: 1}, cursor)
+	})
+}
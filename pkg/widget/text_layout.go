@@ -0,0 +1,253 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+	"strings"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/core/grapheme"
+)
+
+// fadeCells is how many cells at the end of a line fade into the
+// background when text overflows with OverflowFade
+const fadeCells = 4
+
+// TextAlign is where lines of text sit across the width they are laid out
+// in
+type TextAlign int
+
+const (
+	TextAlignLeft TextAlign = iota
+	TextAlignCenter
+	TextAlignRight
+	// TextAlignJustify widens the spaces of wrapped lines so they fill the
+	// width. The last line of a paragraph is left aligned.
+	TextAlignJustify
+)
+
+// LineBreak is where soft wrapped text may break its lines
+type LineBreak int
+
+const (
+	// BreakWord breaks lines between words. Words wider than a line are
+	// broken between characters.
+	BreakWord LineBreak = iota
+	// BreakCharacter breaks lines between any two characters
+	BreakCharacter
+)
+
+// TextOverflow is how text that doesn't fit is cut off. It applies to
+// lines wider than the text and, when lines are left out for the height
+// or the maximum number of lines, to the last line shown.
+type TextOverflow int
+
+const (
+	// OverflowClip cuts the text off at the edge
+	OverflowClip TextOverflow = iota
+	// OverflowEllipsis ends the text with an ellipsis
+	OverflowEllipsis
+	// OverflowFade fades the end of the text into the background
+	OverflowFade
+)
+
+// textOptions holds how text is wrapped, aligned and cut off
+type textOptions struct {
+	softWrap  bool
+	lineBreak LineBreak
+	align     TextAlign
+	// maxLines limits the lines shown, or is zero for no limit
+	maxLines int
+	overflow TextOverflow
+}
+
+// defaultTextOptions wraps text between words, left aligned and clipped
+func defaultTextOptions() textOptions {
+	return textOptions{softWrap: true}
+}
+
+// textRow is a laid out line of text
+type textRow struct {
+	text  string
+	width int
+	// start is the byte offset of the line in the content
+	start int
+	// last is set on the last line of a paragraph, which justified text
+	// leaves ragged
+	last bool
+}
+
+// layout breaks content into lines at most width cells wide. Lines only
+// break at newlines when soft wrapping is off or width is unbounded.
+func (o textOptions) layout(content string, width int) []textRow {
+	var rows []textRow
+	offset := 0
+	for _, paragraph := range strings.Split(content, "\n") {
+		if !o.softWrap || width >= math.MaxInt32 {
+			rows = append(rows, textRow{text: paragraph, width: cellWidth(paragraph), start: offset, last: true})
+			offset += len(paragraph) + 1
+			continue
+		}
+
+		var lines []wrappedLine
+		if o.lineBreak == BreakCharacter {
+			lines = breakCharacters(paragraph, max(1, width))
+		} else {
+			lines = breakWords(paragraph, max(1, width))
+		}
+		for i, line := range lines {
+			rows = append(rows, textRow{text: line.text, width: cellWidth(line.text), start: offset + line.start, last: i == len(lines)-1})
+		}
+		offset += len(paragraph) + 1
+	}
+	return rows
+}
+
+// minWidth returns the narrowest width the content can be laid out in
+// without lines overflowing: its widest word or character when wrapping,
+// or its widest line when not
+func (o textOptions) minWidth(content string) int {
+	if !o.softWrap {
+		return rowsSize(o.layout(content, math.MaxInt32)).Width
+	}
+	width := 0
+	for _, paragraph := range strings.Split(content, "\n") {
+		if o.lineBreak == BreakCharacter {
+			width = max(width, widestCharacter(paragraph))
+			continue
+		}
+		for _, word := range strings.Split(paragraph, " ") {
+			width = max(width, cellWidth(word))
+		}
+	}
+	return width
+}
+
+// wrappedLine is a line text was broken into and the byte offset where it
+// starts in the text
+type wrappedLine struct {
+	text  string
+	start int
+}
+
+// breakWords breaks text between words into lines of at most width cells.
+// The spaces lines are broken at are dropped, and words wider than a line
+// are broken between characters.
+func breakWords(text string, width int) []wrappedLine {
+	var lines []wrappedLine
+	var line strings.Builder
+	lineStart, lineWidth := 0, 0
+	next := 0
+	for i, word := range strings.Split(text, " ") {
+		start := next
+		next += len(word) + 1
+		wordWidth := cellWidth(word)
+		if i > 0 && lineWidth+1+wordWidth <= width {
+			line.WriteByte(' ')
+			line.WriteString(word)
+			lineWidth += 1 + wordWidth
+			continue
+		}
+		if i > 0 {
+			// Runs of spaces don't start a line of their own
+			if word == "" {
+				continue
+			}
+			lines = append(lines, wrappedLine{text: line.String(), start: lineStart})
+			line.Reset()
+		}
+		if wordWidth > width {
+			pieces := breakCharacters(word, width)
+			for _, piece := range pieces[:len(pieces)-1] {
+				lines = append(lines, wrappedLine{text: piece.text, start: start + piece.start})
+			}
+			last := pieces[len(pieces)-1]
+			word, start = last.text, start+last.start
+			wordWidth = cellWidth(word)
+		}
+		line.WriteString(word)
+		lineStart, lineWidth = start, wordWidth
+	}
+	return append(lines, wrappedLine{text: line.String(), start: lineStart})
+}
+
+// breakCharacters breaks text between characters into lines of at most
+// width cells. A character wider than a line gets a line of its own.
+func breakCharacters(text string, width int) []wrappedLine {
+	runes := []rune(text)
+	// offsets[i] is the byte offset of rune i, then the length of the text
+	offsets := make([]int, 0, len(runes)+1)
+	for offset := range text {
+		offsets = append(offsets, offset)
+	}
+	offsets = append(offsets, len(text))
+
+	var lines []wrappedLine
+	start, lineWidth := 0, 0
+	for i := 0; i < len(runes); {
+		next := clusterEnd(runes, i)
+		w := clusterWidth(runes[i:next])
+		if lineWidth > 0 && lineWidth+w > width {
+			lines = append(lines, wrappedLine{text: text[offsets[start]:offsets[i]], start: offsets[start]})
+			start, lineWidth = i, 0
+		}
+		lineWidth += w
+		i = next
+	}
+	return append(lines, wrappedLine{text: text[offsets[start]:], start: offsets[start]})
+}
+
+// rowsSize returns the size laid out rows take
+func rowsSize(rows []textRow) (size geometry.Size) {
+	for _, row := range rows {
+		size.Width = max(size.Width, row.width)
+	}
+	size.Height = len(rows)
+	return size
+}
+
+// cellWidth returns the cells text takes on one line
+func cellWidth(text string) int {
+	return grapheme.Width(text)
+}
+
+// widestCharacter returns the cells taken by the widest character of text
+func widestCharacter(text string) int {
+	width := 0
+	for _, cluster := range grapheme.Clusters(text) {
+		width = max(width, cluster.Width)
+	}
+	return width
+}
+
+// cutCells returns the longest start of text that fits in width cells
+func cutCells(text string, width int) string {
+	var cut strings.Builder
+	cells := 0
+	for _, cluster := range grapheme.Clusters(text) {
+		cells += cluster.Width
+		if cells > width {
+			break
+		}
+		cut.WriteString(cluster.Text)
+	}
+	return cut.String()
+}
+
+// withEllipsis cuts text down so it fits in width cells followed by an
+// ellipsis, which is spelled out in dots without unicode
+func withEllipsis(text string, width int, unicode bool) string {
+	ellipsis := "…"
+	if !unicode {
+		ellipsis = "..."
+	}
+	room := width - cellWidth(ellipsis)
+	if room < 0 {
+		return cutCells(ellipsis, width)
+	}
+	return strings.TrimRight(cutCells(text, room), " ") + ellipsis
+}
@@ -6,6 +6,9 @@
 package widget
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -43,3 +46,163 @@ func TestText_Paint(t *testing.T) {
 		assert.Equal(t, color.Blue, cell.Bg)
 	}
 }
+
+// textLines lays text out at width by height and returns the painted lines
+// without trailing space
+func textLines(text *Text, width, height int) []string {
+	r := text.CreateRenderObject()
+	size := r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	m := NewMockRenderContext()
+	r.Paint(m)
+	lines := make([]string, size.Height)
+	for y := range lines {
+		lines[y] = strings.TrimRight(screenRow(m, y, width), " ")
+	}
+	return lines
+}
+
+func TestText_Wrap(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  *Text
+		width int
+		want  []string
+	}{
+		{"between words", NewText("the quick brown fox"), 10, []string{"the quick", "brown fox"}},
+		{"long words break", NewText("a supercalifragilistic day"), 8, []string{"a", "supercal", "ifragili", "stic day"}},
+		{"newlines are kept", NewText("one two\n\nthree"), 5, []string{"one", "two", "", "three"}},
+		{"indentation is kept", NewText("  a b c"), 5, []string{"  a b", "c"}},
+		{"by character", NewText("the quick").WithLineBreak(BreakCharacter), 4, []string{"the", "quic", "k"}},
+		// The mock leaves the second cell of a wide character blank
+		{"wide characters take two cells", NewText("日本語 テキスト"), 7, []string{"日 本 語", "テ キ ス", "ト"}},
+		{"emoji sequences and flags take two cells", NewText("👨‍👩‍👧 🇯🇵 ok"), 5, []string{"👨‍👩‍👧  🇯🇵", "ok"}},
+		{"without soft wrap", NewText("the quick brown").WithSoftWrap(false), 9, []string{"the quick"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, textLines(tt.text, tt.width, 10))
+		})
+	}
+}
+
+func TestTextOptions_LayoutStarts(t *testing.T) {
+	tests := []struct {
+		name    string
+		options textOptions
+		content string
+		width   int
+		starts  []int
+	}{
+		{"between words", defaultTextOptions(), "the quick brown fox", 10, []int{0, 10}},
+		{"runs of spaces", defaultTextOptions(), "ab    cd ef", 4, []int{0, 6, 9}},
+		{"long words", defaultTextOptions(), "a supercalifragilistic", 8, []int{0, 2, 10, 18}},
+		{"paragraphs", defaultTextOptions(), "one two\n\nthree", 5, []int{0, 4, 8, 9}},
+		{"by character", textOptions{softWrap: true, lineBreak: BreakCharacter}, "日本語 テ", 4, []int{0, 6, 10}},
+		{"combining marks", textOptions{softWrap: true, lineBreak: BreakCharacter}, "e\u0301e\u0301e", 2, []int{0, 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := tt.options.layout(tt.content, tt.width)
+			starts := make([]int, len(rows))
+			for i, row := range rows {
+				starts[i] = row.start
+				assert.Equal(t, row.text, tt.content[row.start:row.start+len(row.text)], "row %d", i)
+			}
+			assert.Equal(t, tt.starts, starts)
+		})
+	}
+}
+
+func TestText_Align(t *testing.T) {
+	tests := []struct {
+		align TextAlign
+		want  []string
+	}{
+		{TextAlignLeft, []string{"a bb ccc", "dd e"}},
+		{TextAlignCenter, []string{" a bb ccc", "   dd e"}},
+		{TextAlignRight, []string{"  a bb ccc", "      dd e"}},
+		{TextAlignJustify, []string{"a  bb  ccc", "dd e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.align), func(t *testing.T) {
+			text := NewText("a bb ccc dd e").WithTextAlign(tt.align)
+			r := text.CreateRenderObject()
+			r.Layout(NewConstraints(geometry.Size{Width: 10}, geometry.Size{Width: 10, Height: 5}))
+			m := NewMockRenderContext()
+			r.Paint(m)
+			assert.Equal(t, tt.want, []string{
+				strings.TrimRight(screenRow(m, 0, 10), " "),
+				strings.TrimRight(screenRow(m, 1, 10), " "),
+			})
+		})
+	}
+}
+
+func TestText_Overflow(t *testing.T) {
+	content := "one two three four"
+	tests := []struct {
+		name   string
+		text   *Text
+		width  int
+		height int
+		want   []string
+	}{
+		{"max lines clip", NewText(content).WithMaxLines(2), 9, 10, []string{"one two", "three"}},
+		{"max lines ellipsis", NewText(content).WithMaxLines(2).WithOverflow(OverflowEllipsis), 9, 10, []string{"one two", "three…"}},
+		// Wrapped at nine cells the text is seven wide, its widest line
+		{"height ellipsis", NewText(content).WithOverflow(OverflowEllipsis), 9, 1, []string{"one tw…"}},
+		{"long line ellipsis", NewText(content).WithSoftWrap(false).WithOverflow(OverflowEllipsis), 9, 1, []string{"one two…"}},
+		{"wide characters ellipsis", NewText("日本語テキスト").WithSoftWrap(false).WithOverflow(OverflowEllipsis), 6, 1, []string{"日 本 …"}},
+		{"fitting text is left alone", NewText("one").WithOverflow(OverflowEllipsis), 9, 1, []string{"one"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, textLines(tt.text, tt.width, tt.height))
+		})
+	}
+}
+
+func TestText_OverflowFade(t *testing.T) {
+	text := NewText("abcdefghij").
+		WithSoftWrap(false).
+		WithOverflow(OverflowFade).
+		WithStyle(NewWidgetStyle().WithForeground(color.White).WithBackground(color.Black))
+	r := text.CreateRenderObject()
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 1}))
+	m := NewMockRenderContext()
+	r.Paint(m)
+
+	assert.Equal(t, "abcdefgh", screenRow(m, 0, 8))
+	assert.Equal(t, color.White, m.cells[geometry.Point{X: 3}].Fg)
+	previous := color.White
+	for x := 4; x < 8; x++ {
+		fg := m.cells[geometry.Point{X: x}].Fg
+		assert.Less(t, fg.R, previous.R, "cell %d fades further", x)
+		previous = fg
+	}
+}
+
+func TestText_Intrinsics(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  *Text
+		width int
+		want  intrinsics
+	}{
+		{"wrapping", NewText("the quick brown\nfox"), 10, intrinsics{5, 15, 3, 3}},
+		{"by character", NewText("ab 日本").WithLineBreak(BreakCharacter), 4, intrinsics{2, 7, 2, 2}},
+		{"without wrapping", NewText("the quick brown").WithSoftWrap(false), 5, intrinsics{15, 15, 1, 1}},
+		{"max lines", NewText("a b c d").WithMaxLines(2), 1, intrinsics{1, 7, 2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := tt.text.CreateRenderObject()
+			assert.Equal(t, tt.want, measureIntrinsics(r, tt.width, math.MaxInt32))
+		})
+	}
+}
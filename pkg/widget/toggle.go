@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+// Checkbox is a label with a box that is checked or not. Activating it,
+// by a click or by Enter or Space while it has focus, calls onChanged with
+// the opposite of checked; the parent rebuilds it with the new value. It
+// shows focus, hover, presses and being disabled like a Button.
+type Checkbox struct {
+	BaseWidget
+	config controlConfig
+}
+
+// NewCheckbox creates a checkbox showing label and checked. A checkbox
+// without onChanged is disabled.
+func NewCheckbox(label string, checked bool, onChanged func(checked bool)) *Checkbox {
+	config := controlConfig{
+		label: label,
+		indicator: func(unicode bool) string {
+			switch {
+			case !checked:
+				return "[ ]"
+			case unicode:
+				return "[✓]"
+			}
+			return "[x]"
+		},
+		disabled: onChanged == nil,
+	}
+	if onChanged != nil {
+		config.activate = func() { onChanged(!checked) }
+	}
+	return &Checkbox{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		config:     config,
+	}
+}
+
+// WithFocusNode sets the node that gives the checkbox focus. Without one
+// the checkbox creates its own.
+func (w *Checkbox) WithFocusNode(node *FocusNode) *Checkbox {
+	w.config.focusNode = node
+	return w
+}
+
+// WithDisabled sets whether the checkbox is disabled
+func (w *Checkbox) WithDisabled(disabled bool) *Checkbox {
+	w.config.disabled = disabled || w.config.activate == nil
+	return w
+}
+
+// WithFocusedStyle sets the style the checkbox has while it has focus
+func (w *Checkbox) WithFocusedStyle(style WidgetStyle) *Checkbox {
+	w.config.focusedStyle = &style
+	return w
+}
+
+func (w *Checkbox) WithStyle(style WidgetStyle) *Checkbox {
+	w.style = style
+	return w
+}
+
+func (w *Checkbox) CreateState() State {
+	return &controlState{}
+}
+
+func (w *Checkbox) controlConfig() controlConfig {
+	return w.config
+}
+
+// Switch is a label with a knob that is on or off. It behaves like a
+// Checkbox.
+type Switch struct {
+	BaseWidget
+	config controlConfig
+}
+
+// NewSwitch creates a switch showing label and whether it is on. A switch
+// without onChanged is disabled.
+func NewSwitch(label string, on bool, onChanged func(on bool)) *Switch {
+	config := controlConfig{
+		label: label,
+		indicator: func(unicode bool) string {
+			switch {
+			case on && unicode:
+				return "[ ●]"
+			case on:
+				return "[ O]"
+			case unicode:
+				return "[○ ]"
+			}
+			return "[o ]"
+		},
+		disabled: onChanged == nil,
+	}
+	if onChanged != nil {
+		config.activate = func() { onChanged(!on) }
+	}
+	return &Switch{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		config:     config,
+	}
+}
+
+// WithFocusNode sets the node that gives the switch focus. Without one the
+// switch creates its own.
+func (w *Switch) WithFocusNode(node *FocusNode) *Switch {
+	w.config.focusNode = node
+	return w
+}
+
+// WithDisabled sets whether the switch is disabled
+func (w *Switch) WithDisabled(disabled bool) *Switch {
+	w.config.disabled = disabled || w.config.activate == nil
+	return w
+}
+
+// WithFocusedStyle sets the style the switch has while it has focus
+func (w *Switch) WithFocusedStyle(style WidgetStyle) *Switch {
+	w.config.focusedStyle = &style
+	return w
+}
+
+func (w *Switch) WithStyle(style WidgetStyle) *Switch {
+	w.style = style
+	return w
+}
+
+func (w *Switch) CreateState() State {
+	return &controlState{}
+}
+
+func (w *Switch) controlConfig() controlConfig {
+	return w.config
+}
+
+// Radio is one choice of a RadioGroup, selected when the group's value is
+// the radio's. Activating it selects it, and while it has focus the arrow
+// keys select and focus the radio before or after it in the group.
+type Radio struct {
+	BaseWidget
+	config controlConfig
+}
+
+// NewRadio creates a radio showing label that selects value in group. A
+// radio without a group is disabled.
+func NewRadio(label, value string, group *RadioGroup) *Radio {
+	config := controlConfig{
+		label: label,
+		indicator: func(unicode bool) string {
+			switch {
+			case group == nil || group.Value() != value:
+				return "( )"
+			case unicode:
+				return "(•)"
+			}
+			return "(*)"
+		},
+		group:    group,
+		value:    value,
+		disabled: group == nil,
+	}
+	if group != nil {
+		config.activate = func() { group.Select(value) }
+	}
+	return &Radio{
+		BaseWidget: BaseWidget{style: NewWidgetStyle()},
+		config:     config,
+	}
+}
+
+// WithFocusNode sets the node that gives the radio focus. Without one the
+// radio creates its own.
+func (w *Radio) WithFocusNode(node *FocusNode) *Radio {
+	w.config.focusNode = node
+	return w
+}
+
+// WithDisabled sets whether the radio is disabled. The arrow keys skip
+// disabled radios.
+func (w *Radio) WithDisabled(disabled bool) *Radio {
+	w.config.disabled = disabled || w.config.group == nil
+	return w
+}
+
+// WithFocusedStyle sets the style the radio has while it has focus
+func (w *Radio) WithFocusedStyle(style WidgetStyle) *Radio {
+	w.config.focusedStyle = &style
+	return w
+}
+
+func (w *Radio) WithStyle(style WidgetStyle) *Radio {
+	w.style = style
+	return w
+}
+
+func (w *Radio) CreateState() State {
+	return &controlState{}
+}
+
+func (w *Radio) controlConfig() controlConfig {
+	return w.config
+}
@@ -0,0 +1,371 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+
+	"github.com/watzon/tide/pkg/core/color"
+)
+
+// TreeNode is a node of a TreeView. Nodes are told apart by key, which
+// must be unique within the tree.
+type TreeNode struct {
+	Key   string
+	Label string
+	// Children are the node's children when they are known up front. A
+	// node with no children that isn't a leaf has them loaded when it is
+	// first expanded.
+	Children []TreeNode
+	Leaf     bool
+}
+
+// NewTreeNode creates a node whose children are loaded on expansion
+func NewTreeNode(key, label string) TreeNode {
+	return TreeNode{Key: key, Label: label}
+}
+
+// NewTreeLeaf creates a node without children
+func NewTreeLeaf(key, label string) TreeNode {
+	return TreeNode{Key: key, Label: label, Leaf: true}
+}
+
+// WithChildren sets the node's children
+func (n TreeNode) WithChildren(children ...TreeNode) TreeNode {
+	n.Children = children
+	return n
+}
+
+// TreeChildLoader loads the children of node and passes them to done.
+// done may be called before the loader returns or later from any
+// goroutine; the children show from the next frame. Slow loads should
+// happen on another goroutine.
+type TreeChildLoader func(node TreeNode, done func(children []TreeNode))
+
+// TreeView shows a tree of nodes one per line, with guides joining each
+// node to its parent. Children of a node are only asked for when it is
+// first expanded, and which nodes are expanded is kept by key while the
+// tree is rebuilt.
+//
+// While the tree has focus, which a click gives it, the up and down keys,
+// PgUp/PgDn and Home/End move the selection, as clicks do. Right expands
+// the selected node or moves to its first child, left collapses it or
+// moves to its parent, and enter, space or a click on the expander
+// toggles it. Typing the start of a label selects the next
+// matching node. A filter shows only the loaded nodes whose label holds
+// it, along with their ancestors.
+type TreeView struct {
+	BaseWidget
+	roots          []TreeNode
+	load           TreeChildLoader
+	controller     *ScrollController
+	focusNode      *FocusNode
+	filter         string
+	onSelect       func(node TreeNode)
+	selectionStyle WidgetStyle
+	guideStyle     WidgetStyle
+}
+
+// NewTreeView creates a tree with the given roots whose unknown children
+// are loaded by load
+func NewTreeView(roots []TreeNode, load TreeChildLoader) *TreeView {
+	return &TreeView{
+		BaseWidget:     BaseWidget{style: NewWidgetStyle()},
+		roots:          roots,
+		load:           load,
+		selectionStyle: NewWidgetStyle().WithBackground(color.Blue),
+		guideStyle:     NewWidgetStyle().WithForeground(color.Gray),
+	}
+}
+
+// WithController sets the controller that reads and changes the offset.
+// Without one the tree creates its own.
+func (w *TreeView) WithController(controller *ScrollController) *TreeView {
+	w.controller = controller
+	return w
+}
+
+// WithFocusNode sets the node that gives the tree focus. Without one the
+// tree creates its own.
+func (w *TreeView) WithFocusNode(node *FocusNode) *TreeView {
+	w.focusNode = node
+	return w
+}
+
+// WithFilter shows only nodes whose label contains query, ignoring case,
+// and their ancestors. An empty query shows every expanded node.
+func (w *TreeView) WithFilter(query string) *TreeView {
+	w.filter = query
+	return w
+}
+
+// WithOnSelect sets the function called with each newly selected node
+func (w *TreeView) WithOnSelect(onSelect func(node TreeNode)) *TreeView {
+	w.onSelect = onSelect
+	return w
+}
+
+// WithSelectionStyle sets the colors the selected node is painted with
+func (w *TreeView) WithSelectionStyle(style WidgetStyle) *TreeView {
+	w.selectionStyle = style
+	return w
+}
+
+// WithGuideStyle sets the colors of the guides and expanders
+func (w *TreeView) WithGuideStyle(style WidgetStyle) *TreeView {
+	w.guideStyle = style
+	return w
+}
+
+func (w *TreeView) WithStyle(style WidgetStyle) *TreeView {
+	w.style = style
+	return w
+}
+
+func (w *TreeView) CreateState() State {
+	return &treeViewState{}
+}
+
+// treeViewState keeps which nodes are expanded, the children loaded for
+// them and the selected node, all by key
+type treeViewState struct {
+	BaseState
+	binding  scrollBinding
+	focus    focusBinding
+	expanded map[string]bool
+	children map[string][]TreeNode
+	loading  map[string]bool
+	selected string
+	disposed bool
+}
+
+func (s *treeViewState) InitState() {
+	s.expanded = make(map[string]bool)
+	s.children = make(map[string][]TreeNode)
+	s.loading = make(map[string]bool)
+	s.bind()
+	s.focus.bind(s.Widget().(*TreeView).focusNode, s.Element(), s.changed)
+}
+
+func (s *treeViewState) Dispose() {
+	s.disposed = true
+	s.binding.unbind()
+	s.focus.unbind()
+}
+
+func (s *treeViewState) Build(context BuildContext) Widget {
+	w := s.Widget().(*TreeView)
+	return &treeViewport{
+		tree:       w,
+		rows:       s.flatten(w),
+		selected:   s.selected,
+		controller: s.bind(),
+		focus:      s.focus.bind(w.focusNode, s.Element(), s.changed),
+		toggle:     s.toggle,
+		selectKey:  s.selectKey,
+		onChange:   s.changed,
+	}
+}
+
+// bind listens to the widget's controller and returns it
+func (s *treeViewState) bind() *ScrollController {
+	return s.binding.bind(s.Widget().(*TreeView).controller, s.changed, s.Context().Post)
+}
+
+func (s *treeViewState) changed() {
+	s.SetState(nil)
+}
+
+// toggle expands or collapses the node with key
+func (s *treeViewState) toggle(key string) {
+	s.SetState(func() {
+		if s.expanded[key] {
+			delete(s.expanded, key)
+		} else {
+			s.expanded[key] = true
+		}
+	})
+}
+
+func (s *treeViewState) selectKey(key string) {
+	s.SetState(func() {
+		s.selected = key
+	})
+}
+
+// childrenOf returns the children of node and whether they are known
+func (s *treeViewState) childrenOf(node TreeNode) ([]TreeNode, bool) {
+	if node.Children != nil || node.Leaf {
+		return node.Children, true
+	}
+	children, ok := s.children[node.Key]
+	return children, ok
+}
+
+// request starts loading the children of node
+func (s *treeViewState) request(load TreeChildLoader, node TreeNode) {
+	if s.loading[node.Key] {
+		return
+	}
+	s.loading[node.Key] = true
+	post := s.Context().Post
+	key := node.Key
+	load(node, func(children []TreeNode) {
+		post(func() {
+			if s.disposed {
+				return
+			}
+			s.SetState(func() {
+				delete(s.loading, key)
+				s.children[key] = children
+			})
+		})
+	})
+}
+
+// treeRow is a node shown on one line of the tree
+type treeRow struct {
+	node  TreeNode
+	depth int
+	// lasts holds, for each depth down to the node's own, whether the
+	// ancestor at that depth is the last of its siblings
+	lasts []bool
+	// parent is the row of the node's parent, or -1 for a root
+	parent     int
+	expandable bool
+	expanded   bool
+	loading    bool
+}
+
+// flatten lists the rows the tree shows, requesting the children of
+// expanded nodes that aren't loaded yet
+func (s *treeViewState) flatten(w *TreeView) []treeRow {
+	f := &treeFlattener{state: s, tree: w, query: strings.ToLower(w.filter)}
+	if f.query != "" {
+		f.matched = make(map[string]bool)
+	}
+	f.add(w.roots, nil, -1)
+	return f.rows
+}
+
+// treeFlattener walks the visible part of a tree into rows
+type treeFlattener struct {
+	state   *treeViewState
+	tree    *TreeView
+	query   string
+	matched map[string]bool
+	rows    []treeRow
+}
+
+func (f *treeFlattener) add(nodes []TreeNode, lasts []bool, parent int) {
+	if f.query != "" {
+		nodes = f.filter(nodes)
+	}
+	for i, node := range nodes {
+		row := treeRow{
+			node:   node,
+			depth:  len(lasts),
+			lasts:  append(lasts[:len(lasts):len(lasts)], i == len(nodes)-1),
+			parent: parent,
+		}
+		children, known := f.state.childrenOf(node)
+		// Without a loader, children that aren't given don't exist
+		known = known || f.tree.load == nil
+		row.expandable = !known || len(children) > 0
+		row.expanded = row.expandable && (f.state.expanded[node.Key] || f.query != "")
+		if row.expanded && !known {
+			if f.query != "" {
+				row.expanded = false
+			} else {
+				f.state.request(f.tree.load, node)
+				row.loading = true
+			}
+		}
+
+		f.rows = append(f.rows, row)
+		if row.expanded && known {
+			f.add(children, row.lasts, len(f.rows)-1)
+		}
+	}
+}
+
+// filter returns the nodes that match the query or have a loaded
+// descendant that does
+func (f *treeFlattener) filter(nodes []TreeNode) []TreeNode {
+	var kept []TreeNode
+	for _, node := range nodes {
+		if f.matches(node) {
+			kept = append(kept, node)
+		}
+	}
+	return kept
+}
+
+func (f *treeFlattener) matches(node TreeNode) bool {
+	if matched, ok := f.matched[node.Key]; ok {
+		return matched
+	}
+	matched := strings.Contains(strings.ToLower(node.Label), f.query)
+	if children, known := f.state.childrenOf(node); known && !matched {
+		for _, child := range children {
+			if f.matches(child) {
+				matched = true
+				break
+			}
+		}
+	}
+	f.matched[node.Key] = matched
+	return matched
+}
+
+// treeViewport is the widget the tree builds, which creates the render
+// object painting its rows
+type treeViewport struct {
+	BaseWidget
+	tree       *TreeView
+	rows       []treeRow
+	selected   string
+	controller *ScrollController
+	focus      *FocusNode
+	toggle     func(key string)
+	selectKey  func(key string)
+	onChange   func()
+}
+
+func (w *treeViewport) Build(context BuildContext) Widget {
+	return w
+}
+
+func (w *treeViewport) CreateRenderObject() RenderObject {
+	r := &RenderTreeView{cell: newLineRenderObject()}
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *treeViewport) UpdateRenderObject(renderObject RenderObject) {
+	r, ok := renderObject.(*RenderTreeView)
+	if !ok {
+		return
+	}
+	r.style = w.tree.style
+	r.tree = w.tree
+	r.rows = w.rows
+	r.controller = w.controller
+	r.focus = w.focus
+	r.toggle = w.toggle
+	r.selectKey = w.selectKey
+	r.onChange = w.onChange
+
+	// Follow the selected node to its new row, or stay on the same line
+	// when it is no longer shown
+	r.selected = max(0, min(r.selected, len(w.rows)-1))
+	for i, row := range w.rows {
+		if row.node.Key == w.selected {
+			r.selected = i
+			break
+		}
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+	"github.com/watzon/tide/pkg/engine"
+)
+
+// typeAheadTimeout is how long after the last key typed characters are
+// still added to the search
+const typeAheadTimeout = time.Second
+
+// treeIndent is the width of each guide column, of the connector and of
+// the expander
+const treeIndent = 2
+
+// treeGlyphs are the pieces of guides and expanders a tree is drawn with
+type treeGlyphs struct {
+	pipe, tee, corner, leaf      string
+	collapsed, expanded, loading string
+}
+
+var (
+	unicodeTreeGlyphs = treeGlyphs{"│ ", "├─", "└─", "─ ", "▸ ", "▾ ", "⋯ "}
+	asciiTreeGlyphs   = treeGlyphs{"| ", "+-", "`-", "- ", "> ", "v ", "~ "}
+)
+
+// RenderTreeView paints the rows of a tree that are in view
+type RenderTreeView struct {
+	BaseRenderObject
+	tree       *TreeView
+	rows       []treeRow
+	controller *ScrollController
+	focus      *FocusNode
+	// toggle and selectKey keep expansion and selection in the state, and
+	// onChange asks for the tree to be painted again
+	toggle    func(key string)
+	selectKey func(key string)
+	onChange  func()
+
+	selected int
+	bar      bool
+	typed    string
+	typedAt  time.Time
+	cell     *TextRenderObject
+}
+
+func (r *RenderTreeView) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	lines := len(r.rows)
+	height := constraints.Constrain(geometry.Size{Height: lines}).Height
+	r.bar = lines > height && constraints.MaxSize.Width > 1
+
+	width := 0
+	for _, row := range r.rows {
+		width = max(width, r.labelX(row)+textWidth(row.node.Label))
+	}
+	r.size = constraints.Constrain(geometry.Size{Width: width + r.barWidth(), Height: height})
+	r.controller.updateExtents(r.size.Height, lines)
+	return r.size
+}
+
+func (r *RenderTreeView) barWidth() int {
+	if r.bar {
+		return 1
+	}
+	return 0
+}
+
+// labelX returns where the label of a row starts, after its guides,
+// connector and expander
+func (r *RenderTreeView) labelX(row treeRow) int {
+	return row.depth*treeIndent + treeIndent
+}
+
+func (r *RenderTreeView) Paint(context engine.RenderContext) {
+	glyphs := asciiTreeGlyphs
+	if context.Capabilities().SupportsUnicode {
+		glyphs = unicodeTreeGlyphs
+	}
+	width := max(0, r.size.Width-r.barWidth())
+	context.PushClipRect(geometry.NewRect(0, 0, width, r.size.Height))
+	for y := 0; y < r.size.Height; y++ {
+		if i := r.controller.Offset() + y; i < len(r.rows) {
+			r.paintRow(context, i, y, width, glyphs)
+		}
+	}
+	context.PopClipRect()
+
+	if r.bar {
+		paintScrollbar(context, AxisVertical, r.size, r.controller, r.style)
+	}
+}
+
+// paintRow paints the guides, expander and label of row i on line y
+func (r *RenderTreeView) paintRow(context engine.RenderContext, i, y, width int, glyphs treeGlyphs) {
+	row := r.rows[i]
+	s := r.style
+	if i == r.selected {
+		s = r.tree.selectionStyle
+	}
+	paintBackground(context, s, geometry.NewRect(0, y, width, 1))
+
+	guide := r.tree.guideStyle
+	x := 0
+	for _, ch := range treePrefix(row, glyphs) {
+		context.DrawCell(x, y, ch, guide.ForegroundColor, s.BackgroundColor)
+		x++
+	}
+
+	labelX := r.labelX(row)
+	r.cell.style = s
+	r.cell.content = row.node.Label
+	r.cell.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: max(0, width-labelX), Height: 1}))
+	context.PushOffset(geometry.Point{X: labelX, Y: y})
+	r.cell.Paint(context)
+	context.PopOffset()
+}
+
+// treePrefix returns the guides, connector and expander drawn before the
+// label of row
+func treePrefix(row treeRow, glyphs treeGlyphs) string {
+	var b strings.Builder
+	// Roots take no guide column, so guides start from the second level
+	for depth := 1; depth < row.depth; depth++ {
+		if row.lasts[depth] {
+			b.WriteString("  ")
+		} else {
+			b.WriteString(glyphs.pipe)
+		}
+	}
+	if row.depth > 0 {
+		if row.lasts[row.depth] {
+			b.WriteString(glyphs.corner)
+		} else {
+			b.WriteString(glyphs.tee)
+		}
+	}
+
+	switch {
+	case row.loading:
+		b.WriteString(glyphs.loading)
+	case row.expanded:
+		b.WriteString(glyphs.expanded)
+	case row.expandable:
+		b.WriteString(glyphs.collapsed)
+	case row.depth > 0:
+		b.WriteString(glyphs.leaf)
+	default:
+		b.WriteString("  ")
+	}
+	return b.String()
+}
+
+// HandleEvent moves the selection, expands and collapses nodes and searches
+// by typing while the tree has focus, and scrolls
+func (r *RenderTreeView) HandleEvent(event terminal.Event) bool {
+	if r.controller == nil || len(r.rows) == 0 {
+		return false
+	}
+	switch ev := event.(type) {
+	case terminal.KeyEvent:
+		if r.focus == nil || !r.focus.HasFocus() {
+			return false
+		}
+		return r.handleKey(ev)
+	case terminal.MouseEvent:
+		return r.handleMouse(ev)
+	}
+	return false
+}
+
+func (r *RenderTreeView) handleKey(ev terminal.KeyEvent) bool {
+	row := r.rows[r.selected]
+	switch {
+	case ev.Key == tcell.KeyRight:
+		if row.expandable && !row.expanded {
+			return r.toggleRow(r.selected)
+		}
+		if r.selected+1 < len(r.rows) && r.rows[r.selected+1].parent == r.selected {
+			return r.selectRow(r.selected + 1)
+		}
+		return false
+	case ev.Key == tcell.KeyLeft:
+		if row.expanded {
+			return r.toggleRow(r.selected)
+		}
+		return row.parent >= 0 && r.selectRow(row.parent)
+	case ev.Key == tcell.KeyEnter || ev.Key == tcell.KeyRune && ev.Rune == ' ':
+		return row.expandable && r.toggleRow(r.selected)
+	case ev.Key == tcell.KeyRune:
+		return r.typeAhead(ev.Rune)
+	}
+
+	if target, ok := r.selectionTarget(ev.Key); ok {
+		return r.selectRow(target)
+	}
+	return false
+}
+
+// selectionTarget returns the row a key moves the selection to
+func (r *RenderTreeView) selectionTarget(key tcell.Key) (int, bool) {
+	page := max(1, r.size.Height)
+	switch key {
+	case tcell.KeyUp:
+		return r.selected - 1, true
+	case tcell.KeyDown:
+		return r.selected + 1, true
+	case tcell.KeyPgUp:
+		return r.selected - page, true
+	case tcell.KeyPgDn:
+		return r.selected + page, true
+	case tcell.KeyHome:
+		return 0, true
+	case tcell.KeyEnd:
+		return len(r.rows) - 1, true
+	}
+	return 0, false
+}
+
+// handleMouse scrolls on the wheel, and focuses the tree and selects the
+// row under a click, toggling it on its expander
+func (r *RenderTreeView) handleMouse(ev terminal.MouseEvent) bool {
+	if ev.Buttons&(tcell.WheelUp|tcell.WheelDown) != 0 {
+		return scrollForEvent(r.controller, AxisVertical, ev)
+	}
+	if ev.Buttons&tcell.ButtonPrimary == 0 || ev.Motion {
+		return false
+	}
+
+	i := r.controller.Offset() + ev.Position.Y
+	if i < 0 || i >= len(r.rows) || ev.Position.X >= r.size.Width-r.barWidth() {
+		return false
+	}
+	if r.focus != nil {
+		r.focus.RequestFocus()
+	}
+	row := r.rows[i]
+	selected := r.selectRow(i)
+	expander := r.labelX(row) - treeIndent
+	if row.expandable && ev.Position.X >= expander && ev.Position.X < expander+treeIndent {
+		return r.toggleRow(i) || selected
+	}
+	return selected
+}
+
+// typeAhead adds ch to the search and selects the first node from the
+// selection on whose label starts with it. A character that matches
+// nothing starts a new search.
+func (r *RenderTreeView) typeAhead(ch rune) bool {
+	now := time.Now()
+	if now.Sub(r.typedAt) > typeAheadTimeout {
+		r.typed = ""
+	}
+	r.typedAt = now
+
+	r.typed += strings.ToLower(string(ch))
+	// A longer search may still match the selected node
+	i, ok := r.findLabel(r.typed, len(r.typed) > 1)
+	if !ok {
+		r.typed = strings.ToLower(string(ch))
+		i, ok = r.findLabel(r.typed, false)
+	}
+	if ok {
+		r.selectRow(i)
+	}
+	return ok
+}
+
+// findLabel returns the first row, from the selection on and wrapping
+// around, whose label starts with prefix
+func (r *RenderTreeView) findLabel(prefix string, includeSelected bool) (int, bool) {
+	start := r.selected + 1
+	if includeSelected {
+		start = r.selected
+	}
+	for n := 0; n < len(r.rows); n++ {
+		i := (start + n) % len(r.rows)
+		if strings.HasPrefix(strings.ToLower(r.rows[i].node.Label), prefix) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// selectRow moves the selection to row i, clamped to the rows there are,
+// and scrolls it into view
+func (r *RenderTreeView) selectRow(i int) bool {
+	i = max(0, min(i, len(r.rows)-1))
+	if i == r.selected {
+		return false
+	}
+	r.selected = i
+	r.controller.ShowRange(i, 1)
+	node := r.rows[i].node
+	if r.selectKey != nil {
+		r.selectKey(node.Key)
+	}
+	if r.tree.onSelect != nil {
+		r.tree.onSelect(node)
+	}
+	r.changed()
+	return true
+}
+
+// toggleRow expands or collapses row i. Collapsing a node the selection is
+// under selects the node.
+func (r *RenderTreeView) toggleRow(i int) bool {
+	row := r.rows[i]
+	if row.expanded && r.selected > i && r.isUnder(r.selected, i) {
+		r.selectRow(i)
+	}
+	if r.toggle != nil {
+		r.toggle(row.node.Key)
+	}
+	return true
+}
+
+// isUnder reports whether row i is a descendant of row ancestor
+func (r *RenderTreeView) isUnder(i, ancestor int) bool {
+	for i >= 0 {
+		if i = r.rows[i].parent; i == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Selected returns the selected node, if the tree shows any
+func (r *RenderTreeView) Selected() (TreeNode, bool) {
+	if len(r.rows) == 0 {
+		return TreeNode{}, false
+	}
+	return r.rows[r.selected].node, true
+}
+
+func (r *RenderTreeView) changed() {
+	if r.onChange != nil {
+		r.onChange()
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/backend/terminal"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// sampleTree returns a small source tree whose children are all known
+func sampleTree() []TreeNode {
+	return []TreeNode{
+		NewTreeNode("src", "src").WithChildren(
+			NewTreeLeaf("main", "main.go"),
+			NewTreeNode("widget", "widget").WithChildren(
+				NewTreeLeaf("a", "a.go"),
+				NewTreeLeaf("b", "b.go"),
+			),
+		),
+		NewTreeLeaf("readme", "README"),
+	}
+}
+
+// layoutTreeView mounts w, gives the tree focus and lays it out
+func layoutTreeView(w Widget, width, height int) (*BuildOwner, *RenderTreeView) {
+	owner := NewBuildOwner()
+	root := NewElement(w)
+	owner.MountRoot(root)
+	r := root.RenderObject().(*RenderTreeView)
+	r.focus.RequestFocus()
+	owner.BuildScope()
+	r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: width, Height: height}))
+	return owner, r
+}
+
+// pumpTree runs posted work, rebuilds and lays the tree out again
+func pumpTree(owner *BuildOwner, r *RenderTreeView) {
+	owner.FlushPosted()
+	owner.BuildScope()
+	r.Layout(r.Constraints())
+}
+
+// sendKeys dispatches keys to the tree, pumping after each
+func sendKeys(owner *BuildOwner, r *RenderTreeView, keys ...terminal.KeyEvent) {
+	for _, key := range keys {
+		DispatchEvent(r, key)
+		pumpTree(owner, r)
+	}
+}
+
+// treeLines paints the tree and returns its lines without trailing space
+func treeLines(r *RenderTreeView) []string {
+	m := NewMockRenderContext()
+	r.Paint(m)
+	lines := make([]string, r.Size().Height)
+	for y := range lines {
+		lines[y] = strings.TrimRight(screenRow(m, y, r.Size().Width), " ")
+	}
+	return lines
+}
+
+func keyOf(k tcell.Key) terminal.KeyEvent {
+	return terminal.KeyEvent{Key: k}
+}
+
+func runeOf(ch rune) terminal.KeyEvent {
+	return terminal.KeyEvent{Key: tcell.KeyRune, Rune: ch}
+}
+
+func TestTreeView_Guides(t *testing.T) {
+	owner, r := layoutTreeView(NewTreeView(sampleTree(), nil), 20, 6)
+	sendKeys(owner, r, keyOf(tcell.KeyRight), keyOf(tcell.KeyDown), keyOf(tcell.KeyDown), keyOf(tcell.KeyRight))
+
+	assert.Equal(t, []string{
+		"▾ src",
+		"├── main.go",
+		"└─▾ widget",
+		"  ├── a.go",
+		"  └── b.go",
+		"  README",
+	}, treeLines(r))
+}
+
+func TestTreeView_LazyChildren(t *testing.T) {
+	t.Run("synchronous", func(t *testing.T) {
+		var loads []string
+		load := func(node TreeNode, done func([]TreeNode)) {
+			loads = append(loads, node.Key)
+			done([]TreeNode{NewTreeLeaf(node.Key+"/x", "x")})
+		}
+		roots := []TreeNode{NewTreeNode("a", "a"), NewTreeNode("b", "b")}
+		owner, r := layoutTreeView(NewTreeView(roots, load), 20, 5)
+		assert.Empty(t, loads, "nothing loads before expanding")
+
+		DispatchEvent(r, keyOf(tcell.KeyRight))
+		owner.BuildScope()
+		r.Layout(r.Constraints())
+		assert.Equal(t, []string{"⋯ a", "▸ b"}, treeLines(r))
+
+		pumpTree(owner, r)
+		assert.Equal(t, []string{"▾ a", "└── x", "▸ b"}, treeLines(r))
+		assert.Equal(t, []string{"a"}, loads)
+
+		// Children stay loaded while collapsed
+		sendKeys(owner, r, keyOf(tcell.KeyLeft), keyOf(tcell.KeyRight))
+		assert.Equal(t, []string{"a"}, loads)
+	})
+
+	t.Run("from another goroutine", func(t *testing.T) {
+		loaded := make(chan struct{})
+		load := func(node TreeNode, done func([]TreeNode)) {
+			go func() {
+				done(nil)
+				close(loaded)
+			}()
+		}
+		owner, r := layoutTreeView(NewTreeView([]TreeNode{NewTreeNode("a", "a")}, load), 20, 2)
+		sendKeys(owner, r, keyOf(tcell.KeyRight))
+		<-loaded
+		pumpTree(owner, r)
+
+		assert.Equal(t, []string{"  a"}, treeLines(r)[:1], "a node without children shows no expander")
+	})
+}
+
+// treeHost shows a tree of the roots held by its state
+type treeHost struct {
+	BaseWidget
+	state *treeHostState
+}
+
+func (w *treeHost) CreateState() State {
+	w.state = &treeHostState{roots: sampleTree()}
+	return w.state
+}
+
+type treeHostState struct {
+	BaseState
+	roots []TreeNode
+}
+
+func (s *treeHostState) Build(context BuildContext) Widget {
+	return NewTreeView(s.roots, nil)
+}
+
+func TestTreeView_KeepsExpansionAcrossRebuilds(t *testing.T) {
+	host := &treeHost{}
+	owner, r := layoutTreeView(host, 20, 6)
+	sendKeys(owner, r, keyOf(tcell.KeyRight), keyOf(tcell.KeyEnd))
+
+	host.state.SetState(func() {
+		host.state.roots = append([]TreeNode{NewTreeLeaf("new", "NEW")}, sampleTree()...)
+	})
+	pumpTree(owner, r)
+
+	assert.Equal(t, []string{"  NEW", "▾ src", "├── main.go", "└─▸ widget", "  README"}, treeLines(r)[:5])
+	node, _ := r.Selected()
+	assert.Equal(t, "readme", node.Key, "the selection follows its node")
+}
+
+func TestTreeView_Keys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []terminal.KeyEvent
+		selected string
+		rows     int
+	}{
+		{"right expands", []terminal.KeyEvent{keyOf(tcell.KeyRight)}, "src", 4},
+		{"right again enters", []terminal.KeyEvent{keyOf(tcell.KeyRight), keyOf(tcell.KeyRight)}, "main", 4},
+		{"left goes to the parent", []terminal.KeyEvent{keyOf(tcell.KeyRight), keyOf(tcell.KeyRight), keyOf(tcell.KeyLeft)}, "src", 4},
+		{"left collapses", []terminal.KeyEvent{keyOf(tcell.KeyRight), keyOf(tcell.KeyLeft)}, "src", 2},
+		{"enter toggles", []terminal.KeyEvent{keyOf(tcell.KeyEnter)}, "src", 4},
+		{"space on a leaf", []terminal.KeyEvent{keyOf(tcell.KeyEnd), runeOf(' ')}, "readme", 2},
+		{"up from a nested node", []terminal.KeyEvent{keyOf(tcell.KeyRight), keyOf(tcell.KeyEnd), keyOf(tcell.KeyUp), keyOf(tcell.KeyRight), keyOf(tcell.KeyEnd), keyOf(tcell.KeyUp)}, "b", 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, r := layoutTreeView(NewTreeView(sampleTree(), nil), 20, 10)
+			sendKeys(owner, r, tt.keys...)
+
+			node, _ := r.Selected()
+			assert.Equal(t, tt.selected, node.Key)
+			assert.Len(t, r.rows, tt.rows)
+		})
+	}
+
+	t.Run("collapsing an ancestor of the selection", func(t *testing.T) {
+		owner, r := layoutTreeView(NewTreeView(sampleTree(), nil), 20, 10)
+		sendKeys(owner, r, keyOf(tcell.KeyRight), keyOf(tcell.KeyEnd))
+		sendKeys(owner, r, keyOf(tcell.KeyUp), keyOf(tcell.KeyRight), keyOf(tcell.KeyRight))
+
+		// Clicking the expander of src collapses it
+		DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: 0, Y: 0}})
+		pumpTree(owner, r)
+
+		node, _ := r.Selected()
+		assert.Equal(t, "src", node.Key)
+		assert.Len(t, r.rows, 2)
+	})
+}
+
+func TestTreeView_Mouse(t *testing.T) {
+	var selected []string
+	tree := NewTreeView(sampleTree(), nil).WithOnSelect(func(node TreeNode) { selected = append(selected, node.Key) })
+	owner, r := layoutTreeView(tree, 20, 10)
+	click := func(x, y int) {
+		DispatchEvent(r, terminal.MouseEvent{Buttons: tcell.ButtonPrimary, Position: geometry.Point{X: x, Y: y}})
+		pumpTree(owner, r)
+	}
+
+	click(3, 0)
+	assert.Len(t, r.rows, 2, "clicking a label only selects")
+	click(0, 0)
+	assert.Len(t, r.rows, 4, "clicking the expander toggles")
+	click(3, 2)
+	assert.Len(t, r.rows, 6, "nested expanders sit after the connector")
+	click(6, 4)
+
+	assert.Equal(t, []string{"widget", "b"}, selected)
+}
+
+func TestTreeView_TypeAhead(t *testing.T) {
+	roots := []TreeNode{
+		NewTreeLeaf("1", "alpha"),
+		NewTreeLeaf("2", "beta"),
+		NewTreeLeaf("3", "Bravo"),
+		NewTreeLeaf("4", "charlie"),
+	}
+	tests := []struct {
+		name  string
+		typed string
+		want  string
+	}{
+		{"first letter", "b", "2"},
+		{"ignores case", "br", "3"},
+		{"same letter cycles", "bb", "3"},
+		{"no match restarts", "bc", "4"},
+		{"nothing matches", "z", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner, r := layoutTreeView(NewTreeView(roots, nil), 20, 4)
+			for _, ch := range tt.typed {
+				sendKeys(owner, r, runeOf(ch))
+			}
+
+			node, _ := r.Selected()
+			assert.Equal(t, tt.want, node.Key)
+		})
+	}
+}
+
+func TestTreeView_Filter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"B.", []string{"▾ src", "└─▾ widget", "  └── b.go"}},
+		{"read", []string{"  README"}},
+		{"nothing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, r := layoutTreeView(NewTreeView(sampleTree(), nil).WithFilter(tt.query), 20, 3)
+			var lines []string
+			for _, line := range treeLines(r) {
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			assert.Equal(t, tt.want, lines)
+		})
+	}
+}
+
+func TestTreeView_Scrolls(t *testing.T) {
+	var roots []TreeNode
+	for _, label := range []string{"a", "b", "c", "d", "e", "f"} {
+		roots = append(roots, NewTreeLeaf(label, label))
+	}
+	c := NewScrollController()
+	owner, r := layoutTreeView(NewTreeView(roots, nil).WithController(c), 10, 3)
+	sendKeys(owner, r, keyOf(tcell.KeyEnd))
+
+	assert.Equal(t, 3, c.Offset())
+	assert.Equal(t, []string{"  d░", "  e░", "  f█"}, treeLines(r))
+}
+
+func TestTreeView_KeysNeedFocus(t *testing.T) {
+	text := NewTextEditingController("")
+	input, treeFocus := NewFocusNode(), NewFocusNode()
+	var selected []string
+	tree := mountControls(NewColumn(
+		NewTextInput().WithController(text).WithFocusNode(input),
+		NewButton("OK", func() {}),
+		NewExpanded(NewTreeView([]TreeNode{NewTreeLeaf("apple", "apple"), NewTreeLeaf("hello", "hello")}, nil).
+			WithFocusNode(treeFocus).
+			WithOnSelect(func(node TreeNode) { selected = append(selected, node.Key) })),
+	), 20, 8)
+
+	input.RequestFocus()
+	tree.pump()
+	tree.send(runeOf('h'), runeOf('a'), keyOf(tcell.KeyDown))
+	assert.Equal(t, "ha", text.Text(), "the focused input gets the keys")
+	assert.Empty(t, selected)
+
+	// Even offered to every handler, the unfocused tree leaves keys alone
+	assert.False(t, DispatchEvent(tree.root.RenderObject(), keyOf(tcell.KeyDown)))
+
+	treeFocus.RequestFocus()
+	tree.pump()
+	tree.send(runeOf('h'))
+	assert.Equal(t, "ha", text.Text())
+	assert.Equal(t, []string{"hello"}, selected)
+}
@@ -129,7 +129,9 @@ type StatefulWidget interface {
 
 // State represents the mutable state of a StatefulWidget
 type State interface {
-	// Lifecycle
+	// Lifecycle. The element calls InitState once it has attached the
+	// state with MountState, so Widget, Element and Context are set, and
+	// before the first Build. Dispose is called when the element unmounts.
 	InitState()
 	Dispose()
 
@@ -0,0 +1,291 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"math"
+
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// Wrap lays out its children in runs along an axis, starting a new run
+// whenever the next child would not fit in the space left. Runs are stacked
+// across the axis, so a horizontal wrap flows children onto the next line.
+type Wrap struct {
+	BaseWidget
+	direction          Axis
+	alignment          MainAxisAlignment
+	runAlignment       MainAxisAlignment
+	crossAxisAlignment CrossAxisAlignment
+	spacing            int
+	runSpacing         int
+	children           []Widget
+}
+
+// NewWrap creates a wrap that flows children from left to right and then
+// from top to bottom
+func NewWrap(children ...Widget) *Wrap {
+	return &Wrap{
+		BaseWidget: BaseWidget{
+			style: NewWidgetStyle(),
+		},
+		direction: AxisHorizontal,
+		children:  children,
+	}
+}
+
+func (w *Wrap) AppendChild(child Widget) {
+	w.children = append(w.children, child)
+}
+
+// GetChildren returns the child widgets of the wrap
+func (w *Wrap) GetChildren() []Widget {
+	return w.children
+}
+
+// WithDirection sets the axis children are placed along within a run
+func (w *Wrap) WithDirection(direction Axis) *Wrap {
+	w.direction = direction
+	return w
+}
+
+// WithAlignment sets how children are placed within each run
+func (w *Wrap) WithAlignment(alignment MainAxisAlignment) *Wrap {
+	w.alignment = alignment
+	return w
+}
+
+// WithRunAlignment sets how the runs are placed across the axis
+func (w *Wrap) WithRunAlignment(alignment MainAxisAlignment) *Wrap {
+	w.runAlignment = alignment
+	return w
+}
+
+// WithCrossAxisAlignment sets how children are aligned across the axis
+// within their run. Stretch is treated as start.
+func (w *Wrap) WithCrossAxisAlignment(alignment CrossAxisAlignment) *Wrap {
+	w.crossAxisAlignment = alignment
+	return w
+}
+
+// WithSpacing sets the number of cells between children in a run
+func (w *Wrap) WithSpacing(spacing int) *Wrap {
+	w.spacing = max(0, spacing)
+	return w
+}
+
+// WithRunSpacing sets the number of cells between runs
+func (w *Wrap) WithRunSpacing(spacing int) *Wrap {
+	w.runSpacing = max(0, spacing)
+	return w
+}
+
+// WithStyle sets the style of the wrap
+func (w *Wrap) WithStyle(style WidgetStyle) *Wrap {
+	w.style = style
+	return w
+}
+
+func (w *Wrap) Build(context BuildContext) Widget {
+	return w
+}
+
+// CreateRenderObject creates the wrap's render object. The render objects of
+// the children are attached by the wrap's element.
+func (w *Wrap) CreateRenderObject() RenderObject {
+	r := NewRenderWrap(w.direction)
+	w.UpdateRenderObject(r)
+	return r
+}
+
+func (w *Wrap) UpdateRenderObject(renderObject RenderObject) {
+	if r, ok := renderObject.(*RenderWrap); ok {
+		r.WithStyle(w.style)
+		r.direction = w.direction
+		r.alignment = w.alignment
+		r.runAlignment = w.runAlignment
+		r.crossAxisAlignment = w.crossAxisAlignment
+		r.spacing = w.spacing
+		r.runSpacing = w.runSpacing
+	}
+}
+
+// wrapRun is a line of children laid out along the main axis
+type wrapRun struct {
+	first int
+	sizes []geometry.Size
+	main  int
+	cross int
+}
+
+// RenderWrap lays out its children in runs inside the box model of its style
+type RenderWrap struct {
+	BaseRenderBox
+	direction          Axis
+	alignment          MainAxisAlignment
+	runAlignment       MainAxisAlignment
+	crossAxisAlignment CrossAxisAlignment
+	spacing            int
+	runSpacing         int
+
+	// runs is the number of runs after the last layout
+	runs int
+}
+
+// NewRenderWrap creates a render wrap that runs along direction
+func NewRenderWrap(direction Axis) *RenderWrap {
+	return &RenderWrap{
+		BaseRenderBox: *NewBaseRenderBox(),
+		direction:     direction,
+	}
+}
+
+// Runs returns the number of runs after the last layout
+func (r *RenderWrap) Runs() int {
+	return r.runs
+}
+
+func (r *RenderWrap) Layout(constraints Constraints) geometry.Size {
+	r.constraints = constraints
+	content := r.layoutWrap(r.contentConstraints(constraints))
+	r.size = constraints.Constrain(r.outerSize(content))
+	return r.size
+}
+
+// layoutWrap breaks the children into runs and positions them within the
+// content constraints. The wrap fills a bounded main axis and is as tall
+// as its runs across it.
+func (r *RenderWrap) layoutWrap(constraints Constraints) geometry.Size {
+	maxMain := r.direction.main(constraints.MaxSize)
+	runs := r.buildRuns(maxMain)
+	r.runs = len(runs)
+
+	mainSize := maxMain
+	if maxMain >= math.MaxInt32 {
+		mainSize = 0
+		for _, run := range runs {
+			mainSize = max(mainSize, run.main)
+		}
+	}
+
+	crossUsed := r.runSpacing * max(0, len(runs)-1)
+	for _, run := range runs {
+		crossUsed += run.cross
+	}
+	crossSize := max(crossUsed, r.direction.cross(constraints.MinSize))
+	crossSize = min(crossSize, r.direction.cross(constraints.MaxSize))
+
+	r.position(runs, mainSize, max(0, crossSize-crossUsed))
+	return r.direction.size(mainSize, crossSize)
+}
+
+// buildRuns lays out every child and groups them into runs no longer than
+// maxMain. A child too long for any run gets a run of its own.
+func (r *RenderWrap) buildRuns(maxMain int) []wrapRun {
+	childConstraints := Constraints{
+		MaxSize: r.direction.size(maxMain, math.MaxInt32),
+	}
+
+	var runs []wrapRun
+	var run wrapRun
+	for i, child := range r.children {
+		size := LayoutIfNeeded(child, childConstraints)
+		childMain := r.direction.main(size)
+
+		if len(run.sizes) > 0 && run.main+r.spacing+childMain > maxMain {
+			runs = append(runs, run)
+			run = wrapRun{first: i}
+		}
+		if len(run.sizes) > 0 {
+			run.main += r.spacing
+		}
+		run.sizes = append(run.sizes, size)
+		run.main += childMain
+		run.cross = max(run.cross, r.direction.cross(size))
+	}
+	if len(run.sizes) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// position places the runs across the axis and the children within them
+func (r *RenderWrap) position(runs []wrapRun, mainSize, freeCross int) {
+	origin := r.ContentRect().Min
+
+	runLeading, runBetween := r.runAlignment.distribute(freeCross, len(runs))
+	cross := runLeading
+	for _, run := range runs {
+		leading, between := r.alignment.distribute(max(0, mainSize-run.main), len(run.sizes))
+		main := leading
+		for i, size := range run.sizes {
+			childCross := cross + r.crossAxisAlignment.offset(run.cross-r.direction.cross(size))
+			setChildOffset(r.children[run.first+i], origin.Add(r.direction.point(main, childCross)))
+			main += r.direction.main(size) + r.spacing + between
+		}
+		cross += run.cross + r.runSpacing + runBetween
+	}
+}
+
+// wrapIntrinsic measures the wrap along axis given the extent across it.
+// Along the main axis the minimum is the longest child and the maximum puts
+// every child in one run. Across it, runs are formed within the main extent
+// from the children's maximum intrinsic extents and their sizes add up.
+func (r *RenderWrap) wrapIntrinsic(axis Axis, extent int, minimum bool) int {
+	measure := intrinsicMeasure(axis, minimum)
+	return r.intrinsicWith(axis, extent, func(extent int) int {
+		if axis != r.direction {
+			return r.runsExtent(extent, measure)
+		}
+		if minimum {
+			return maxIntrinsic(r.children, math.MaxInt32, measure)
+		}
+		total := r.spacing * max(0, len(r.children)-1)
+		for _, child := range r.children {
+			total += measure(child, math.MaxInt32)
+		}
+		return total
+	})
+}
+
+// runsExtent returns the extent across the main axis of the runs formed
+// within maxMain, measuring each child across the axis with measure
+func (r *RenderWrap) runsExtent(maxMain int, measure func(RenderObject, int) int) int {
+	mainMeasure := intrinsicMeasure(r.direction, false)
+
+	// runMain is negative until the first run starts
+	total, runMain, runCross := 0, -1, 0
+	for _, child := range r.children {
+		childMain := min(mainMeasure(child, math.MaxInt32), maxMain)
+		switch {
+		case runMain < 0:
+			runMain = childMain
+		case runMain+r.spacing+childMain > maxMain:
+			total += runCross + r.runSpacing
+			runMain, runCross = childMain, 0
+		default:
+			runMain += r.spacing + childMain
+		}
+		runCross = max(runCross, measure(child, childMain))
+	}
+	return total + runCross
+}
+
+func (r *RenderWrap) MinIntrinsicWidth(height int) int {
+	return r.wrapIntrinsic(AxisHorizontal, height, true)
+}
+
+func (r *RenderWrap) MaxIntrinsicWidth(height int) int {
+	return r.wrapIntrinsic(AxisHorizontal, height, false)
+}
+
+func (r *RenderWrap) MinIntrinsicHeight(width int) int {
+	return r.wrapIntrinsic(AxisVertical, width, true)
+}
+
+func (r *RenderWrap) MaxIntrinsicHeight(width int) int {
+	return r.wrapIntrinsic(AxisVertical, width, false)
+}
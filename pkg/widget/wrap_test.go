@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Christopher Watson
+//
+// This software is released under the MIT License.
+// https://opensource.org/licenses/MIT
+
+package widget
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/watzon/tide/pkg/core/geometry"
+)
+
+// layoutWrap mounts a wrap widget and lays it out with constraints
+func layoutWrap(w *Wrap, constraints Constraints) *RenderWrap {
+	elem := NewElement(w)
+	elem.Mount(nil)
+	r := elem.RenderObject().(*RenderWrap)
+	r.Layout(constraints)
+	return r
+}
+
+// tags returns the labels used by the wrap tests
+func tags() []Widget {
+	return []Widget{NewText("aaa"), NewText("bb"), NewText("cccc"), NewText("d")}
+}
+
+func TestWrap_Runs(t *testing.T) {
+	t.Run("children flow onto the next run", func(t *testing.T) {
+		w := NewWrap(tags()...).WithSpacing(1)
+		r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 10}))
+
+		assert.Equal(t, 2, r.Runs())
+		assert.Equal(t, []geometry.Point{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 1}, {X: 5, Y: 1}}, childOffsets(r))
+		assert.Equal(t, geometry.Size{Width: 8, Height: 2}, r.Size())
+	})
+
+	t.Run("run spacing separates runs", func(t *testing.T) {
+		w := NewWrap(tags()...).WithSpacing(1).WithRunSpacing(1)
+		r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 10}))
+
+		assert.Equal(t, 2, childOffsets(r)[2].Y)
+		assert.Equal(t, 3, r.Size().Height)
+	})
+
+	t.Run("oversized child gets a run of its own", func(t *testing.T) {
+		w := NewWrap(NewText("a"), NewText("bbbbbbbbbbbb"), NewText("c"))
+		r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 10}))
+
+		assert.Equal(t, 3, r.Runs())
+		assert.Equal(t, 8, r.children[1].Size().Width)
+	})
+
+	t.Run("vertical runs become columns", func(t *testing.T) {
+		w := NewWrap(NewText("a"), NewText("b"), NewText("c")).WithDirection(AxisVertical)
+		r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 10, Height: 2}))
+
+		assert.Equal(t, []geometry.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 0}}, childOffsets(r))
+	})
+
+	t.Run("runs reflow when the width changes", func(t *testing.T) {
+		w := NewWrap(tags()...).WithSpacing(1)
+		r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 20, Height: 10}))
+		assert.Equal(t, 1, r.Runs())
+
+		r.Layout(NewConstraints(geometry.Size{}, geometry.Size{Width: 4, Height: 10}))
+		assert.Equal(t, 4, r.Runs())
+	})
+}
+
+func TestWrap_Alignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment MainAxisAlignment
+		want      []int
+	}{
+		{"start", MainAxisStart, []int{0, 4, 0, 5}},
+		{"center", MainAxisCenter, []int{1, 5, 1, 6}},
+		{"end", MainAxisEnd, []int{2, 6, 2, 7}},
+		{"space between", MainAxisSpaceBetween, []int{0, 6, 0, 7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWrap(tags()...).WithSpacing(1).WithAlignment(tt.alignment)
+			r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 10}))
+
+			offsets := childOffsets(r)
+			xs := make([]int, len(offsets))
+			for i, offset := range offsets {
+				xs[i] = offset.X
+			}
+			assert.Equal(t, tt.want, xs)
+		})
+	}
+}
+
+func TestWrap_RunAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment MainAxisAlignment
+		want      []int
+	}{
+		{"start", MainAxisStart, []int{0, 1}},
+		{"center", MainAxisCenter, []int{2, 3}},
+		{"end", MainAxisEnd, []int{4, 5}},
+		{"space between", MainAxisSpaceBetween, []int{0, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWrap(tags()...).WithSpacing(1).WithRunAlignment(tt.alignment)
+			r := layoutWrap(w, NewConstraints(geometry.Size{Height: 6}, geometry.Size{Width: 8, Height: 6}))
+
+			assert.Equal(t, tt.want, []int{childOffsets(r)[0].Y, childOffsets(r)[2].Y})
+		})
+	}
+}
+
+func TestWrap_CrossAxisAlignment(t *testing.T) {
+	tests := []struct {
+		name      string
+		alignment CrossAxisAlignment
+		wantY     int
+	}{
+		{"start", CrossAxisStart, 0},
+		{"center", CrossAxisCenter, 1},
+		{"end", CrossAxisEnd, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWrap(NewText("a"), NewText("b\nb\nb")).WithCrossAxisAlignment(tt.alignment)
+			r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 8, Height: 10}))
+
+			assert.Equal(t, tt.wantY, childOffsets(r)[0].Y)
+			assert.Equal(t, 0, childOffsets(r)[1].Y)
+		})
+	}
+}
+
+func TestWrap_Paint(t *testing.T) {
+	w := NewWrap(NewText("ab"), NewText("cd")).WithSpacing(1)
+	r := layoutWrap(w, NewConstraints(geometry.Size{}, geometry.Size{Width: 4, Height: 10}))
+
+	ctx := NewMockRenderContext()
+	r.Paint(ctx)
+
+	assert.Equal(t, 'a', ctx.cells[geometry.Point{X: 0, Y: 0}].Rune)
+	assert.Equal(t, 'c', ctx.cells[geometry.Point{X: 0, Y: 1}].Rune)
+	assert.Equal(t, 'd', ctx.cells[geometry.Point{X: 1, Y: 1}].Rune)
+}